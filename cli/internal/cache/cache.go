@@ -0,0 +1,191 @@
+// Package cache is the single-writer-safe store behind `credlink cache`:
+// every manifest/verify entry lives under a content-addressed path sharded
+// into 256 subdirectories by the first byte of its hash (the same scheme
+// rogpeppe/go-internal/cache uses) so no one directory ever holds millions
+// of files, and every read/write is guarded by a lockedfile-style advisory
+// lock so concurrent `credlink` processes don't tear each other's entries.
+//
+// Two lock tiers are used. Each entry has its own lock, taken for the
+// duration of a single Get or Put. A second, cache-wide "cache.lock" is
+// held shared by Get and Put (and by Walk's callers, since Walk takes no
+// lock itself) and exclusive by whole-cache
+// housekeeping (prune, clear) - so a prune can't remove an entry out from
+// under a concurrent reader, while ordinary reads and writes don't block
+// each other.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Entry describes one cached object: the (actionID, key) pair it was
+// stored under, where its content lives on disk, and basic stat info.
+type Entry struct {
+	ActionID string    `json:"action_id"`
+	Key      string    `json:"key"`
+	Path     string    `json:"-"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mod_time"`
+}
+
+// entryMeta is the small sidecar Put writes next to an entry's content so
+// Walk can recover the (actionID, key) a shard's content-addressed
+// filename no longer carries - the same sidecar-file device
+// cacheintegrity.go uses for its ".bit" hashes.
+type entryMeta struct {
+	ActionID string `json:"action_id"`
+	Key      string `json:"key"`
+}
+
+// LockFileName is the cache-wide lock file's name, exported so other
+// packages walking a cache directory directly (doctor, cmd's own
+// filepath.Walk-based listers) know to skip it.
+const LockFileName = "cache.lock"
+
+const metaSuffix = ".meta"
+
+// Cache is a handle on a cache directory. It is safe for concurrent use
+// from multiple goroutines and multiple processes.
+type Cache struct {
+	dir string
+}
+
+// Open returns a Cache rooted at dir, creating dir if it doesn't exist.
+func Open(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cache: open %s: %w", dir, err)
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// Dir returns the cache's root directory.
+func (c *Cache) Dir() string { return c.dir }
+
+// RLock acquires the cache-wide shared lock, held by Get and Put (and by
+// callers around Walk) so they can't observe a cache mid-prune. The caller must call the
+// returned unlock func to release it.
+func (c *Cache) RLock() (unlock func() error, err error) {
+	return c.lockWhole(false)
+}
+
+// Lock acquires the cache-wide exclusive lock, held by whole-cache
+// housekeeping (prune, clear) so it doesn't remove an entry a concurrent
+// reader is in the middle of returning. The caller must call the returned
+// unlock func to release it.
+func (c *Cache) Lock() (unlock func() error, err error) {
+	return c.lockWhole(true)
+}
+
+func (c *Cache) lockWhole(exclusive bool) (func() error, error) {
+	lf, err := lockFile(filepath.Join(c.dir, LockFileName), exclusive)
+	if err != nil {
+		return nil, fmt.Errorf("cache: lock %s: %w", c.dir, err)
+	}
+	return lf.Close, nil
+}
+
+// TryRLock attempts the cache-wide shared lock without blocking. ok is
+// false when an exclusive holder (a concurrent prune or clear) is stuck
+// holding it; doctor uses this to flag a wedged writer instead of hanging.
+// The caller must call unlock when ok is true.
+func (c *Cache) TryRLock() (ok bool, unlock func() error, err error) {
+	f, err := os.OpenFile(filepath.Join(c.dir, LockFileName), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return false, nil, fmt.Errorf("cache: open lock file: %w", err)
+	}
+	acquired, err := tryFlock(f, false)
+	if err != nil {
+		f.Close()
+		return false, nil, fmt.Errorf("cache: try-lock %s: %w", c.dir, err)
+	}
+	if !acquired {
+		f.Close()
+		return false, nil, nil
+	}
+	lf := &lockedFile{f: f}
+	return true, lf.Close, nil
+}
+
+// shardPath returns the content path and meta sidecar path for
+// (actionID, key), sharded into a 2-hex-digit (256-way) subdirectory by
+// the first byte of sha256(actionID, key).
+func (c *Cache) shardPath(actionID, key string) (content, meta string) {
+	sum := sha256.Sum256([]byte(actionID + "\x00" + key))
+	id := hex.EncodeToString(sum[:])
+	content = filepath.Join(c.dir, id[:2], id)
+	return content, content + metaSuffix
+}
+
+// Get opens the cached content for (actionID, key). Callers must close
+// the returned ReadCloser.
+func (c *Cache) Get(actionID, key string) (io.ReadCloser, Entry, error) {
+	unlock, err := c.RLock()
+	if err != nil {
+		return nil, Entry{}, err
+	}
+	defer unlock()
+
+	contentPath, _ := c.shardPath(actionID, key)
+	lf, err := lockFile(contentPath, false)
+	if err != nil {
+		return nil, Entry{}, fmt.Errorf("cache: lock entry: %w", err)
+	}
+
+	info, err := lf.f.Stat()
+	if err != nil {
+		lf.Close()
+		return nil, Entry{}, fmt.Errorf("cache: stat entry: %w", err)
+	}
+
+	entry := Entry{ActionID: actionID, Key: key, Path: contentPath, Size: info.Size(), ModTime: info.ModTime()}
+	return lf, entry, nil
+}
+
+// Put stores r's content under (actionID, key), overwriting any existing
+// entry, and returns the resulting Entry.
+func (c *Cache) Put(actionID, key string, r io.Reader) (Entry, error) {
+	unlock, err := c.RLock()
+	if err != nil {
+		return Entry{}, err
+	}
+	defer unlock()
+
+	contentPath, metaPath := c.shardPath(actionID, key)
+	if err := os.MkdirAll(filepath.Dir(contentPath), 0o755); err != nil {
+		return Entry{}, fmt.Errorf("cache: create shard dir: %w", err)
+	}
+
+	lf, err := lockFile(contentPath, true)
+	if err != nil {
+		return Entry{}, fmt.Errorf("cache: lock entry: %w", err)
+	}
+	defer lf.Close()
+
+	if err := lf.f.Truncate(0); err != nil {
+		return Entry{}, fmt.Errorf("cache: truncate entry: %w", err)
+	}
+	if _, err := lf.f.Seek(0, io.SeekStart); err != nil {
+		return Entry{}, fmt.Errorf("cache: seek entry: %w", err)
+	}
+	size, err := io.Copy(lf.f, r)
+	if err != nil {
+		return Entry{}, fmt.Errorf("cache: write entry: %w", err)
+	}
+
+	metaBytes, err := json.Marshal(entryMeta{ActionID: actionID, Key: key})
+	if err != nil {
+		return Entry{}, fmt.Errorf("cache: marshal entry meta: %w", err)
+	}
+	if err := os.WriteFile(metaPath, metaBytes, 0o644); err != nil {
+		return Entry{}, fmt.Errorf("cache: write entry meta: %w", err)
+	}
+
+	return Entry{ActionID: actionID, Key: key, Path: contentPath, Size: size, ModTime: time.Now()}, nil
+}
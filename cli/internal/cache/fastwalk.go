@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// Walk parallel-walks the cache directory, fanning directory reads out to
+// jobs workers (jobs<=0 means runtime.GOMAXPROCS(0)) instead of the single
+// goroutine filepath.Walk uses - the bottleneck once a cache grows into
+// the hundreds of thousands of entries a long-running CI cache reaches.
+// Each worker reads a directory with a single os.ReadDir call (not an
+// Lstat per child) and feeds any subdirectories it finds back onto a
+// shared work-stealing queue, seeded from the cache root's immediate
+// subdirectories - the up-to-256 shard directories Put writes into.
+//
+// fn is called once per entry with its path relative to the cache root;
+// returning fs.SkipDir from fn skips descending into that entry (only
+// meaningful when the entry is a directory) without stopping the walk,
+// any other non-nil error stops the walk and is returned from Walk once
+// every in-flight worker has wound down.
+//
+// Callers that need Walk to observe a consistent snapshot against
+// concurrent prune/clear should hold RLock (or Lock) for the duration;
+// Walk itself takes no lock, since it's commonly called from code that
+// already holds one.
+func (c *Cache) Walk(ctx context.Context, jobs int, fn func(relPath string, d fs.DirEntry) error) error {
+	return fastWalk(ctx, c.dir, jobs, fn)
+}
+
+// walkQueue is a concurrent-safe LIFO of pending directories to read, plus
+// a count of workers currently processing one - used to tell "empty
+// because there's truly nothing left" from "empty for now, but a worker
+// in flight may still push more".
+type walkQueue struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	items  []string
+	active int
+}
+
+func newWalkQueue() *walkQueue {
+	q := &walkQueue{}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *walkQueue) push(relDir string) {
+	q.mu.Lock()
+	q.items = append(q.items, relDir)
+	q.cond.Signal()
+	q.mu.Unlock()
+}
+
+// pop blocks until a directory is available, or returns ok=false once the
+// queue is empty and no worker is still in flight to refill it.
+func (q *walkQueue) pop() (relDir string, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) == 0 {
+		if q.active == 0 {
+			q.cond.Broadcast()
+			return "", false
+		}
+		q.cond.Wait()
+	}
+	n := len(q.items) - 1
+	item := q.items[n]
+	q.items = q.items[:n]
+	q.active++
+	return item, true
+}
+
+// done marks the caller's previously-popped directory as finished being
+// read (including any subdirectories it discovered having been pushed
+// back already).
+func (q *walkQueue) done() {
+	q.mu.Lock()
+	q.active--
+	if q.active == 0 {
+		q.cond.Broadcast()
+	}
+	q.mu.Unlock()
+}
+
+func fastWalk(ctx context.Context, root string, jobs int, fn func(relPath string, d fs.DirEntry) error) error {
+	if jobs <= 0 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+
+	queue := newWalkQueue()
+	queue.push("")
+
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	recordErr := func(err error) {
+		if err == nil {
+			return
+		}
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+		}
+		mu.Unlock()
+	}
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+
+	visit := func(relDir string) {
+		absDir := root
+		if relDir != "" {
+			absDir = filepath.Join(root, relDir)
+		}
+
+		entries, err := os.ReadDir(absDir)
+		if err != nil {
+			recordErr(err)
+			return
+		}
+
+		for _, entry := range entries {
+			relPath := entry.Name()
+			if relDir != "" {
+				relPath = filepath.Join(relDir, entry.Name())
+			}
+
+			err := fn(relPath, entry)
+			if err == fs.SkipDir {
+				continue
+			}
+			if err != nil {
+				recordErr(err)
+				return
+			}
+			if entry.IsDir() {
+				queue.push(relPath)
+			}
+		}
+	}
+
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				relDir, ok := queue.pop()
+				if !ok {
+					return
+				}
+				if ctx.Err() == nil && !failed() {
+					visit(relDir)
+				}
+				queue.done()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
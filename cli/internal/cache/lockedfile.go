@@ -0,0 +1,33 @@
+package cache
+
+import "os"
+
+// lockedFile is an open file held under an advisory lock (flock/fcntl on
+// POSIX, LockFileEx on Windows - see lockedfile_unix.go/lockedfile_windows.go).
+type lockedFile struct {
+	f *os.File
+}
+
+// lockFile opens path (creating it if necessary) and blocks until it can
+// take a shared (exclusive=false) or exclusive lock on it.
+func lockFile(path string, exclusive bool) (*lockedFile, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := flock(f, exclusive); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &lockedFile{f: f}, nil
+}
+
+// Read implements io.Reader over the locked file's content, for Get's
+// returned ReadCloser.
+func (l *lockedFile) Read(p []byte) (int, error) { return l.f.Read(p) }
+
+// Close releases the lock and closes the underlying file.
+func (l *lockedFile) Close() error {
+	funlock(l.f)
+	return l.f.Close()
+}
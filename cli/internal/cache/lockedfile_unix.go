@@ -0,0 +1,39 @@
+//go:build !windows
+
+package cache
+
+import (
+	"os"
+	"syscall"
+)
+
+// flock takes a POSIX advisory lock on f via flock(2), blocking until it's
+// granted. exclusive selects LOCK_EX over LOCK_SH; funlock releases it with
+// LOCK_UN regardless.
+func flock(f *os.File, exclusive bool) error {
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	return syscall.Flock(int(f.Fd()), how)
+}
+
+func funlock(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
+
+// tryFlock is flock's non-blocking counterpart: ok is false (with a nil
+// error) when the lock is held by someone else instead of blocking for it.
+func tryFlock(f *os.File, exclusive bool) (ok bool, err error) {
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	if err := syscall.Flock(int(f.Fd()), how|syscall.LOCK_NB); err != nil {
+		if err == syscall.EWOULDBLOCK {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
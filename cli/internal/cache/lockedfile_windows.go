@@ -0,0 +1,42 @@
+//go:build windows
+
+package cache
+
+import (
+	"os"
+	"syscall"
+)
+
+// flock takes a lock on f via LockFileEx (funlock releases it), the Windows
+// equivalent of POSIX flock(2). exclusive selects LOCKFILE_EXCLUSIVE_LOCK;
+// shared locks block only against exclusive ones, matching Unix semantics.
+func flock(f *os.File, exclusive bool) error {
+	var flags uint32
+	if exclusive {
+		flags = syscall.LOCKFILE_EXCLUSIVE_LOCK
+	}
+	overlapped := syscall.Overlapped{}
+	return syscall.LockFileEx(syscall.Handle(f.Fd()), flags, 0, 1, 0, &overlapped)
+}
+
+func funlock(f *os.File) error {
+	overlapped := syscall.Overlapped{}
+	return syscall.UnlockFileEx(syscall.Handle(f.Fd()), 0, 1, 0, &overlapped)
+}
+
+// tryFlock is flock's non-blocking counterpart: ok is false (with a nil
+// error) when the lock is held by someone else instead of blocking for it.
+func tryFlock(f *os.File, exclusive bool) (ok bool, err error) {
+	flags := uint32(syscall.LOCKFILE_FAIL_IMMEDIATELY)
+	if exclusive {
+		flags |= syscall.LOCKFILE_EXCLUSIVE_LOCK
+	}
+	overlapped := syscall.Overlapped{}
+	if err := syscall.LockFileEx(syscall.Handle(f.Fd()), flags, 0, 1, 0, &overlapped); err != nil {
+		if err == syscall.ERROR_LOCK_VIOLATION {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/credlink/sdk/go/credlink/events"
+)
+
+// batchJob is one feed entry dispatched to the worker pool, tagged with its
+// absolute index in the feed (not its position in a resumed sub-slice) so
+// the contiguous-cursor bookkeeping in runBatchWorkerPool stays correct
+// across --resume.
+type batchJob struct {
+	index int
+	entry FeedEntry
+}
+
+type batchResult struct {
+	index int
+	url   string
+	err   error
+	// skip marks a result the dispatcher resolved directly from the
+	// completed-hash set without ever dispatching it to a worker; it still
+	// advances the contiguous cursor but isn't counted or WAL-appended
+	// again, since it was already accounted for when the WAL was replayed.
+	skip bool
+}
+
+// reportBatchFailure emits a structured record for a failed entry in
+// ndjson/json/github-actions output mode, mirroring renderAssetProgress's
+// mode gating in verify.go: human mode already renders failures through
+// the progress bar and state.LastError, so it's a no-op there to avoid
+// printing the same failure twice.
+func reportBatchFailure(url string, err error) {
+	switch outputMode() {
+	case events.OutputNDJSON, events.OutputJSON, events.OutputGitHubActions:
+		newEventEmitter().Emit(events.AssetVerified(url, false, "", err.Error()))
+	}
+}
+
+// callProcess runs process and converts a panic into an ordinary error
+// instead of letting it escape the worker goroutine - main.go's top-level
+// recover only guards the main goroutine, so one malformed entry anywhere
+// in a multi-thousand-entry feed would otherwise take down the whole batch
+// (or mirror, or recursive diff, or prefix verify) run instead of being
+// recorded as that one entry's failure.
+func callProcess(process func(entry FeedEntry, index int) error, entry FeedEntry, index int) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic processing %s: %v", entry.URL, r)
+		}
+	}()
+	return process(entry, index)
+}
+
+// runBatchWorkerPool fans entries out to batchOpts.Concurrency workers,
+// each calling process, and folds results back into state as they
+// complete - which, since workers finish out of dispatch order, is not
+// the same as folding them in as they're sent to jobs.
+//
+// state.LastCursor only ever advances to the highest index N such that
+// every entry in [offset, N] has completed, never just the last index any
+// worker happened to finish - so a --resume after an abort reprocesses
+// nothing that's still in flight and skips nothing that never finished.
+//
+// completed is the set of URL hashes loadBatchState already replayed from
+// the WAL; an entry whose hash is in it is already done (even if the feed
+// has been reordered since, so its absolute index alone wouldn't catch it)
+// and is resolved without ever touching a worker.
+//
+// Every real result is appended to the WAL the instant it's known, and the
+// WAL is folded into the snapshot and truncated once it crosses
+// walCompactThreshold records, instead of the old rewrite-the-whole-
+// snapshot-every-10-entries checkpoint.
+//
+// ctx canceled (Ctrl-C, or a halt-on match) stops dispatch and in-flight
+// workers are allowed to finish their current job before the pool drains;
+// aborted reports whether that happened instead of a clean finish.
+//
+// process does the actual per-entry work (verify, sign, ...); the pool
+// itself is otherwise agnostic to what an entry means, which is what lets
+// batch sign reuse the exact same dispatch/WAL/cursor/halt-on machinery as
+// batch verify instead of re-implementing it.
+func runBatchWorkerPool(ctx context.Context, entries []FeedEntry, offset int, state *BatchState, completed map[string]bool, progress *progressBar, process func(entry FeedEntry, index int) error) (aborted bool, haltErr error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	walFile := walPath(batchOpts.StateFile)
+
+	jobs := make(chan batchJob)
+	results := make(chan batchResult)
+
+	concurrency := batchOpts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				err := callProcess(process, job.entry, job.index)
+				// Always hand the result back, even after cancellation: the
+				// collector keeps reading results until it's closed, and
+				// dropping this one would lose a job that already ran.
+				results <- batchResult{index: job.index, url: job.entry.URL, err: err}
+			}
+		}()
+	}
+
+	// The dispatcher also resolves already-completed entries directly onto
+	// results, bypassing jobs/workers entirely, so it joins wg too: results
+	// can't be closed until both the workers and the dispatcher are done
+	// sending to it.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		for i, entry := range entries {
+			if completed[urlHash(entry.URL)] {
+				select {
+				case results <- batchResult{index: offset + i, url: entry.URL, skip: true}:
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			select {
+			case jobs <- batchJob{index: offset + i, entry: entry}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	done := make(map[int]bool)
+	nextContiguous := offset
+
+	recordResult := func(result batchResult) {
+		done[result.index] = true
+		for done[nextContiguous] {
+			delete(done, nextContiguous)
+			nextContiguous++
+		}
+		state.LastCursor = strconv.Itoa(nextContiguous - 1)
+
+		if result.skip {
+			return
+		}
+
+		rec := walRecord{Index: result.index, URL: result.url, Status: walStatusOK, SHA256: urlHash(result.url)}
+		if result.err != nil {
+			state.Failures++
+			state.LastError = result.err.Error()
+			reportBatchFailure(result.url, result.err)
+			rec.Status, rec.Error = walStatusFail, result.err.Error()
+		} else {
+			state.Successes++
+		}
+		state.Processed++
+		if err := appendWAL(walFile, rec); err != nil {
+			PrintErrf("Warning: failed to append WAL record: %v\n", err)
+		}
+		if state.Processed%walCompactThreshold == 0 {
+			if err := compactWAL(state, walFile); err != nil {
+				PrintErrf("Warning: failed to compact WAL: %v\n", err)
+			}
+		}
+	}
+
+	for {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				progress.finish(aborted)
+				return aborted, haltErr
+			}
+
+			recordResult(result)
+			progress.update(state.Processed, state.Successes, state.Failures)
+
+			if result.err != nil && haltErr == nil && shouldHalt(result.err) {
+				haltErr = result.err
+				aborted = true
+				cancel()
+			}
+
+		case <-ctx.Done():
+			aborted = true
+			// Drain remaining in-flight results so state/cursor/WAL reflect
+			// every job a worker had already started, not just the ones
+			// acknowledged before cancellation.
+			for result := range results {
+				recordResult(result)
+			}
+			progress.finish(aborted)
+			return aborted, haltErr
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/credlink/cli/pkg/storage"
+	"github.com/credlink/sdk/go/credlink"
+)
+
+// batchSignEntryOptions is FeedEntry.Options decoded for batch sign: which
+// signer to use and the C2PA-ish claim metadata to attach to the manifest.
+type batchSignEntryOptions struct {
+	SignerID        string   `json:"signer_id"`
+	ClaimGenerators []string `json:"claim_generators,omitempty"`
+	ParentAsset     string   `json:"parent_asset,omitempty"`
+	Assertions      []string `json:"assertions,omitempty"`
+}
+
+// decodeBatchSignOptions round-trips entry.Options (a generic
+// map[string]interface{}, the same shape every feed format decodes options
+// into) through JSON into batchSignEntryOptions, the same pattern
+// SignFolderOptions.Files uses for per-file overrides.
+func decodeBatchSignOptions(entry FeedEntry) (batchSignEntryOptions, error) {
+	var opts batchSignEntryOptions
+	if len(entry.Options) == 0 {
+		return opts, fmt.Errorf("entry %s has no options.signer_id", entry.URL)
+	}
+
+	data, err := json.Marshal(entry.Options)
+	if err != nil {
+		return opts, fmt.Errorf("marshal options for %s: %w", entry.URL, err)
+	}
+	if err := json.Unmarshal(data, &opts); err != nil {
+		return opts, fmt.Errorf("parse options for %s: %w", entry.URL, err)
+	}
+	if opts.SignerID == "" {
+		return opts, fmt.Errorf("entry %s missing options.signer_id", entry.URL)
+	}
+	return opts, nil
+}
+
+// batchSignManifest is one line of the JSONL manifest batch sign writes,
+// shaped so it can be fed straight back into `batch verify` as a feed in
+// its own right.
+type batchSignManifest struct {
+	URL         string    `json:"url"`
+	OutputURL   string    `json:"output_url"`
+	ManifestSHA string    `json:"manifest_sha"`
+	SignedAt    time.Time `json:"signed_at"`
+}
+
+// signManifestWriter appends batchSignManifest lines from concurrent worker
+// goroutines to a single JSONL file.
+type signManifestWriter struct {
+	mu  sync.Mutex
+	f   *os.File
+	enc *json.Encoder
+}
+
+func newSignManifestWriter(path string) (*signManifestWriter, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open manifest output %s: %w", path, err)
+	}
+	return &signManifestWriter{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+func (w *signManifestWriter) write(rec batchSignManifest) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.enc.Encode(rec)
+}
+
+func (w *signManifestWriter) Close() error {
+	return w.f.Close()
+}
+
+// signBatchEntry is the per-entry work for `batch sign`: resolve the
+// entry's signer, fetch and hash the asset, sign the digest locally (the
+// key never leaving the keyring's KMS/HSM/local source), register a
+// manifest for it, write a `<key>.credlink.json` sidecar next to the asset,
+// and append a line to the output JSONL manifest.
+func signBatchEntry(ctx context.Context, client *credlink.Client, keyring *signerKeyring, manifest *signManifestWriter, entry FeedEntry, index int) error {
+	opts, err := decodeBatchSignOptions(entry)
+	if err != nil {
+		return err
+	}
+
+	signer, err := keyring.resolve(ctx, opts.SignerID)
+	if err != nil {
+		return err
+	}
+
+	backend, key, err := storage.Factory(entry.URL)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", entry.URL, err)
+	}
+
+	body, meta, err := backend.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("get %s: %w", entry.URL, err)
+	}
+	defer body.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, body); err != nil {
+		return fmt.Errorf("hash %s: %w", entry.URL, err)
+	}
+	digest := hash.Sum(nil)
+	digestHex := hex.EncodeToString(digest)
+
+	sig, err := signer.Signer.Sign(nil, digest, crypto.SHA256)
+	if err != nil {
+		return fmt.Errorf("sign %s with signer_id %s: %w", entry.URL, opts.SignerID, err)
+	}
+
+	manifestBody, err := json.Marshal(map[string]interface{}{
+		"object_key":        key,
+		"size":              meta.Size,
+		"signer_id":         opts.SignerID,
+		"claim_generators":  opts.ClaimGenerators,
+		"parent_asset":      opts.ParentAsset,
+		"assertions":        opts.Assertions,
+		"signature":         sig,
+		"certificate_chain": signer.CertChain,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal manifest body for %s: %w", entry.URL, err)
+	}
+
+	manifestResp, err := client.PutManifest(ctx, digestHex, manifestBody, credlink.PutManifestOptions{
+		Metadata: map[string]interface{}{
+			"signer_id":    opts.SignerID,
+			"parent_asset": opts.ParentAsset,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("put manifest for %s: %w", entry.URL, err)
+	}
+
+	sidecar, err := json.MarshalIndent(manifestResp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal sidecar for %s: %w", entry.URL, err)
+	}
+
+	outputKey := key + ".credlink.json"
+	if err := backend.Put(ctx, outputKey, bytes.NewReader(sidecar), storage.ObjectMeta{ContentType: "application/json"}); err != nil {
+		return fmt.Errorf("put sidecar for %s: %w", entry.URL, err)
+	}
+
+	return manifest.write(batchSignManifest{
+		URL:         entry.URL,
+		OutputURL:   entry.URL + ".credlink.json",
+		ManifestSHA: digestHex,
+		SignedAt:    time.Now(),
+	})
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// walRecord is one append-only line in the WAL, recording a single entry's
+// outcome the instant it's known so a crash loses at most the record
+// currently being written, not the whole batch of entries since the last
+// snapshot.
+type walRecord struct {
+	Index  int    `json:"index"`
+	URL    string `json:"url"`
+	Status string `json:"status"` // walStatusOK or walStatusFail
+	Error  string `json:"error,omitempty"`
+	SHA256 string `json:"sha256"`
+}
+
+const (
+	walStatusOK   = "ok"
+	walStatusFail = "fail"
+)
+
+// walCompactThreshold is how many WAL records accumulate before
+// runBatchWorkerPool folds them into the snapshot and truncates the WAL,
+// bounding both the WAL's size and how much a crash makes the next replay
+// re-derive.
+const walCompactThreshold = 500
+
+// urlHash content-addresses a feed entry by its URL, the key duplicates
+// (even across a feed that's been reordered since the last run) are
+// recognized by on resume.
+func urlHash(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
+
+// walPath derives the WAL sibling of a state file: ".c2c-batch.state"
+// becomes ".c2c-batch.wal"; a state file with no extension just gets
+// ".wal" appended.
+func walPath(stateFile string) string {
+	ext := filepath.Ext(stateFile)
+	if ext == "" {
+		return stateFile + ".wal"
+	}
+	return strings.TrimSuffix(stateFile, ext) + ".wal"
+}
+
+// appendWAL appends one record to the WAL and syncs before returning, so a
+// record is only ever lost by a crash if it was never appended at all, not
+// because it was sitting unflushed in a page cache.
+func appendWAL(path string, rec walRecord) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open WAL: %w", err)
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("append WAL: %w", err)
+	}
+	return f.Sync()
+}
+
+// replayWAL reads every well-formed record from path. A trailing line that
+// fails to parse is silently dropped instead of erroring the whole replay,
+// since that's exactly the shape a crash mid-append leaves behind: a torn,
+// incomplete last line.
+func replayWAL(path string) ([]walRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read WAL: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	records := make([]walRecord, 0, len(lines))
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		var rec walRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			if i == len(lines)-1 {
+				break // torn last line from a crash mid-append
+			}
+			continue
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// applyWALReplay folds records onto state's counters and LastCursor (via
+// the same highest-contiguous-index rule runBatchWorkerPool itself applies
+// live) and returns the set of completed URL hashes, so a resumed run can
+// skip a URL that's already done even if its position in the feed has
+// since shifted.
+func applyWALReplay(state *BatchState, records []walRecord) map[string]bool {
+	completed := make(map[string]bool, len(records))
+	done := make(map[int]bool, len(records))
+
+	baseCursor := -1
+	if n, err := strconv.Atoi(state.LastCursor); err == nil {
+		baseCursor = n
+	}
+	nextContiguous := baseCursor + 1
+
+	for _, rec := range records {
+		if rec.Status == walStatusOK {
+			state.Successes++
+		} else {
+			state.Failures++
+			state.LastError = rec.Error
+		}
+		state.Processed++
+		completed[rec.SHA256] = true
+
+		done[rec.Index] = true
+		for done[nextContiguous] {
+			delete(done, nextContiguous)
+			nextContiguous++
+		}
+	}
+	if nextContiguous-1 > baseCursor {
+		state.LastCursor = strconv.Itoa(nextContiguous - 1)
+	}
+	return completed
+}
+
+// compactWAL writes state as the new snapshot and truncates the WAL - the
+// "dump/restore" half of the transactional checkpoint pattern. Once this
+// returns, a fresh replayWAL has nothing left to fold in until the next
+// record is appended.
+func compactWAL(state *BatchState, walFile string) error {
+	if err := saveBatchState(state); err != nil {
+		return err
+	}
+	if err := os.Truncate(walFile, 0); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("truncate WAL: %w", err)
+	}
+	return nil
+}
@@ -1,22 +1,30 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/credlink/cli/internal/cache"
 	"github.com/spf13/cobra"
 )
 
 // Cache command options
 type CacheOptions struct {
-	Stats   bool
-	Prune   bool
-	Clear   bool
-	MaxAge  time.Duration
-	MaxSize string
+	Stats       bool
+	Prune       bool
+	Clear       bool
+	MaxAge      time.Duration
+	MaxSize     string
+	KeepStorage string
+	MinHits     int
+	Jobs        int
 }
 
 var cacheOpts CacheOptions
@@ -46,6 +54,7 @@ locations on Windows/macOS.`,
 	InitCachePruneCommand(cacheCmd)
 	InitCacheClearCommand(cacheCmd)
 	InitCacheStatsCommand(cacheCmd)
+	InitCacheVerifyCommand(cacheCmd)
 
 	rootCmd.AddCommand(cacheCmd)
 }
@@ -74,6 +83,9 @@ Preserves frequently accessed items.`,
 	// Prune flags
 	pruneCmd.Flags().DurationVar(&cacheOpts.MaxAge, "max-age", 7*24*time.Hour, "Maximum age for entries")
 	pruneCmd.Flags().StringVar(&cacheOpts.MaxSize, "max-size", "1GB", "Maximum cache size")
+	pruneCmd.Flags().StringVar(&cacheOpts.KeepStorage, "keep-storage", "", "Evict coldest entries (ascending access_count, last_access) until total cache size is at most this, e.g. 500MB")
+	pruneCmd.Flags().IntVar(&cacheOpts.MinHits, "min-hits", 0, "Never evict a --keep-storage-triggered entry with at least this many recorded accesses unless it's also older than --max-age")
+	pruneCmd.Flags().IntVar(&cacheOpts.Jobs, "jobs", 0, "Parallel cache-walk workers (0 = GOMAXPROCS)")
 
 	cacheCmd.AddCommand(pruneCmd)
 }
@@ -99,6 +111,8 @@ including file counts, sizes, and hit rates.`,
 		RunE: runCacheStatsCommand,
 	}
 
+	statsCmd.Flags().IntVar(&cacheOpts.Jobs, "jobs", 0, "Parallel cache-walk workers (0 = GOMAXPROCS)")
+
 	cacheCmd.AddCommand(statsCmd)
 }
 
@@ -112,7 +126,17 @@ func runCacheLsCommand(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	entries, err := listCacheEntries(cacheDir)
+	c, err := cache.Open(cacheDir)
+	if err != nil {
+		return err
+	}
+	unlock, err := c.RLock()
+	if err != nil {
+		return fmt.Errorf("cache ls: %w", err)
+	}
+	defer unlock()
+
+	entries, err := listCacheEntries(cacheDir, 0)
 	if err != nil {
 		return err
 	}
@@ -146,22 +170,40 @@ func runCachePruneCommand(cmd *cobra.Command, args []string) error {
 
 	PrintMsgf("Pruning cache entries older than %v\n", cacheOpts.MaxAge)
 
-	removed, sizeFreed, err := pruneCacheEntries(cacheDir, cacheOpts.MaxAge)
+	keepStorageBytes, err := parseByteSize(cacheOpts.KeepStorage)
+	if err != nil {
+		return fmt.Errorf("--keep-storage: %w", err)
+	}
+
+	c, err := cache.Open(cacheDir)
+	if err != nil {
+		return err
+	}
+	unlock, err := c.Lock()
+	if err != nil {
+		return fmt.Errorf("cache prune: %w", err)
+	}
+	defer unlock()
+
+	pruned, err := pruneCacheEntries(cacheDir, cacheOpts.MaxAge, keepStorageBytes, cacheOpts.MinHits, cacheOpts.Jobs)
 	if err != nil {
 		return err
 	}
 
 	result := map[string]interface{}{
-		"removed_files":    removed,
-		"size_freed":       sizeFreed,
-		"size_freed_human": formatBytes(sizeFreed),
+		"removed_files":    pruned.RemovedFiles,
+		"size_freed":       pruned.SizeFreed,
+		"size_freed_human": formatBytes(pruned.SizeFreed),
+		"kept_hot":         pruned.KeptHot,
+		"kept_recent":      pruned.KeptRecent,
 	}
 
 	if globalOpts.JSON {
 		return PrintOutput(result)
 	}
 
-	PrintMsgf("Pruned %d files, freed %s\n", removed, formatBytes(sizeFreed))
+	PrintMsgf("Pruned %d files, freed %s (kept %d hot, %d recent)\n",
+		pruned.RemovedFiles, formatBytes(pruned.SizeFreed), pruned.KeptHot, pruned.KeptRecent)
 	return nil
 }
 
@@ -175,6 +217,16 @@ func runCacheClearCommand(cmd *cobra.Command, args []string) error {
 
 	PrintMsgf("Clearing cache directory: %s\n", cacheDir)
 
+	c, err := cache.Open(cacheDir)
+	if err != nil {
+		return err
+	}
+	unlock, err := c.Lock()
+	if err != nil {
+		return fmt.Errorf("cache clear: %w", err)
+	}
+	defer unlock()
+
 	removed, sizeFreed, err := clearCacheDir(cacheDir)
 	if err != nil {
 		return err
@@ -197,7 +249,17 @@ func runCacheClearCommand(cmd *cobra.Command, args []string) error {
 func runCacheStatsCommand(cmd *cobra.Command, args []string) error {
 	cacheDir := getCacheDir()
 
-	stats, err := getCacheStatistics(cacheDir)
+	c, err := cache.Open(cacheDir)
+	if err != nil {
+		return err
+	}
+	unlock, err := c.RLock()
+	if err != nil {
+		return fmt.Errorf("cache stats: %w", err)
+	}
+	defer unlock()
+
+	stats, err := getCacheStatistics(cacheDir, cacheOpts.Jobs)
 	if err != nil {
 		return err
 	}
@@ -254,23 +316,44 @@ func getCacheDir() string {
 	return filepath.Join(home, ".cache", "credlink")
 }
 
-func listCacheEntries(cacheDir string) ([]CacheEntry, error) {
-	var entries []CacheEntry
+// walkCacheFiles parallel-walks cacheDir via cache.Walk (jobs<=0 means
+// GOMAXPROCS workers), invoking fn once per surfaced file with its path
+// relative to cacheDir. Directories and isCacheInternalPath bookkeeping
+// (sidecars, the access DB, the cache-wide lock, the quarantine directory)
+// are skipped without ever descending into them. fn may be called
+// concurrently from multiple workers, so callers touching shared state
+// must synchronize it themselves.
+func walkCacheFiles(cacheDir string, jobs int, fn func(relPath string, info fs.FileInfo) error) error {
+	c, err := cache.Open(cacheDir)
+	if err != nil {
+		return err
+	}
 
-	err := filepath.Walk(cacheDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil // Skip errors
+	return c.Walk(context.Background(), jobs, func(relPath string, d fs.DirEntry) error {
+		absPath := filepath.Join(cacheDir, relPath)
+		if isCacheInternalPath(cacheDir, absPath) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
 		}
-
-		if info.IsDir() {
+		if d.IsDir() {
 			return nil
 		}
 
-		relPath, err := filepath.Rel(cacheDir, path)
+		info, err := d.Info()
 		if err != nil {
 			return nil
 		}
+		return fn(relPath, info)
+	})
+}
+
+func listCacheEntries(cacheDir string, jobs int) ([]CacheEntry, error) {
+	var mu sync.Mutex
+	var entries []CacheEntry
 
+	err := walkCacheFiles(cacheDir, jobs, func(relPath string, info fs.FileInfo) error {
 		entry := CacheEntry{
 			Name:     relPath,
 			Size:     info.Size(),
@@ -278,94 +361,83 @@ func listCacheEntries(cacheDir string) ([]CacheEntry, error) {
 		}
 
 		// Determine type based on path
-		if filepath.Base(path) == "manifest.json" {
+		switch filepath.Base(relPath) {
+		case "manifest.json":
 			entry.Type = "manifest"
-		} else if filepath.Base(path) == "verification.json" {
+		case "verification.json":
 			entry.Type = "verification"
-		} else {
+		default:
 			entry.Type = "other"
 		}
 
+		mu.Lock()
 		entries = append(entries, entry)
+		mu.Unlock()
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
 
-	return entries, err
+	// cache.Walk fans out across workers, so entry order isn't deterministic;
+	// sort by name to keep `cache ls` output stable between runs.
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
 }
 
-func pruneCacheEntries(cacheDir string, maxAge time.Duration) (int, int64, error) {
+// clearCacheDir removes every entry under cacheDir via cache.Walk, using
+// fs.SkipDir to remove each directory wholesale as soon as it's reached
+// instead of descending into a directory it's about to delete out from
+// under a sibling worker.
+func clearCacheDir(cacheDir string) (int, int64, error) {
+	c, err := cache.Open(cacheDir)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var mu sync.Mutex
 	var removed int
 	var sizeFreed int64
-	cutoff := time.Now().Add(-maxAge)
-
-	err := filepath.Walk(cacheDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
 
-		if info.IsDir() {
-			return nil
-		}
+	err = c.Walk(context.Background(), 0, func(relPath string, d fs.DirEntry) error {
+		absPath := filepath.Join(cacheDir, relPath)
 
-		if info.ModTime().Before(cutoff) {
-			if err := os.Remove(path); err == nil {
+		if d.IsDir() {
+			if err := os.RemoveAll(absPath); err == nil {
+				mu.Lock()
 				removed++
-				sizeFreed += info.Size()
+				mu.Unlock()
 			}
+			return fs.SkipDir
 		}
 
-		return nil
-	})
-
-	return removed, sizeFreed, err
-}
-
-func clearCacheDir(cacheDir string) (int, int64, error) {
-	var removed int
-	var sizeFreed int64
-
-	err := filepath.Walk(cacheDir, func(path string, info os.FileInfo, err error) error {
+		info, err := d.Info()
 		if err != nil {
 			return nil
 		}
-
-		if path == cacheDir {
-			return nil
-		}
-
-		if info.IsDir() {
-			if err := os.RemoveAll(path); err == nil {
-				// Count files in directory (simplified)
-				removed++
-			}
-		} else {
-			if err := os.Remove(path); err == nil {
-				removed++
-				sizeFreed += info.Size()
-			}
+		if err := os.Remove(absPath); err == nil {
+			mu.Lock()
+			removed++
+			sizeFreed += info.Size()
+			mu.Unlock()
 		}
-
 		return nil
 	})
 
 	return removed, sizeFreed, err
 }
 
-func getCacheStatistics(cacheDir string) (*CacheStats, error) {
+func getCacheStatistics(cacheDir string, jobs int) (*CacheStats, error) {
 	stats := &CacheStats{
 		Path: cacheDir,
 	}
 
+	var mu sync.Mutex
 	var oldest, newest time.Time
 
-	err := filepath.Walk(cacheDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return nil
-		}
-
-		if info.IsDir() {
-			return nil
-		}
+	err := walkCacheFiles(cacheDir, jobs, func(relPath string, info fs.FileInfo) error {
+		mu.Lock()
+		defer mu.Unlock()
 
 		stats.TotalFiles++
 		stats.TotalSize += info.Size()
@@ -378,9 +450,10 @@ func getCacheStatistics(cacheDir string) (*CacheStats, error) {
 		}
 
 		// Count types
-		if filepath.Base(path) == "manifest.json" {
+		switch filepath.Base(relPath) {
+		case "manifest.json":
 			stats.ManifestCount++
-		} else if filepath.Base(path) == "verification.json" {
+		case "verification.json":
 			stats.VerifyCount++
 		}
 
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cacheAccessDBFileName is a JSON document (despite the .db name, chosen to
+// read naturally next to the cache's sha256/ fan-out dirs) keyed by
+// cache-relative path, recording enough per-entry history for prune's
+// ascending (access_count, last_access) eviction order.
+const cacheAccessDBFileName = "access.db"
+
+type cacheAccessRecord struct {
+	LastAccess  time.Time `json:"last_access"`
+	AccessCount int64     `json:"access_count"`
+	Bytes       int64     `json:"bytes"`
+}
+
+type cacheAccessDB struct {
+	Entries map[string]*cacheAccessRecord `json:"entries"`
+}
+
+func loadCacheAccessDB(cacheDir string) (*cacheAccessDB, error) {
+	raw, err := os.ReadFile(filepath.Join(cacheDir, cacheAccessDBFileName))
+	if os.IsNotExist(err) {
+		return &cacheAccessDB{Entries: map[string]*cacheAccessRecord{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read access db: %w", err)
+	}
+
+	var db cacheAccessDB
+	if err := json.Unmarshal(raw, &db); err != nil {
+		return nil, fmt.Errorf("parse access db: %w", err)
+	}
+	if db.Entries == nil {
+		db.Entries = map[string]*cacheAccessRecord{}
+	}
+	return &db, nil
+}
+
+func (db *cacheAccessDB) save(cacheDir string) error {
+	raw, err := json.Marshal(db)
+	if err != nil {
+		return fmt.Errorf("marshal access db: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, cacheAccessDBFileName), raw, 0o644); err != nil {
+		return fmt.Errorf("write access db: %w", err)
+	}
+	return nil
+}
+
+// recordCacheAccess bumps the access-frequency record for relPath (a path
+// relative to cacheDir), called when sign --resume loads its checkpoint from
+// the cache directory so prune can tell a frequently-reused entry from one
+// that's just sitting there.
+func recordCacheAccess(cacheDir, relPath string, size int64) error {
+	db, err := loadCacheAccessDB(cacheDir)
+	if err != nil {
+		return err
+	}
+
+	record, ok := db.Entries[relPath]
+	if !ok {
+		record = &cacheAccessRecord{}
+		db.Entries[relPath] = record
+	}
+	record.LastAccess = time.Now()
+	record.AccessCount++
+	record.Bytes = size
+
+	return db.save(cacheDir)
+}
@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/credlink/cli/internal/cache"
+	"github.com/spf13/cobra"
+)
+
+// Sidecar format: cacheIntegritySidecarVersion1 is the only version so far.
+// A sidecar is written next to each cached payload as "<file>.bit":
+//
+//	[0]      version byte
+//	[1]      algorithm ID (cacheIntegrityAlgoSHA256)
+//	[2:6]    block size, uint32 big-endian
+//	[6:14]   total payload length, uint64 big-endian
+//	[14:46]  root hash (sha256 of the whole payload)
+//	[46:]    one sha256 hash per 64 KiB-aligned block, in order
+const (
+	cacheIntegritySidecarVersion1 byte = 1
+	cacheIntegrityAlgoSHA256      byte = 1
+	cacheIntegrityBlockSize            = 64 * 1024
+	cacheIntegritySidecarSuffix        = ".bit"
+	cacheIntegrityQuarantineDir        = ".corrupt"
+)
+
+// cacheIntegritySidecar is the decoded form of a "<file>.bit" sidecar.
+type cacheIntegritySidecar struct {
+	BlockSize   uint32
+	TotalLength uint64
+	RootHash    []byte
+	BlockHashes [][]byte
+}
+
+// writeCacheFileWithIntegrity writes data to path and a matching "<path>.bit"
+// sidecar recording its root hash plus 64 KiB-aligned block hashes, so a
+// later `cache verify` can detect and localize on-disk bitrot.
+func writeCacheFileWithIntegrity(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create cache entry directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write cache entry %s: %w", path, err)
+	}
+
+	sidecar := buildCacheIntegritySidecar(data)
+	if err := os.WriteFile(path+cacheIntegritySidecarSuffix, encodeCacheIntegritySidecar(sidecar), 0o644); err != nil {
+		return fmt.Errorf("write integrity sidecar for %s: %w", path, err)
+	}
+	return nil
+}
+
+func buildCacheIntegritySidecar(data []byte) cacheIntegritySidecar {
+	root := sha256.Sum256(data)
+	var blocks [][]byte
+	for off := 0; off < len(data); off += cacheIntegrityBlockSize {
+		end := off + cacheIntegrityBlockSize
+		if end > len(data) {
+			end = len(data)
+		}
+		h := sha256.Sum256(data[off:end])
+		blocks = append(blocks, h[:])
+	}
+	return cacheIntegritySidecar{
+		BlockSize:   cacheIntegrityBlockSize,
+		TotalLength: uint64(len(data)),
+		RootHash:    root[:],
+		BlockHashes: blocks,
+	}
+}
+
+func encodeCacheIntegritySidecar(s cacheIntegritySidecar) []byte {
+	buf := make([]byte, 14+len(s.RootHash)+len(s.BlockHashes)*sha256.Size)
+	buf[0] = cacheIntegritySidecarVersion1
+	buf[1] = cacheIntegrityAlgoSHA256
+	binary.BigEndian.PutUint32(buf[2:6], s.BlockSize)
+	binary.BigEndian.PutUint64(buf[6:14], s.TotalLength)
+	off := 14
+	off += copy(buf[off:], s.RootHash)
+	for _, b := range s.BlockHashes {
+		off += copy(buf[off:], b)
+	}
+	return buf
+}
+
+func decodeCacheIntegritySidecar(raw []byte) (cacheIntegritySidecar, error) {
+	if len(raw) < 14+sha256.Size {
+		return cacheIntegritySidecar{}, fmt.Errorf("sidecar too short (%d bytes)", len(raw))
+	}
+	if raw[0] != cacheIntegritySidecarVersion1 {
+		return cacheIntegritySidecar{}, fmt.Errorf("unsupported sidecar version %d", raw[0])
+	}
+	if raw[1] != cacheIntegrityAlgoSHA256 {
+		return cacheIntegritySidecar{}, fmt.Errorf("unsupported sidecar algorithm %d", raw[1])
+	}
+	s := cacheIntegritySidecar{
+		BlockSize:   binary.BigEndian.Uint32(raw[2:6]),
+		TotalLength: binary.BigEndian.Uint64(raw[6:14]),
+	}
+	off := 14
+	s.RootHash = raw[off : off+sha256.Size]
+	off += sha256.Size
+
+	rest := raw[off:]
+	if len(rest)%sha256.Size != 0 {
+		return cacheIntegritySidecar{}, fmt.Errorf("sidecar block hash array is not a multiple of %d bytes", sha256.Size)
+	}
+	for i := 0; i < len(rest); i += sha256.Size {
+		s.BlockHashes = append(s.BlockHashes, rest[i:i+sha256.Size])
+	}
+	return s, nil
+}
+
+// cacheIntegrityResult is one entry's outcome from `cache verify`.
+type cacheIntegrityResult struct {
+	Path       string `json:"path"`
+	Protected  bool   `json:"protected"`
+	Healthy    bool   `json:"healthy"`
+	BadOffset  int64  `json:"bad_offset,omitempty"`
+	BadBlock   int    `json:"bad_block,omitempty"`
+	ErrMessage string `json:"error,omitempty"`
+}
+
+// verifyCacheFile checks path against its "<path>.bit" sidecar: the root
+// hash first, and - only if that fails - each block hash in turn, so the
+// report can point at the first corrupt offset instead of just saying
+// "bitrot happened somewhere in this file".
+func verifyCacheFile(path string) cacheIntegrityResult {
+	result := cacheIntegrityResult{Path: path, BadOffset: -1, BadBlock: -1}
+
+	sidecarRaw, err := os.ReadFile(path + cacheIntegritySidecarSuffix)
+	if os.IsNotExist(err) {
+		result.Healthy = true // unprotected, but that's not bitrot
+		return result
+	}
+	if err != nil {
+		result.ErrMessage = fmt.Sprintf("read sidecar: %v", err)
+		return result
+	}
+	result.Protected = true
+
+	sidecar, err := decodeCacheIntegritySidecar(sidecarRaw)
+	if err != nil {
+		result.ErrMessage = fmt.Sprintf("decode sidecar: %v", err)
+		return result
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		result.ErrMessage = fmt.Sprintf("read payload: %v", err)
+		return result
+	}
+
+	root := sha256.Sum256(data)
+	if string(root[:]) == string(sidecar.RootHash) {
+		result.Healthy = true
+		return result
+	}
+
+	// Root hash mismatched - stream-verify blocks to localize the damage.
+	blockSize := int(sidecar.BlockSize)
+	if blockSize <= 0 {
+		blockSize = cacheIntegrityBlockSize
+	}
+	for i, want := range sidecar.BlockHashes {
+		off := i * blockSize
+		if off >= len(data) {
+			result.BadOffset = int64(off)
+			result.BadBlock = i
+			result.ErrMessage = "payload is shorter than the sidecar's recorded length"
+			return result
+		}
+		end := off + blockSize
+		if end > len(data) {
+			end = len(data)
+		}
+		got := sha256.Sum256(data[off:end])
+		if string(got[:]) != string(want) {
+			result.BadOffset = int64(off)
+			result.BadBlock = i
+			result.ErrMessage = fmt.Sprintf("block %d corrupt at offset %d", i, off)
+			return result
+		}
+	}
+
+	result.ErrMessage = "root hash mismatched but every block hash matched (sidecar likely stale)"
+	return result
+}
+
+// cacheVerifyReport is the `cache verify` summary, JSON-printable like
+// CacheStats.
+type cacheVerifyReport struct {
+	Path        string                 `json:"path"`
+	Scanned     int                    `json:"scanned"`
+	Healthy     int                    `json:"healthy"`
+	Unprotected int                    `json:"unprotected"`
+	Corrupt     int                    `json:"corrupt"`
+	Repaired    int                    `json:"repaired,omitempty"`
+	Entries     []cacheIntegrityResult `json:"corrupt_entries,omitempty"`
+}
+
+// CacheVerifyOptions are the `cache verify` flags.
+type CacheVerifyOptions struct {
+	Repair bool
+	Sample int
+}
+
+var cacheVerifyOpts CacheVerifyOptions
+
+func InitCacheVerifyCommand(cacheCmd *cobra.Command) {
+	var verifyCmd = &cobra.Command{
+		Use:   "verify",
+		Short: "Check cache entries for bitrot against their integrity sidecars",
+		Long: `Walk the cache directory and verify each entry's "<file>.bit"
+sidecar, reporting healthy, unprotected (written before sidecars existed),
+and corrupt entries. With --repair, corrupt entries are moved under
+<cache>/.corrupt/ instead of being deleted.`,
+		RunE: runCacheVerifyCommand,
+	}
+
+	verifyCmd.Flags().BoolVar(&cacheVerifyOpts.Repair, "repair", false, "Quarantine corrupt entries under <cache>/.corrupt/")
+	verifyCmd.Flags().IntVar(&cacheVerifyOpts.Sample, "sample", 0, "Verify at most N entries (0 = verify every entry)")
+
+	cacheCmd.AddCommand(verifyCmd)
+}
+
+func runCacheVerifyCommand(cmd *cobra.Command, args []string) error {
+	cacheDir := getCacheDir()
+
+	if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
+		PrintMsg("Cache directory does not exist")
+		return nil
+	}
+
+	report, err := verifyCacheDir(cacheDir, cacheVerifyOpts.Sample, cacheVerifyOpts.Repair)
+	if err != nil {
+		return err
+	}
+
+	if globalOpts.JSON {
+		return PrintOutput(report)
+	}
+
+	PrintMsgf("Scanned %d entries: %d healthy, %d unprotected, %d corrupt\n",
+		report.Scanned, report.Healthy, report.Unprotected, report.Corrupt)
+	for _, e := range report.Entries {
+		PrintMsgf("  CORRUPT %s: %s\n", e.Path, e.ErrMessage)
+	}
+	if cacheVerifyOpts.Repair && report.Repaired > 0 {
+		PrintMsgf("Quarantined %d corrupt entries under %s\n", report.Repaired, filepath.Join(cacheDir, cacheIntegrityQuarantineDir))
+	}
+
+	return nil
+}
+
+// verifyCacheDir gathers candidates by fanning the walk of cacheDir out to
+// GOMAXPROCS workers (skipping sidecars and the quarantine directory
+// itself, via walkCacheFiles), then verifies up to sample entries (0 = all,
+// in a stable sorted order - restored after the concurrent walk - so
+// --sample=N always checks the same N entries), and, if repair is set,
+// moves corrupt entries (payload + sidecar) under <cacheDir>/.corrupt/
+// preserving their relative path.
+func verifyCacheDir(cacheDir string, sample int, repair bool) (*cacheVerifyReport, error) {
+	report := &cacheVerifyReport{Path: cacheDir}
+
+	var mu sync.Mutex
+	var candidates []string
+	err := walkCacheFiles(cacheDir, 0, func(relPath string, info fs.FileInfo) error {
+		mu.Lock()
+		candidates = append(candidates, filepath.Join(cacheDir, relPath))
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk cache directory: %w", err)
+	}
+	sort.Strings(candidates)
+	if sample > 0 && sample < len(candidates) {
+		candidates = candidates[:sample]
+	}
+
+	for _, path := range candidates {
+		result := verifyCacheFile(path)
+		report.Scanned++
+
+		switch {
+		case !result.Protected:
+			report.Unprotected++
+		case result.Healthy:
+			report.Healthy++
+		default:
+			report.Corrupt++
+			report.Entries = append(report.Entries, result)
+			if repair {
+				if err := quarantineCacheFile(cacheDir, path); err != nil {
+					result.ErrMessage += fmt.Sprintf(" (quarantine failed: %v)", err)
+				} else {
+					report.Repaired++
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// isCacheInternalPath reports whether path is bookkeeping the cache
+// subsystem maintains for itself - a ".bit" sidecar, the access-tracking
+// database, the cache-wide lock file, or anything under the quarantine
+// directory - none of which should be surfaced as a cache entry in its
+// own right.
+func isCacheInternalPath(cacheDir, path string) bool {
+	if filepath.Ext(path) == cacheIntegritySidecarSuffix {
+		return true
+	}
+	if filepath.Base(path) == cacheAccessDBFileName || filepath.Base(path) == cache.LockFileName {
+		return true
+	}
+	rel, err := filepath.Rel(cacheDir, path)
+	if err != nil {
+		return false
+	}
+	first := strings.SplitN(rel, string(filepath.Separator), 2)[0]
+	return first == cacheIntegrityQuarantineDir
+}
+
+// quarantineCacheFile moves path and its sidecar (if any) under
+// <cacheDir>/.corrupt/, preserving path's position relative to cacheDir.
+func quarantineCacheFile(cacheDir, path string) error {
+	rel, err := filepath.Rel(cacheDir, path)
+	if err != nil {
+		return err
+	}
+	dst := filepath.Join(cacheDir, cacheIntegrityQuarantineDir, rel)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	if err := os.Rename(path, dst); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path + cacheIntegritySidecarSuffix); err == nil {
+		_ = os.Rename(path+cacheIntegritySidecarSuffix, dst+cacheIntegritySidecarSuffix)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"container/heap"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cachePruneResult is what `cache prune` reports, beyond the plain
+// removed/size-freed pair it always had: how many entries survived only
+// because they're hot (kept_hot) or merely not yet stale (kept_recent), so
+// JSON consumers can audit why a prune run didn't free as much as expected.
+type cachePruneResult struct {
+	RemovedFiles int   `json:"removed_files"`
+	SizeFreed    int64 `json:"size_freed"`
+	KeptHot      int   `json:"kept_hot"`
+	KeptRecent   int   `json:"kept_recent"`
+}
+
+// cacheEvictionCandidate is one prune-eligible file plus the access-history
+// fields that decide its eviction order.
+type cacheEvictionCandidate struct {
+	path        string
+	size        int64
+	modTime     time.Time
+	accessCount int64
+	lastAccess  time.Time
+}
+
+// evictionHeap is a min-heap ordered by ascending (accessCount, lastAccess):
+// the least-used, least-recently-used entry sorts first, so prune always
+// evicts the coldest entry next without sorting the whole cache.
+type evictionHeap []cacheEvictionCandidate
+
+func (h evictionHeap) Len() int { return len(h) }
+func (h evictionHeap) Less(i, j int) bool {
+	if h[i].accessCount != h[j].accessCount {
+		return h[i].accessCount < h[j].accessCount
+	}
+	return h[i].lastAccess.Before(h[j].lastAccess)
+}
+func (h evictionHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *evictionHeap) Push(x any)   { *h = append(*h, x.(cacheEvictionCandidate)) }
+func (h *evictionHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// pruneCacheEntries walks cacheDir once (fanning the walk out to jobs
+// workers, jobs<=0 meaning GOMAXPROCS - see cache.Walk), builds a min-heap
+// of eviction candidates keyed by (access_count, last_access), and pops it
+// in ascending order: any entry older than maxAge is removed outright, and
+// once keepStorageBytes is exceeded, colder entries are removed too - unless
+// an entry's access_count is at least minHits, in which case it's kept (as
+// "hot") so long as it isn't also stale enough to expire via maxAge.
+// keepStorageBytes <= 0 disables size-based eviction; minHits <= 0 disables
+// hot protection.
+func pruneCacheEntries(cacheDir string, maxAge time.Duration, keepStorageBytes int64, minHits int, jobs int) (cachePruneResult, error) {
+	var result cachePruneResult
+
+	accessDB, err := loadCacheAccessDB(cacheDir)
+	if err != nil {
+		return result, err
+	}
+
+	var mu sync.Mutex
+	var candidates evictionHeap
+	var totalSize int64
+
+	err = walkCacheFiles(cacheDir, jobs, func(relPath string, info fs.FileInfo) error {
+		candidate := cacheEvictionCandidate{
+			path:       filepath.Join(cacheDir, relPath),
+			size:       info.Size(),
+			modTime:    info.ModTime(),
+			lastAccess: info.ModTime(),
+		}
+		if rec, ok := accessDB.Entries[relPath]; ok {
+			candidate.accessCount = rec.AccessCount
+			candidate.lastAccess = rec.LastAccess
+		}
+
+		mu.Lock()
+		candidates = append(candidates, candidate)
+		totalSize += candidate.size
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	heap.Init(&candidates)
+	cutoff := time.Now().Add(-maxAge)
+
+	for candidates.Len() > 0 {
+		candidate := heap.Pop(&candidates).(cacheEvictionCandidate)
+
+		stale := maxAge > 0 && candidate.modTime.Before(cutoff)
+		hot := minHits > 0 && candidate.accessCount >= int64(minHits)
+		overBudget := keepStorageBytes > 0 && totalSize > keepStorageBytes
+
+		evict := stale || (overBudget && !hot)
+		if !evict {
+			if hot && overBudget {
+				result.KeptHot++
+			} else {
+				result.KeptRecent++
+			}
+			continue
+		}
+
+		if err := os.Remove(candidate.path); err != nil {
+			continue
+		}
+		_ = os.Remove(candidate.path + cacheIntegritySidecarSuffix)
+		result.RemovedFiles++
+		result.SizeFreed += candidate.size
+		totalSize -= candidate.size
+	}
+
+	return result, nil
+}
+
+// parseByteSize parses a human size like "500MB" or "2GiB" (binary units,
+// case-insensitive, bare digits meaning bytes) - the same K/M/G/T/P scale
+// formatBytes prints with, just in reverse.
+func parseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	units := []string{"EB", "PB", "TB", "GB", "MB", "KB", "B"}
+	upper := strings.ToUpper(s)
+	if strings.HasSuffix(upper, "IB") {
+		// "GiB" and friends name the same binary scale as "GB" here.
+		s = s[:len(s)-2] + "B"
+		upper = strings.ToUpper(s)
+	}
+	for _, unit := range units {
+		if strings.HasSuffix(upper, unit) {
+			numPart := strings.TrimSpace(s[:len(s)-len(unit)])
+			n, err := strconv.ParseFloat(numPart, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid size %q", s)
+			}
+			return int64(n * float64(byteUnitScale(unit))), nil
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n, nil
+}
+
+func byteUnitScale(unit string) int64 {
+	const unitSize = 1024
+	switch unit {
+	case "B":
+		return 1
+	case "KB":
+		return unitSize
+	case "MB":
+		return unitSize * unitSize
+	case "GB":
+		return unitSize * unitSize * unitSize
+	case "TB":
+		return unitSize * unitSize * unitSize * unitSize
+	case "PB":
+		return unitSize * unitSize * unitSize * unitSize * unitSize
+	case "EB":
+		return unitSize * unitSize * unitSize * unitSize * unitSize * unitSize
+	default:
+		return 1
+	}
+}
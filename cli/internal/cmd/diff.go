@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/credlink/cli/internal/manifest"
+	"github.com/credlink/cli/internal/safepath"
 )
 
 // Diff command options
@@ -15,10 +18,27 @@ type DiffOptions struct {
 	ShowCerts      bool
 	ShowProvenance bool
 	Context        int
+	Format         string
+	ExitCode       bool
+	FailOn         string
+	Ignore         []string
+	Base           string
+	Recursive      bool
+	Parallel       int
 }
 
 var diffOpts DiffOptions
 
+// severityRank orders diff severities for --fail-on comparison: a diff
+// gates the pipeline when its derived severity ranks at or above the
+// configured floor.
+var severityRank = map[string]int{
+	"none":     0,
+	"minor":    1,
+	"major":    2,
+	"critical": 3,
+}
+
 func InitDiffCommand(rootCmd *cobra.Command) {
 	var diffCmd = &cobra.Command{
 		Use:   "diff <assetA> <assetB|manifest>",
@@ -28,7 +48,12 @@ func InitDiffCommand(rootCmd *cobra.Command) {
 - Signer/cert differences (thumbprints, validity)
 - Provenance graph differences (parent/variant links)
 
-Outputs human-readable summary and machine-readable JSON.`,
+Outputs human-readable summary and machine-readable JSON.
+
+With --recursive, the two arguments are directories or cloud prefixes
+instead of single assets: both sides are walked, paired by relative path,
+and diffed pair-by-pair across a --parallel worker pool, printing one
+record per pair (NDJSON under --json) followed by an aggregated summary.`,
 		Args: cobra.ExactArgs(2),
 		RunE: runDiffCommand,
 	}
@@ -38,6 +63,13 @@ Outputs human-readable summary and machine-readable JSON.`,
 	diffCmd.Flags().BoolVar(&diffOpts.ShowCerts, "certs", false, "Show certificate differences")
 	diffCmd.Flags().BoolVar(&diffOpts.ShowProvenance, "provenance", true, "Show provenance differences")
 	diffCmd.Flags().IntVar(&diffOpts.Context, "context", 3, "Context lines for differences")
+	diffCmd.Flags().StringVar(&diffOpts.Format, "format", "text", "Output format: text|unified|json|sarif|html")
+	diffCmd.Flags().BoolVar(&diffOpts.ExitCode, "exit-code", false, "Exit non-zero if the assets differ, like git diff --exit-code")
+	diffCmd.Flags().StringVar(&diffOpts.FailOn, "fail-on", "none", "Exit non-zero when severity is at or above this floor: none|minor|major|critical")
+	diffCmd.Flags().StringSliceVar(&diffOpts.Ignore, "ignore", nil, "Claim path to suppress before computing severity (e.g. c2pa.time); repeatable")
+	diffCmd.Flags().StringVar(&diffOpts.Base, "base", "", "Merge-base asset for a three-way diff: classifies each claim as unchanged, changed-in-A, changed-in-B, or conflicting relative to this common ancestor")
+	diffCmd.Flags().BoolVar(&diffOpts.Recursive, "recursive", false, "Treat the two arguments as directories/cloud prefixes and diff every paired asset beneath them")
+	diffCmd.Flags().IntVar(&diffOpts.Parallel, "parallel", 4, "Parallel diff workers for --recursive")
 
 	rootCmd.AddCommand(diffCmd)
 }
@@ -51,160 +83,515 @@ func runDiffCommand(cmd *cobra.Command, args []string) error {
 		PrintErrf("Input error: %v\n", err)
 		return err
 	}
+	if _, ok := severityRank[diffOpts.FailOn]; !ok {
+		err := fmt.Errorf("invalid --fail-on %q: must be one of none|minor|major|critical", diffOpts.FailOn)
+		PrintErrf("Input error: %v\n", err)
+		return ErrInputValidation
+	}
+	renderer, err := diffRendererFor(diffOpts.Format)
+	if err != nil {
+		PrintErrf("Input error: %v\n", err)
+		return ErrInputValidation
+	}
+	// --json keeps its historical meaning - machine-readable output -
+	// regardless of --format, the same precedence the flag had before
+	// --format grew json/sarif/html options of its own.
+	if globalOpts.JSON {
+		renderer = jsonRenderer{}
+	}
 
-	PrintMsgf("Comparing assets:\n  A: %s\n  B: %s\n", assetA, assetB)
+	if diffOpts.Recursive {
+		return runRecursiveDiff(cmd.Context(), assetA, assetB)
+	}
 
-	// Generate mock diff result
-	result := generateMockDiff(assetA, assetB)
+	if diffOpts.Base != "" {
+		if err := validateAssetPaths(diffOpts.Base); err != nil {
+			PrintErrf("Input error: %v\n", err)
+			return err
+		}
+	}
 
-	if globalOpts.JSON {
-		return PrintOutput(result)
+	if diffOpts.Base != "" {
+		PrintMsgf("Comparing assets:\n  Base: %s\n  A: %s\n  B: %s\n", diffOpts.Base, assetA, assetB)
 	} else {
-		return printHumanDiff(result)
+		PrintMsgf("Comparing assets:\n  A: %s\n  B: %s\n", assetA, assetB)
+	}
+
+	dr, err := computeDiff(cmd.Context(), assetA, assetB, diffOpts.Base)
+	if err != nil {
+		PrintErrf("Diff failed: %v\n", err)
+		return err
 	}
+
+	if err := renderer.Render(dr); err != nil {
+		return err
+	}
+
+	return diffExitGate(dr)
+}
+
+// diffExitGate decides whether the comparison just printed should exit
+// non-zero: --exit-code fires on any difference (like git diff
+// --exit-code), --fail-on fires once the derived severity reaches its
+// floor. Either can trigger independently.
+func diffExitGate(dr diffResult) error {
+	if diffOpts.ExitCode && !dr.Identical {
+		PrintErrf("Assets differ\n")
+		return ErrDiffFound
+	}
+	if severityRank[dr.Severity] >= severityRank[diffOpts.FailOn] && diffOpts.FailOn != "none" {
+		PrintErrf("Differences found (severity=%s) meet or exceed --fail-on %s\n", dr.Severity, diffOpts.FailOn)
+		return ErrDiffFound
+	}
+	return nil
 }
 
 func validateDiffInput(assetA, assetB string) error {
 	if assetA == "" || assetB == "" {
 		return fmt.Errorf("both assets must be specified")
 	}
+	return validateAssetPaths(assetA, assetB)
+}
 
-	// Validate cloud path formats and check for traversal
-	for _, asset := range []string{assetA, assetB} {
-		// Check for path traversal attempts in local paths
-		if !strings.HasPrefix(asset, "s3://") && !strings.HasPrefix(asset, "r2://") {
-			// Resolve to absolute path to detect traversal
-			absPath, err := filepath.Abs(asset)
-			if err != nil {
-				return fmt.Errorf("invalid path: %s", asset)
-			}
-
-			// Check for path traversal
-			if strings.Contains(asset, "..") {
-				// For relative paths, check if they go outside current directory
-				cwd, err := os.Getwd()
-				if err != nil {
-					return fmt.Errorf("cannot determine current directory")
-				}
-
-				// If the resolved path is not under current directory, it's traversal
-				if !strings.HasPrefix(absPath, cwd) {
-					return fmt.Errorf("path traversal detected in %s: access outside current directory not allowed", asset)
-				}
-			}
-
-			// Additional check for suspicious patterns
-			if strings.Contains(asset, "../") || strings.Contains(asset, "..\\") {
-				return fmt.Errorf("path traversal patterns not allowed in %s", asset)
+// validateAssetPaths applies the same path-traversal and cloud-path-shape
+// checks every other command delegates to safepath to each of assets -
+// shared by the two required positional assets, when set --base's
+// merge-base asset, and (one entry at a time) every pair --recursive's
+// walker discovers beneath a directory or cloud prefix.
+func validateAssetPaths(assets ...string) error {
+	for _, asset := range assets {
+		if _, _, hasScheme := strings.Cut(asset, "://"); !hasScheme {
+			if err := safepath.ValidateLocalPath(asset); err != nil {
+				return err
 			}
+			continue
 		}
-
-		// Validate cloud path format
-		if strings.HasPrefix(asset, "s3://") || strings.HasPrefix(asset, "r2://") {
-			var prefix string
-			if strings.HasPrefix(asset, "s3://") {
-				prefix = "s3://"
-			} else {
-				prefix = "r2://"
-			}
-			parts := strings.SplitN(strings.TrimPrefix(asset, prefix), "/", 2)
-			if len(parts) < 2 {
-				return fmt.Errorf("invalid cloud path format: %s", asset)
-			}
-			if parts[0] == "" {
-				return fmt.Errorf("bucket name cannot be empty in: %s", asset)
-			}
-			// Check for path traversal in cloud paths
-			if strings.Contains(parts[1], "..") {
-				return fmt.Errorf("path traversal not allowed in cloud path: %s", asset)
-			}
+		if _, err := safepath.ParseCloudPath(asset); err != nil {
+			return err
 		}
 	}
-
 	return nil
 }
 
-func generateMockDiff(assetA, assetB string) map[string]interface{} {
-	return map[string]interface{}{
+// diffResult bundles the printHumanDiff-compatible map with the raw Myers
+// edit scripts behind its ordered deltas, so --format unified can render
+// real hunks instead of re-deriving them from the flattened added/removed
+// lists.
+type diffResult struct {
+	Result     map[string]interface{}
+	ActionOps  []manifest.EditOp
+	ParentOps  []manifest.EditOp
+	VariantOps []manifest.EditOp
+	Identical  bool
+	Severity   string
+}
+
+// computeDiff loads both assets' embedded C2PA manifests (local or
+// s3://, r2://, gs://, oss://, azblob:// via manifest.Loader) and derives
+// the claim/certificate/provenance deltas printHumanDiff and
+// printUnifiedDiff render. When basePath is set, it additionally performs
+// a three-way comparison against basePath as the merge-base (see
+// computeMergeBase).
+func computeDiff(ctx context.Context, assetA, assetB, basePath string) (diffResult, error) {
+	loader := manifest.NewLoader()
+
+	mA, err := loader.Parse(ctx, assetA, manifest.Options{})
+	if err != nil {
+		return diffResult{}, fmt.Errorf("diff: parse %s: %w", assetA, err)
+	}
+	mB, err := loader.Parse(ctx, assetB, manifest.Options{})
+	if err != nil {
+		return diffResult{}, fmt.Errorf("diff: parse %s: %w", assetB, err)
+	}
+
+	ignore := ignoredClaimPaths()
+	claimsDelta, claimChanges := diffClaims(mA, mB, ignore)
+	certsDelta, certChanged := diffCertificates(mA, mB)
+	brokenChain := mA.VerifyError != "" || mB.VerifyError != ""
+	actionOps := manifest.DiffOrdered(mA.Actions, mB.Actions)
+	parentOps, variantOps := diffProvenanceOps(mA, mB)
+	provenanceDelta, provenanceChanges := provenanceDeltaFromOps(parentOps, variantOps)
+
+	differences := buildDifferences(claimsDelta, certChanged, actionOps)
+	total := claimChanges + provenanceChanges + len(differences)
+
+	severity := deriveSeverity(certChanged, brokenChain, claimsDelta, total)
+
+	result := map[string]interface{}{
 		"comparison": map[string]interface{}{
-			"asset_a":   assetA,
-			"asset_b":   assetB,
-			"timestamp": "2025-01-15T10:30:00Z",
+			"asset_a": assetA,
+			"asset_b": assetB,
 		},
 		"summary": map[string]interface{}{
-			"identical":   false,
-			"differences": 3,
-			"severity":    "minor",
+			"identical":   total == 0,
+			"differences": total,
+			"severity":    severity,
 		},
-		"differences":        generateMockDifferences(),
-		"claims_delta":       generateMockClaimsDelta(),
-		"certificates_delta": generateMockCertDelta(),
-		"provenance_delta":   generateMockProvenanceDelta(),
+		"differences":        differences,
+		"claims_delta":       claimsDelta,
+		"certificates_delta": certsDelta,
+		"provenance_delta":   provenanceDelta,
+	}
+
+	if basePath != "" {
+		mergeBase, ingredientConflicts, err := computeMergeBase(ctx, loader, basePath, mA, mB, ignore)
+		if err != nil {
+			return diffResult{}, err
+		}
+		result["merge_base"] = mergeBase
+		provenanceDelta["conflicts"] = ingredientConflicts
 	}
+
+	return diffResult{
+		Result:     result,
+		ActionOps:  actionOps,
+		ParentOps:  parentOps,
+		VariantOps: variantOps,
+		Identical:  total == 0,
+		Severity:   severity,
+	}, nil
 }
 
-func generateMockDifferences() []map[string]interface{} {
-	return []map[string]interface{}{
-		{
-			"type":        "claim_added",
-			"claim":       "c2pa.actions",
-			"description": "New signing action detected",
-		},
-		{
-			"type":        "hash_changed",
-			"algorithm":   "sha256",
-			"description": "Content hash differs",
-		},
-		{
-			"type": "timestamp_changed",
-			"old":  "2025-01-14T10:30:00Z",
-			"new":  "2025-01-15T10:30:00Z",
+// computeMergeBase loads basePath and classifies every claim label seen in
+// mA, mB, or the base manifest as unchanged, changed_in_a, changed_in_b,
+// or conflicting relative to it - the same three-way logic `git merge`
+// applies to a blob's lines, run over C2PA claim hashes instead. The
+// merge-base's own claim generator stands in for the LCA claim that a full
+// recursive walk of each ingredient DAG would otherwise have to compute,
+// since the caller is supplying it directly as the known common ancestor.
+// It also reports ingredient (provenance) nodes where A and B disagree
+// about the relationship an ingredient ID carries ("divergent variant
+// edges").
+func computeMergeBase(ctx context.Context, loader *manifest.Loader, basePath string, mA, mB *manifest.Manifest, ignore map[string]bool) (map[string]interface{}, []map[string]interface{}, error) {
+	mBase, err := loader.Parse(ctx, basePath, manifest.Options{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("diff: parse base %s: %w", basePath, err)
+	}
+
+	baseHashes := claimHashMap(mBase, ignore)
+	aHashes := claimHashMap(mA, ignore)
+	bHashes := claimHashMap(mB, ignore)
+
+	labels := make(map[string]bool, len(baseHashes)+len(aHashes)+len(bHashes))
+	for l := range baseHashes {
+		labels[l] = true
+	}
+	for l := range aHashes {
+		labels[l] = true
+	}
+	for l := range bHashes {
+		labels[l] = true
+	}
+
+	var changedInA, changedInB, conflicting []string
+	unchanged := 0
+	for label := range labels {
+		base, a, b := baseHashes[label], aHashes[label], bHashes[label]
+		switch {
+		case a == b:
+			unchanged++
+		case a == base:
+			changedInB = append(changedInB, label)
+		case b == base:
+			changedInA = append(changedInA, label)
+		default:
+			conflicting = append(conflicting, label)
+		}
+	}
+	sort.Strings(changedInA)
+	sort.Strings(changedInB)
+	sort.Strings(conflicting)
+
+	mergeBase := map[string]interface{}{
+		"base_asset": basePath,
+		"lca":        mBase.Generator,
+		"claims": map[string]interface{}{
+			"unchanged":    unchanged,
+			"changed_in_a": changedInA,
+			"changed_in_b": changedInB,
+			"conflicting":  conflicting,
 		},
 	}
+
+	return mergeBase, ingredientConflicts(mA, mB), nil
+}
+
+// ingredientConflicts finds every ingredient ID referenced by A and/or B
+// whose relationship ("parentOf", "componentOf", ...) disagrees between
+// the two - a derivative asset's variant edges that were resolved
+// differently on each branch.
+func ingredientConflicts(mA, mB *manifest.Manifest) []map[string]interface{} {
+	relA := make(map[string]string, len(mA.Ingredients))
+	for _, ing := range mA.Ingredients {
+		relA[ingredientID(ing)] = ing.Relationship
+	}
+	relB := make(map[string]string, len(mB.Ingredients))
+	for _, ing := range mB.Ingredients {
+		relB[ingredientID(ing)] = ing.Relationship
+	}
+
+	nodes := make(map[string]bool, len(relA)+len(relB))
+	for n := range relA {
+		nodes[n] = true
+	}
+	for n := range relB {
+		nodes[n] = true
+	}
+	sorted := make([]string, 0, len(nodes))
+	for n := range nodes {
+		sorted = append(sorted, n)
+	}
+	sort.Strings(sorted)
+
+	var conflicts []map[string]interface{}
+	for _, node := range sorted {
+		ra, rb := relA[node], relB[node]
+		if ra == rb {
+			continue
+		}
+		conflicts = append(conflicts, map[string]interface{}{
+			"node": node,
+			"in_a": relationshipOrAbsent(ra),
+			"in_b": relationshipOrAbsent(rb),
+		})
+	}
+	return conflicts
 }
 
-func generateMockClaimsDelta() map[string]interface{} {
-	return map[string]interface{}{
-		"added": []string{
-			"c2pa.actions",
-			"c2pa.thumbnail",
-		},
-		"removed": []string{
-			"c2pa.relationships",
-		},
-		"modified": []map[string]interface{}{
-			{
-				"claim":  "c2pa.hash.data",
-				"change": "hash_value_updated",
-			},
-		},
+func relationshipOrAbsent(relationship string) string {
+	if relationship == "" {
+		return "absent"
+	}
+	return relationship
+}
+
+// deriveSeverity classifies a diff per the CI gate's vocabulary
+// (none|minor|major|critical): a changed signer identity or a broken
+// signature/chain is critical (the provenance claim itself can't be
+// trusted), a removed or hash-modified claim is major (content-bearing
+// data changed), and anything else that differs is minor (metadata-only).
+func deriveSeverity(certChanged, brokenChain bool, claimsDelta map[string]interface{}, total int) string {
+	switch {
+	case certChanged || brokenChain:
+		return "critical"
+	case len(claimsDelta["removed"].([]string)) > 0 || len(claimsDelta["modified"].([]map[string]interface{})) > 0:
+		return "major"
+	case total > 0:
+		return "minor"
+	default:
+		return "none"
+	}
+}
+
+// ignoredClaimPaths normalizes --ignore into the bare claim labels
+// diffClaims compares against, accepting a jsonpath-flavored "$.claims.X"
+// or "claims.X" prefix for readability alongside the bare label.
+func ignoredClaimPaths() map[string]bool {
+	set := make(map[string]bool, len(diffOpts.Ignore))
+	for _, p := range diffOpts.Ignore {
+		p = strings.TrimPrefix(p, "$.")
+		p = strings.TrimPrefix(p, "claims.")
+		set[p] = true
 	}
+	return set
 }
 
-func generateMockCertDelta() map[string]interface{} {
+// diffClaims keyed-diffs the two manifests' assertions by label (claims
+// are an unordered set: what matters is which labels are present and
+// whether their hash changed, not declaration order), and returns the
+// printHumanDiff-shaped delta plus the number of added/removed/modified
+// claims. Labels in ignore are dropped from both sides first, so noisy
+// claims (e.g. timestamps) never surface as a difference or affect
+// severity.
+func diffClaims(mA, mB *manifest.Manifest, ignore map[string]bool) (map[string]interface{}, int) {
+	return finishClaimsDelta(claimHashMap(mA, ignore), claimHashMap(mB, ignore))
+}
+
+// claimHashMap projects a manifest's assertions into label -> hash,
+// dropping any label present in ignore. Shared by the two-way diffClaims
+// and computeMergeBase's three-way classification so both compare claims
+// the same way.
+func claimHashMap(m *manifest.Manifest, ignore map[string]bool) map[string]string {
+	out := make(map[string]string, len(m.Assertions))
+	for _, ar := range m.Assertions {
+		if label := claimLabel(ar.URI); !ignore[label] {
+			out[label] = ar.Hash
+		}
+	}
+	return out
+}
+
+func finishClaimsDelta(a, b map[string]string) (map[string]interface{}, int) {
+	added, removed, modified := manifest.DiffKeyed(a, b)
+
+	modifiedList := make([]map[string]interface{}, 0, len(modified))
+	for _, m := range modified {
+		modifiedList = append(modifiedList, map[string]interface{}{
+			"claim":  m.Key,
+			"change": "hash_value_updated",
+		})
+	}
+
 	return map[string]interface{}{
-		"signer_changed":  false,
-		"thumbprint_same": true,
+		"added":    added,
+		"removed":  removed,
+		"modified": modifiedList,
+	}, len(added) + len(removed) + len(modified)
+}
+
+// claimLabel extracts the trailing path component of a claim assertion's
+// JUMBF URL (e.g. "self#jumbf=c2pa.assertions/c2pa.hash.data" ->
+// "c2pa.hash.data"), mirroring manifest's own assertionLabel convention.
+func claimLabel(uri string) string {
+	if i := strings.LastIndexByte(uri, '/'); i >= 0 {
+		return uri[i+1:]
+	}
+	return uri
+}
+
+// diffCertificates compares each manifest's leaf (signer) certificate.
+func diffCertificates(mA, mB *manifest.Manifest) (map[string]interface{}, bool) {
+	var leafA, leafB manifest.CertSummary
+	if len(mA.Certificates) > 0 {
+		leafA = mA.Certificates[0]
+	}
+	if len(mB.Certificates) > 0 {
+		leafB = mB.Certificates[0]
+	}
+
+	signerChanged := leafA.Subject != leafB.Subject
+	thumbprintSame := leafA.Thumbprint == leafB.Thumbprint
+
+	delta := map[string]interface{}{
+		"signer_changed":  signerChanged,
+		"thumbprint_same": thumbprintSame,
 		"validity": map[string]interface{}{
-			"a_not_before": "2025-01-01T00:00:00Z",
-			"a_not_after":  "2026-01-01T00:00:00Z",
-			"b_not_before": "2025-01-01T00:00:00Z",
-			"b_not_after":  "2026-01-01T00:00:00Z",
+			"a_not_before": leafA.NotBefore,
+			"a_not_after":  leafA.NotAfter,
+			"b_not_before": leafB.NotBefore,
+			"b_not_after":  leafB.NotAfter,
 		},
 	}
+	return delta, signerChanged || !thumbprintSame
 }
 
-func generateMockProvenanceDelta() map[string]interface{} {
-	return map[string]interface{}{
+// diffProvenanceOps splits each manifest's ingredients into parent
+// ("parentOf") and variant (anything else, e.g. "componentOf") edges,
+// identified by DocumentID (falling back to InstanceID, then Title), and
+// Myers-diffs each list in declaration order.
+func diffProvenanceOps(mA, mB *manifest.Manifest) (parentOps, variantOps []manifest.EditOp) {
+	parentA, variantA := splitIngredients(mA.Ingredients)
+	parentB, variantB := splitIngredients(mB.Ingredients)
+	return manifest.DiffOrdered(parentA, parentB), manifest.DiffOrdered(variantA, variantB)
+}
+
+func splitIngredients(ingredients []manifest.Ingredient) (parents, variants []string) {
+	for _, ing := range ingredients {
+		id := ingredientID(ing)
+		if ing.Relationship == "parentOf" {
+			parents = append(parents, id)
+		} else {
+			variants = append(variants, id)
+		}
+	}
+	return parents, variants
+}
+
+func ingredientID(ing manifest.Ingredient) string {
+	switch {
+	case ing.DocumentID != "":
+		return ing.DocumentID
+	case ing.InstanceID != "":
+		return ing.InstanceID
+	default:
+		return ing.Title
+	}
+}
+
+// provenanceDeltaFromOps flattens Myers edit scripts back into the
+// added/removed shape printProvenanceLinks expects, and reports how many
+// edges actually changed.
+func provenanceDeltaFromOps(parentOps, variantOps []manifest.EditOp) (map[string]interface{}, int) {
+	parentAdded, parentRemoved := opsToAddedRemoved(parentOps)
+	variantAdded, variantRemoved := opsToAddedRemoved(variantOps)
+
+	delta := map[string]interface{}{
 		"parent_links": map[string]interface{}{
-			"added":   []string{"urn:uuid:parent-123"},
-			"removed": []string{},
+			"added":   parentAdded,
+			"removed": parentRemoved,
 		},
 		"variant_links": map[string]interface{}{
-			"added":   []string{"urn:uuid:variant-456"},
-			"removed": []string{"urn:uuid:variant-789"},
+			"added":   variantAdded,
+			"removed": variantRemoved,
 		},
 	}
+	changes := len(parentAdded) + len(parentRemoved) + len(variantAdded) + len(variantRemoved)
+	return delta, changes
+}
+
+func opsToAddedRemoved(ops []manifest.EditOp) (added, removed []string) {
+	for _, op := range ops {
+		switch op.Op {
+		case manifest.OpInsert:
+			added = append(added, op.Value)
+		case manifest.OpDelete:
+			removed = append(removed, op.Value)
+		}
+	}
+	return added, removed
+}
+
+// buildDifferences projects the structured deltas into the flat
+// type/description list printHumanDiff's "Detailed Differences" section
+// walks.
+func buildDifferences(claimsDelta map[string]interface{}, certChanged bool, actionOps []manifest.EditOp) []map[string]interface{} {
+	var diffs []map[string]interface{}
+
+	for _, c := range claimsDelta["added"].([]string) {
+		diffs = append(diffs, map[string]interface{}{
+			"type":        "claim_added",
+			"claim":       c,
+			"description": fmt.Sprintf("New claim assertion %s", c),
+		})
+	}
+	for _, c := range claimsDelta["removed"].([]string) {
+		diffs = append(diffs, map[string]interface{}{
+			"type":        "claim_removed",
+			"claim":       c,
+			"description": fmt.Sprintf("Claim assertion %s no longer present", c),
+		})
+	}
+	for _, m := range claimsDelta["modified"].([]map[string]interface{}) {
+		diffs = append(diffs, map[string]interface{}{
+			"type":        "hash_changed",
+			"claim":       m["claim"],
+			"description": fmt.Sprintf("Content hash differs for %s", m["claim"]),
+		})
+	}
+	if certChanged {
+		diffs = append(diffs, map[string]interface{}{
+			"type":        "signer_changed",
+			"description": "Signer certificate differs between assets",
+		})
+	}
+	for _, op := range actionOps {
+		switch op.Op {
+		case manifest.OpInsert:
+			diffs = append(diffs, map[string]interface{}{
+				"type":        "action_added",
+				"action":      op.Value,
+				"description": fmt.Sprintf("New action %s recorded", op.Value),
+			})
+		case manifest.OpDelete:
+			diffs = append(diffs, map[string]interface{}{
+				"type":        "action_removed",
+				"action":      op.Value,
+				"description": fmt.Sprintf("Action %s no longer recorded", op.Value),
+			})
+		}
+	}
+
+	return diffs
 }
 
 func printHumanDiff(result map[string]interface{}) error {
@@ -251,9 +638,37 @@ func printHumanDiff(result map[string]interface{}) error {
 		}
 	}
 
+	if mergeBase, ok := result["merge_base"].(map[string]interface{}); ok {
+		printMergeBase(mergeBase, result["provenance_delta"])
+	}
+
 	return nil
 }
 
+// printMergeBase renders the three-way classification --base adds: each
+// claim's status relative to the merge-base, and any ingredient nodes
+// whose relationship diverged between A and B.
+func printMergeBase(mergeBase map[string]interface{}, provenanceDelta interface{}) {
+	fmt.Println("\n=== Merge-Base (Three-Way) ===")
+	fmt.Printf("Base: %v\n", mergeBase["base_asset"])
+	fmt.Printf("LCA claim generator: %v\n", mergeBase["lca"])
+
+	if claims, ok := mergeBase["claims"].(map[string]interface{}); ok {
+		fmt.Printf("Unchanged: %v\n", claims["unchanged"])
+		printStringList("Changed in A:", claims["changed_in_a"])
+		printStringList("Changed in B:", claims["changed_in_b"])
+		printStringList("Conflicting:", claims["conflicting"])
+	}
+
+	if prov, ok := provenanceDelta.(map[string]interface{}); ok {
+		conflicts, _ := prov["conflicts"].([]map[string]interface{})
+		fmt.Println("  Provenance Conflicts:")
+		for _, c := range conflicts {
+			fmt.Printf("    - %s: A=%s B=%s\n", c["node"], c["in_a"], c["in_b"])
+		}
+	}
+}
+
 func printStringList(label string, items interface{}) {
 	fmt.Printf("  %s\n", label)
 	if list, ok := items.([]string); ok {
@@ -278,3 +693,126 @@ func printProvenanceLinks(links interface{}) {
 		printStringList("    Removed:", linkMap["removed"])
 	}
 }
+
+// printUnifiedDiff renders --format unified: a patch-like hunk view per
+// ordered delta (actions, provenance edges), windowed by --context, plus a
+// flat +/- listing for the unordered claim set a hunk header wouldn't mean
+// anything for.
+func printUnifiedDiff(dr diffResult) error {
+	printUnifiedHunks("assertions/c2pa.actions", dr.ActionOps, diffOpts.Context)
+	printUnifiedHunks("provenance/parent_links", dr.ParentOps, diffOpts.Context)
+	printUnifiedHunks("provenance/variant_links", dr.VariantOps, diffOpts.Context)
+
+	if claims, ok := dr.Result["claims_delta"].(map[string]interface{}); ok {
+		printUnifiedClaims(claims)
+	}
+
+	return nil
+}
+
+// unifiedHunk is one contiguous window of ops bounded by --context equal
+// entries on either side, plus the 1-based (start, length) position each
+// side would report in a real unified diff header.
+type unifiedHunk struct {
+	ops                        []manifest.EditOp
+	aStart, aLen, bStart, bLen int
+}
+
+// groupUnifiedHunks walks ops once, tracking each side's running position,
+// and groups each contiguous run of non-equal ops with up to context equal
+// ops of padding on either side - the same windowing `diff -U` applies.
+func groupUnifiedHunks(ops []manifest.EditOp, context int) []unifiedHunk {
+	if context < 0 {
+		context = 0
+	}
+	n := len(ops)
+	posA := make([]int, n+1)
+	posB := make([]int, n+1)
+	for i, op := range ops {
+		posA[i+1], posB[i+1] = posA[i], posB[i]
+		switch op.Op {
+		case manifest.OpEqual:
+			posA[i+1]++
+			posB[i+1]++
+		case manifest.OpDelete:
+			posA[i+1]++
+		case manifest.OpInsert:
+			posB[i+1]++
+		}
+	}
+
+	var hunks []unifiedHunk
+	i := 0
+	for i < n {
+		if ops[i].Op == manifest.OpEqual {
+			i++
+			continue
+		}
+		start := i
+		for start > 0 && i-start < context && ops[start-1].Op == manifest.OpEqual {
+			start--
+		}
+		end := i
+		for end < n && ops[end].Op != manifest.OpEqual {
+			end++
+		}
+		trailing := end
+		for trailing < n && trailing-end < context && ops[trailing].Op == manifest.OpEqual {
+			trailing++
+		}
+		hunks = append(hunks, unifiedHunk{
+			ops:    ops[start:trailing],
+			aStart: posA[start],
+			aLen:   posA[trailing] - posA[start],
+			bStart: posB[start],
+			bLen:   posB[trailing] - posB[start],
+		})
+		i = trailing
+	}
+	return hunks
+}
+
+func printUnifiedHunks(label string, ops []manifest.EditOp, context int) {
+	hunks := groupUnifiedHunks(ops, context)
+	if len(hunks) == 0 {
+		return
+	}
+	fmt.Printf("--- a/%s\n+++ b/%s\n", label, label)
+	for _, h := range hunks {
+		fmt.Printf("@@ -%d,%d +%d,%d @@\n", h.aStart+1, h.aLen, h.bStart+1, h.bLen)
+		for _, op := range h.ops {
+			switch op.Op {
+			case manifest.OpEqual:
+				fmt.Printf(" %s\n", op.Value)
+			case manifest.OpDelete:
+				fmt.Printf("-%s\n", op.Value)
+			case manifest.OpInsert:
+				fmt.Printf("+%s\n", op.Value)
+			}
+		}
+	}
+}
+
+// printUnifiedClaims renders the keyed claim delta as a flat +/- list:
+// claims are an unordered set, so an @@ position header wouldn't mean
+// anything, but the +/- convention still reads as a patch.
+func printUnifiedClaims(claims map[string]interface{}) {
+	added, _ := claims["added"].([]string)
+	removed, _ := claims["removed"].([]string)
+	modified, _ := claims["modified"].([]map[string]interface{})
+	if len(added) == 0 && len(removed) == 0 && len(modified) == 0 {
+		return
+	}
+
+	fmt.Println("--- a/claims\n+++ b/claims")
+	for _, c := range removed {
+		fmt.Printf("-%s\n", c)
+	}
+	for _, m := range modified {
+		fmt.Printf("-%s (%v)\n", m["claim"], m["change"])
+		fmt.Printf("+%s (%v)\n", m["claim"], m["change"])
+	}
+	for _, c := range added {
+		fmt.Printf("+%s\n", c)
+	}
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/credlink/cli/pkg/storage"
+)
+
+// recursivePair is one relative path present on at least one side of a
+// --recursive diff. A path missing from one side can't be diffed, only
+// reported as only_a/only_b.
+type recursivePair struct {
+	RelKey string
+	InA    bool
+	InB    bool
+}
+
+// recursiveRecord is one pair's outcome, the unit --json streams as a
+// single NDJSON line so a downstream tool can start consuming results
+// before the whole corpus finishes.
+type recursiveRecord struct {
+	Path      string      `json:"path"`
+	Status    string      `json:"status"` // "identical", "differ", "only_a", "only_b", "error"
+	Identical bool        `json:"identical,omitempty"`
+	Severity  string      `json:"severity,omitempty"`
+	Result    interface{} `json:"result,omitempty"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// recursiveSummary aggregates a full --recursive pass. It's emitted last,
+// after every per-pair record, so a streaming consumer can fold the run
+// into one number without buffering the records itself.
+type recursiveSummary struct {
+	TotalPairs int            `json:"total_pairs"`
+	Identical  int            `json:"identical"`
+	OnlyA      int            `json:"only_a"`
+	OnlyB      int            `json:"only_b"`
+	Errors     int            `json:"errors"`
+	Severity   map[string]int `json:"severity_histogram"`
+}
+
+// runRecursiveDiff walks dirA and dirB (local directories or cloud
+// prefixes, mirror-style), pairs their entries by relative path, and
+// diffs each pair across a --parallel worker pool. --base/--format
+// unified don't apply here - a three-way or hunk view only makes sense
+// for a single pair - so --recursive ignores them.
+func runRecursiveDiff(ctx context.Context, dirA, dirB string) error {
+	backendA, prefixA, err := storage.Factory(dirA)
+	if err != nil {
+		PrintErrf("Input error: %v\n", err)
+		return ErrInputValidation
+	}
+	backendB, prefixB, err := storage.Factory(dirB)
+	if err != nil {
+		PrintErrf("Input error: %v\n", err)
+		return ErrInputValidation
+	}
+
+	indexA, err := indexBackend(ctx, backendA, prefixA)
+	if err != nil {
+		PrintErrf("Diff failed: list %s: %v\n", dirA, err)
+		return ErrInputValidation
+	}
+	indexB, err := indexBackend(ctx, backendB, prefixB)
+	if err != nil {
+		PrintErrf("Diff failed: list %s: %v\n", dirB, err)
+		return ErrInputValidation
+	}
+
+	pairs := pairRecursiveEntries(indexA, indexB)
+	PrintMsgf("Diffing %d paired asset(s): %s <-> %s\n", len(pairs), dirA, dirB)
+
+	// A Ctrl-C mid-run should stop dispatching new pairs instead of
+	// grinding through a corpus the operator already gave up on, the same
+	// as mirror --parallel.
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	parallel := diffOpts.Parallel
+	if parallel <= 0 {
+		parallel = 1
+	}
+	progress := newProgressBar(len(pairs), isTerminal(os.Stderr))
+
+	jobs := make(chan recursivePair)
+	records := make(chan recursiveRecord)
+
+	var wg sync.WaitGroup
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pair := range jobs {
+				rec := diffRecursivePair(ctx, dirA, pair, dirB)
+				select {
+				case records <- rec:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, pair := range pairs {
+			select {
+			case jobs <- pair:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(records)
+	}()
+
+	encoder := json.NewEncoder(os.Stdout)
+	summary := recursiveSummary{Severity: map[string]int{}}
+
+	for rec := range records {
+		summary.TotalPairs++
+		switch rec.Status {
+		case "error":
+			summary.Errors++
+		case "only_a":
+			summary.OnlyA++
+		case "only_b":
+			summary.OnlyB++
+		default:
+			if rec.Identical {
+				summary.Identical++
+			}
+			if rec.Severity != "" {
+				summary.Severity[rec.Severity]++
+			}
+		}
+
+		if globalOpts.JSON {
+			if err := encoder.Encode(rec); err != nil {
+				return fmt.Errorf("diff: encode record for %s: %w", rec.Path, err)
+			}
+		} else {
+			printRecursiveRecordHuman(rec)
+		}
+
+		progress.update(summary.TotalPairs, summary.Identical, summary.Errors)
+	}
+	progress.finish(ctx.Err() != nil)
+
+	if globalOpts.JSON {
+		if err := encoder.Encode(summary); err != nil {
+			return fmt.Errorf("diff: encode summary: %w", err)
+		}
+	} else {
+		printRecursiveSummaryHuman(summary)
+	}
+
+	if diffOpts.ExitCode && summary.Identical != summary.TotalPairs {
+		PrintErrf("Assets differ\n")
+		return ErrDiffFound
+	}
+	if worst := worstSeverity(summary.Severity); diffOpts.FailOn != "none" && severityRank[worst] >= severityRank[diffOpts.FailOn] {
+		PrintErrf("Differences found (severity=%s) meet or exceed --fail-on %s\n", worst, diffOpts.FailOn)
+		return ErrDiffFound
+	}
+	if summary.Errors > 0 {
+		return ErrPartialFailure
+	}
+	return nil
+}
+
+// pairRecursiveEntries matches both sides' listings by relative path - the
+// comparison key indexBackend already produces for mirror - and sorts the
+// result so a run is reproducible regardless of listing order.
+func pairRecursiveEntries(indexA, indexB map[string]storage.ObjectMeta) []recursivePair {
+	keys := make(map[string]bool, len(indexA)+len(indexB))
+	for k := range indexA {
+		keys[k] = true
+	}
+	for k := range indexB {
+		keys[k] = true
+	}
+
+	pairs := make([]recursivePair, 0, len(keys))
+	for k := range keys {
+		_, inA := indexA[k]
+		_, inB := indexB[k]
+		pairs = append(pairs, recursivePair{RelKey: k, InA: inA, InB: inB})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].RelKey < pairs[j].RelKey })
+	return pairs
+}
+
+// diffRecursivePair resolves a pair to the two asset paths computeDiff
+// expects and runs the ordinary two-way diff against them, re-running
+// validateAssetPaths on each discovered entry so a malicious relative key
+// (e.g. an object key containing "..") is rejected the same as a
+// hand-typed positional argument would be.
+func diffRecursivePair(ctx context.Context, dirA string, pair recursivePair, dirB string) recursiveRecord {
+	rec := recursiveRecord{Path: pair.RelKey}
+
+	switch {
+	case !pair.InB:
+		rec.Status = "only_a"
+		return rec
+	case !pair.InA:
+		rec.Status = "only_b"
+		return rec
+	}
+
+	assetA := joinRecursivePath(dirA, pair.RelKey)
+	assetB := joinRecursivePath(dirB, pair.RelKey)
+
+	if err := validateAssetPaths(assetA, assetB); err != nil {
+		rec.Status = "error"
+		rec.Error = err.Error()
+		return rec
+	}
+
+	dr, err := computeDiff(ctx, assetA, assetB, "")
+	if err != nil {
+		rec.Status = "error"
+		rec.Error = err.Error()
+		return rec
+	}
+
+	rec.Identical = dr.Identical
+	rec.Severity = dr.Severity
+	rec.Result = dr.Result
+	if dr.Identical {
+		rec.Status = "identical"
+	} else {
+		rec.Status = "differ"
+	}
+	return rec
+}
+
+// joinRecursivePath rejoins a relative key stripped by indexBackend back
+// onto root, local-path-style for a plain directory or URI-style (root
+// already ends in the trailing "/" of its prefix, or doesn't) for a cloud
+// prefix.
+func joinRecursivePath(root, rel string) string {
+	if _, _, hasScheme := strings.Cut(root, "://"); hasScheme {
+		if strings.HasSuffix(root, "/") {
+			return root + rel
+		}
+		return root + "/" + rel
+	}
+	return filepath.Join(root, rel)
+}
+
+// worstSeverity reports the highest-ranked severity with at least one
+// occurrence in histogram, or "none" if it's empty.
+func worstSeverity(histogram map[string]int) string {
+	worst := "none"
+	for sev, n := range histogram {
+		if n > 0 && severityRank[sev] > severityRank[worst] {
+			worst = sev
+		}
+	}
+	return worst
+}
+
+func printRecursiveRecordHuman(rec recursiveRecord) {
+	switch rec.Status {
+	case "only_a":
+		fmt.Printf("- %s (only in A)\n", rec.Path)
+	case "only_b":
+		fmt.Printf("+ %s (only in B)\n", rec.Path)
+	case "error":
+		fmt.Printf("! %s: %s\n", rec.Path, rec.Error)
+	case "identical":
+		fmt.Printf("= %s\n", rec.Path)
+	default:
+		fmt.Printf("* %s (severity=%s)\n", rec.Path, rec.Severity)
+	}
+}
+
+func printRecursiveSummaryHuman(summary recursiveSummary) {
+	fmt.Println("\n=== Recursive Diff Summary ===")
+	fmt.Printf("Pairs: %d  Identical: %d  Only-A: %d  Only-B: %d  Errors: %d\n",
+		summary.TotalPairs, summary.Identical, summary.OnlyA, summary.OnlyB, summary.Errors)
+	for _, sev := range []string{"critical", "major", "minor"} {
+		if n := summary.Severity[sev]; n > 0 {
+			fmt.Printf("  %s: %d\n", sev, n)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+)
+
+// diffRenderer turns a computed diffResult into the bytes `diff` actually
+// prints, one implementation per --format value. Introduced so sarif/html
+// could be added alongside the original text/json/unified paths without
+// runDiffCommand growing another format-specific branch.
+type diffRenderer interface {
+	Render(dr diffResult) error
+}
+
+// diffRendererFor resolves --format to its renderer, the single place new
+// formats get registered.
+func diffRendererFor(format string) (diffRenderer, error) {
+	switch format {
+	case "", "text":
+		return textRenderer{}, nil
+	case "unified":
+		return unifiedRenderer{}, nil
+	case "json":
+		return jsonRenderer{}, nil
+	case "sarif":
+		return sarifRenderer{}, nil
+	case "html":
+		return htmlRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("invalid --format %q: must be one of text|unified|json|sarif|html", format)
+	}
+}
+
+// textRenderer is the original human-readable summary.
+type textRenderer struct{}
+
+func (textRenderer) Render(dr diffResult) error { return printHumanDiff(dr.Result) }
+
+// unifiedRenderer is the original patch-style hunk view.
+type unifiedRenderer struct{}
+
+func (unifiedRenderer) Render(dr diffResult) error { return printUnifiedDiff(dr) }
+
+// jsonRenderer is the original --json machine-readable shape, now also
+// reachable as --format json for symmetry with sarif/html.
+type jsonRenderer struct{}
+
+func (jsonRenderer) Render(dr diffResult) error { return PrintOutput(dr.Result) }
+
+// sarifRenderer projects the flat "differences" list buildDifferences
+// already computes into a SARIF 2.1.0 log, so provenance regressions show
+// up natively as code-scanning results in GitHub/GitLab rather than
+// needing a custom parser for the plain JSON shape.
+type sarifRenderer struct{}
+
+func (sarifRenderer) Render(dr diffResult) error {
+	assetB, _ := dr.Result["comparison"].(map[string]interface{})["asset_b"].(string)
+
+	diffs, _ := dr.Result["differences"].([]map[string]interface{})
+	rules := make(map[string]bool)
+	results := make([]sarifResult, 0, len(diffs))
+	for _, d := range diffs {
+		ruleID, _ := d["type"].(string)
+		message, _ := d["description"].(string)
+		rules[ruleID] = true
+		results = append(results, sarifResult{
+			RuleID:  ruleID,
+			Level:   sarifLevel(ruleID),
+			Message: sarifText{Text: message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: assetB},
+				},
+			}},
+		})
+	}
+
+	ruleList := make([]sarifRule, 0, len(rules))
+	for id := range rules {
+		ruleList = append(ruleList, sarifRule{ID: id, ShortDescription: sarifText{Text: id}})
+	}
+
+	log := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "credlink-diff",
+				InformationURI: "https://credlink.com",
+				Rules:          ruleList,
+			}},
+			Results: results,
+		}},
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
+
+// sarifLevel maps a diff finding's type to a SARIF result level: a
+// removed claim, a hash change, or a signer change mean the provenance
+// claim itself can no longer be trusted as-is (error); everything else
+// (an added claim or action) is informational (warning).
+func sarifLevel(ruleID string) string {
+	switch ruleID {
+	case "claim_removed", "hash_changed", "signer_changed":
+		return "error"
+	default:
+		return "warning"
+	}
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string    `json:"id"`
+	ShortDescription sarifText `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifText       `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifText struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// htmlRenderer renders a self-contained report (inline CSS, no external
+// assets) with a collapsible <details> section per delta category and a
+// side-by-side claims table - something to open straight from a browser
+// or attach to a PR, without the reader needing the CLI installed.
+// html/template (not text/template) autoescapes every value, since claim
+// labels/descriptions ultimately come from an untrusted asset's embedded
+// manifest.
+type htmlRenderer struct{}
+
+func (htmlRenderer) Render(dr diffResult) error {
+	return diffHTMLTemplate.Execute(os.Stdout, dr.Result)
+}
+
+var diffHTMLTemplate = template.Must(template.New("diff").Parse(diffHTMLTemplateSrc))
+
+const diffHTMLTemplateSrc = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>CredLink Diff Report</title>
+<style>
+  body { font-family: -apple-system, Helvetica, Arial, sans-serif; margin: 2rem; color: #1a1a1a; }
+  .severity-critical { color: #b91c1c; }
+  .severity-major { color: #b45309; }
+  .severity-minor { color: #555; }
+  .severity-none { color: #15803d; }
+  table { border-collapse: collapse; width: 100%; margin: 0.5rem 0 1.5rem; }
+  td, th { border: 1px solid #ddd; padding: 4px 8px; text-align: left; font-size: 0.9em; }
+  details { margin: 0.75rem 0; }
+  summary { cursor: pointer; font-weight: 600; }
+  code { background: #f5f5f5; padding: 1px 4px; }
+</style>
+</head>
+<body>
+<h1>CredLink Diff Report</h1>
+<p>A: <code>{{.comparison.asset_a}}</code><br>B: <code>{{.comparison.asset_b}}</code></p>
+<p>
+  Identical: <strong>{{.summary.identical}}</strong> &middot;
+  Differences: <strong>{{.summary.differences}}</strong> &middot;
+  Severity: <strong class="severity-{{.summary.severity}}">{{.summary.severity}}</strong>
+</p>
+
+<details open>
+<summary>Claims</summary>
+<table>
+<tr><th>Claim</th><th>A</th><th>B</th></tr>
+{{range .claims_delta.removed}}<tr><td>{{.}}</td><td>present</td><td>&mdash;</td></tr>{{end}}
+{{range .claims_delta.added}}<tr><td>{{.}}</td><td>&mdash;</td><td>present</td></tr>{{end}}
+{{range .claims_delta.modified}}<tr><td>{{.claim}}</td><td colspan="2">{{.change}}</td></tr>{{end}}
+</table>
+</details>
+
+<details>
+<summary>Certificates</summary>
+<table>
+{{range $k, $v := .certificates_delta}}<tr><td>{{$k}}</td><td>{{$v}}</td></tr>{{end}}
+</table>
+</details>
+
+<details open>
+<summary>Provenance</summary>
+<table>
+<tr><th>Edge set</th><th>Added</th><th>Removed</th></tr>
+<tr><td>Parent links</td><td>{{.provenance_delta.parent_links.added}}</td><td>{{.provenance_delta.parent_links.removed}}</td></tr>
+<tr><td>Variant links</td><td>{{.provenance_delta.variant_links.added}}</td><td>{{.provenance_delta.variant_links.removed}}</td></tr>
+</table>
+</details>
+
+<details open>
+<summary>Detailed Differences</summary>
+<table>
+<tr><th>Type</th><th>Description</th></tr>
+{{range .differences}}<tr><td>{{.type}}</td><td>{{.description}}</td></tr>{{end}}
+</table>
+</details>
+</body>
+</html>
+`
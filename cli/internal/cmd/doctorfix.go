@@ -0,0 +1,415 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/credlink/cli/internal/cache"
+)
+
+// Fixer is a remediation for one known CheckResult.Name. Plan describes
+// what Apply would do without doing it (what `doctor --plan` prints); Apply
+// performs one step; Verify re-runs the underlying check so the report
+// reflects whatever the fix actually changed. Every Fixer must be
+// idempotent - applying it against an already-fixed environment is a no-op,
+// not an error.
+type Fixer interface {
+	Plan() []FixStep
+	Apply(step FixStep) error
+	Verify() CheckResult
+}
+
+// FixStep is one action a Fixer proposes, descriptive enough to print under
+// --plan without actually applying anything.
+type FixStep struct {
+	Description string `json:"description"`
+	Command     string `json:"command,omitempty"`
+}
+
+// FixOutcome records what happened when `doctor --fix`/`--plan` ran one
+// check's Fixer: the plan, whether it was applied, and the re-verified
+// CheckResult so a caller can tell "Apply returned nil" from "the problem is
+// actually gone".
+type FixOutcome struct {
+	CheckName string      `json:"check_name"`
+	Steps     []FixStep   `json:"steps"`
+	Applied   bool        `json:"applied"`
+	Error     string      `json:"error,omitempty"`
+	Verify    CheckResult `json:"verify"`
+}
+
+// FixReport is the machine-readable record `doctor --fix`/`--plan` emits
+// alongside DoctorReport, so automation can gate a deploy on whether a fix
+// actually took rather than just that Apply didn't error.
+type FixReport struct {
+	Outcomes []FixOutcome `json:"outcomes"`
+}
+
+// doctorFixers maps a CheckResult.Name to the Fixer that remediates it.
+// Registering a name here is what makes a CanFix: true result actionable
+// under --fix/--plan; an unregistered CanFix check is reported as such but
+// has nothing automated to run.
+var doctorFixers = map[string]func() Fixer{
+	"Windows Long Paths": func() Fixer { return &windowsLongPathFixer{} },
+	"Cache Directory":    func() Fixer { return &cacheDirectoryFixer{cacheDir: getCacheDir()} },
+	"Environment Variables": func() Fixer {
+		return &envVarFixer{vars: []string{"PATH", "HOME", "USER"}}
+	},
+	"System Certificates": func() Fixer { return &certificateChainFixer{host: doctorProbeHost} },
+}
+
+// runDoctorFixes plans (and, unless planOnly, applies after confirmation)
+// the registered Fixer for every CanFix check in report, updating each
+// fixed check in place with its post-Verify result so the caller's next
+// calculateSummary/printDoctorReport sees the fix's effect.
+func runDoctorFixes(report *DoctorReport, planOnly, assumeYes bool) FixReport {
+	var fixReport FixReport
+
+	for i := range report.Checks {
+		check := &report.Checks[i]
+		if !check.CanFix {
+			continue
+		}
+
+		newFixer, ok := doctorFixers[check.Name]
+		if !ok {
+			continue
+		}
+		fixer := newFixer()
+
+		outcome := FixOutcome{CheckName: check.Name, Steps: fixer.Plan()}
+
+		if !planOnly && (assumeYes || confirmFixSteps(check.Name, outcome.Steps)) {
+			outcome.Applied = true
+			for _, step := range outcome.Steps {
+				if err := fixer.Apply(step); err != nil {
+					outcome.Error = err.Error()
+					break
+				}
+			}
+			outcome.Verify = fixer.Verify()
+			*check = outcome.Verify
+		}
+
+		fixReport.Outcomes = append(fixReport.Outcomes, outcome)
+	}
+
+	return fixReport
+}
+
+// confirmFixSteps prompts once per check (not once per step), printing
+// every step so the user knows what they're approving before a single
+// yes/no answer applies all of them.
+func confirmFixSteps(checkName string, steps []FixStep) bool {
+	fmt.Printf("   Apply %d fix step(s) for %q?\n", len(steps), checkName)
+	for _, step := range steps {
+		fmt.Printf("     - %s\n", step.Description)
+	}
+	fmt.Print("   Proceed? [y/N] ")
+
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// findFixOutcome looks up checkName's outcome in report, or nil if report is
+// nil (no --fix/--plan requested) or the check has no registered Fixer.
+func findFixOutcome(report *FixReport, checkName string) *FixOutcome {
+	if report == nil {
+		return nil
+	}
+	for i := range report.Outcomes {
+		if report.Outcomes[i].CheckName == checkName {
+			return &report.Outcomes[i]
+		}
+	}
+	return nil
+}
+
+// printFixOutcome renders one FixOutcome under a check in the human-readable
+// report: the plan alone under --plan, or the apply result (skipped,
+// failed, or applied-and-reverified) otherwise.
+func printFixOutcome(outcome FixOutcome, planOnly bool) {
+	if planOnly {
+		fmt.Printf("   Plan:\n")
+		for _, step := range outcome.Steps {
+			fmt.Printf("     - %s\n", step.Description)
+		}
+		return
+	}
+
+	if !outcome.Applied {
+		fmt.Printf("   Fix skipped (not confirmed)\n")
+		return
+	}
+	if outcome.Error != "" {
+		fmt.Printf("   Fix failed: %s\n", outcome.Error)
+		return
+	}
+	fmt.Printf("   Fix applied; re-checked as %s: %s\n", outcome.Verify.Status, outcome.Verify.Message)
+}
+
+// cacheDirectoryFixer creates the cache directory with the same perms
+// cache.Open uses and writes a sentinel file confirming it's writable.
+type cacheDirectoryFixer struct {
+	cacheDir string
+}
+
+const cacheDirectorySentinelName = ".doctor-sentinel"
+
+func (f *cacheDirectoryFixer) Plan() []FixStep {
+	return []FixStep{{
+		Description: fmt.Sprintf("Create cache directory %s", f.cacheDir),
+		Command:     fmt.Sprintf("mkdir -p %s", f.cacheDir),
+	}}
+}
+
+func (f *cacheDirectoryFixer) Apply(step FixStep) error {
+	if err := os.MkdirAll(f.cacheDir, 0o755); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+	sentinel := filepath.Join(f.cacheDir, cacheDirectorySentinelName)
+	if err := os.WriteFile(sentinel, []byte("written by `credlink doctor --fix`\n"), 0o644); err != nil {
+		return fmt.Errorf("write sentinel file: %w", err)
+	}
+	return nil
+}
+
+func (f *cacheDirectoryFixer) Verify() CheckResult {
+	result := CheckResult{Name: "Cache Directory"}
+
+	stat, err := os.Stat(f.cacheDir)
+	switch {
+	case os.IsNotExist(err):
+		result.Status = "warning"
+		result.Message = "Cache directory does not exist"
+		result.Details = f.cacheDir
+		result.Suggestion = "Cache directory will be created on first use"
+		result.CanFix = true
+		return result
+	case err != nil:
+		result.Status = "error"
+		result.Message = "Cannot access cache directory"
+		result.Details = err.Error()
+		return result
+	case !stat.IsDir():
+		result.Status = "error"
+		result.Message = "Cache path is not a directory"
+		return result
+	}
+
+	result.Status = "ok"
+	result.Message = fmt.Sprintf("Cache directory accessible: %s", f.cacheDir)
+
+	if c, err := cache.Open(f.cacheDir); err == nil {
+		if ok, unlock, err := c.TryRLock(); err == nil {
+			if ok {
+				unlock()
+			} else {
+				result.Status = "warning"
+				result.Message = "Cache directory is locked by another process"
+				result.Suggestion = "A stuck `cache prune`/`cache clear` may be holding the lock; if no such process is running, delete cache.lock"
+			}
+		}
+	}
+
+	return result
+}
+
+// envVarFixer appends `export VAR=<value>` lines to ~/.profile on Unix (the
+// user fills in <value>; credlink has no sensible value to fabricate for a
+// variable like USER or HOME) or prints the equivalent `setx` command on
+// Windows, since credlink can't itself alter a parent shell's or the
+// registry's persistent environment.
+type envVarFixer struct {
+	vars []string
+}
+
+func (f *envVarFixer) missing() []string {
+	var missing []string
+	for _, v := range f.vars {
+		if os.Getenv(v) == "" {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
+
+func (f *envVarFixer) Plan() []FixStep {
+	var steps []FixStep
+	for _, v := range f.missing() {
+		if runtime.GOOS == "windows" {
+			steps = append(steps, FixStep{
+				Description: fmt.Sprintf("Set %s via setx (takes effect in new shells)", v),
+				Command:     fmt.Sprintf("setx %s <value>", v),
+			})
+		} else {
+			steps = append(steps, FixStep{
+				Description: fmt.Sprintf("Append export %s=<value> to ~/.profile (takes effect in new shells)", v),
+				Command:     fmt.Sprintf("export %s=<value>", v),
+			})
+		}
+	}
+	return steps
+}
+
+func (f *envVarFixer) Apply(step FixStep) error {
+	if runtime.GOOS == "windows" {
+		return fmt.Errorf("run manually: %s (credlink does not set registry/session env vars on your behalf)", step.Command)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("locate home directory: %w", err)
+	}
+	profilePath := filepath.Join(home, ".profile")
+
+	existing, err := os.ReadFile(profilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read %s: %w", profilePath, err)
+	}
+	if strings.Contains(string(existing), step.Command) {
+		return nil // already applied
+	}
+
+	out, err := os.OpenFile(profilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", profilePath, err)
+	}
+	defer out.Close()
+
+	if _, err := fmt.Fprintf(out, "# added by `credlink doctor --fix`\n%s\n", step.Command); err != nil {
+		return fmt.Errorf("write %s: %w", profilePath, err)
+	}
+	return nil
+}
+
+func (f *envVarFixer) Verify() CheckResult {
+	result := CheckResult{Name: "Environment Variables"}
+
+	missing := f.missing()
+	if len(missing) > 0 {
+		result.Status = "warning"
+		result.Message = "Some environment variables missing"
+		result.Details = fmt.Sprintf("Missing: %v", missing)
+		result.Suggestion = "Open a new shell so the ~/.profile (or setx) change takes effect"
+		result.CanFix = true
+	} else {
+		result.Status = "ok"
+		result.Message = "Required environment variables present"
+	}
+	return result
+}
+
+// certificateChainFixer installs fallbackRootCAs into the OS trust store
+// when host's live chain doesn't verify against the system pool - the same
+// bundle verifyCertificateChain already checks against, just persisted so
+// other TLS clients on the machine (not only credlink) pick it up too.
+type certificateChainFixer struct {
+	host string
+}
+
+func (f *certificateChainFixer) Plan() []FixStep {
+	step := FixStep{Description: "Install the bundled fallback root CA into the OS trust store"}
+	switch runtime.GOOS {
+	case "darwin":
+		step.Command = "security add-trusted-cert -d -r trustRoot -k /Library/Keychains/System.keychain <bundle>"
+	case "windows":
+		step.Command = "certutil -addstore -f Root <bundle>"
+	default:
+		step.Command = "cp <bundle> /usr/local/share/ca-certificates/credlink-fallback.crt && update-ca-certificates"
+	}
+	return []FixStep{step}
+}
+
+func (f *certificateChainFixer) Apply(step FixStep) error {
+	if fallbackRootCAs == "" {
+		return fmt.Errorf("no bundled fallback root CA is embedded in this build; install the correct system root manually")
+	}
+
+	tmp, err := os.CreateTemp("", "credlink-fallback-ca-*.pem")
+	if err != nil {
+		return fmt.Errorf("write temporary bundle: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(fallbackRootCAs); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temporary bundle: %w", err)
+	}
+	tmp.Close()
+
+	var installCmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		installCmd = exec.Command("security", "add-trusted-cert", "-d", "-r", "trustRoot", "-k", "/Library/Keychains/System.keychain", tmp.Name())
+	case "windows":
+		installCmd = exec.Command("certutil", "-addstore", "-f", "Root", tmp.Name())
+	default:
+		installCmd = exec.Command("sh", "-c", fmt.Sprintf("cp %s /usr/local/share/ca-certificates/credlink-fallback.crt && update-ca-certificates", tmp.Name()))
+	}
+
+	if out, err := installCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("install root CA (requires admin/sudo): %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (f *certificateChainFixer) Verify() CheckResult {
+	chain, err := probeCertificateChain(f.host)
+	if err != nil {
+		return CheckResult{
+			Name:       "System Certificates",
+			Status:     "error",
+			Message:    "Could not retrieve the server's certificate chain",
+			Details:    err.Error(),
+			Suggestion: "Check network connectivity and proxy configuration",
+		}
+	}
+	// verifyCertificateChain always puts the trust-against-system-pool
+	// result first, ahead of any per-certificate expiry warnings.
+	return verifyCertificateChain(chain)[0]
+}
+
+// windowsLongPathFixer enables the Windows long-path registry key, which
+// requires an elevated (Administrator) process to write.
+type windowsLongPathFixer struct{}
+
+func (f *windowsLongPathFixer) Plan() []FixStep {
+	return []FixStep{{
+		Description: `Set HKLM\SYSTEM\CurrentControlSet\Control\FileSystem\LongPathsEnabled to 1 (requires an elevated/Administrator prompt)`,
+	}}
+}
+
+func (f *windowsLongPathFixer) Apply(step FixStep) error {
+	if err := setWindowsLongPathsEnabled(); err != nil {
+		return fmt.Errorf("enable long paths: %w", err)
+	}
+	return nil
+}
+
+func (f *windowsLongPathFixer) Verify() CheckResult {
+	result := CheckResult{Name: "Windows Long Paths"}
+
+	enabled, err := windowsLongPathsEnabled()
+	switch {
+	case err != nil:
+		result.Status = "error"
+		result.Message = "Could not determine long path support"
+		result.Details = err.Error()
+	case enabled:
+		result.Status = "ok"
+		result.Message = "Long path support enabled"
+	default:
+		result.Status = "warning"
+		result.Message = "Long path support disabled"
+		result.Details = "MAX_PATH (260) limit enforced"
+		result.Suggestion = "Enable long path support via group policy or registry"
+		result.CanFix = true
+	}
+	return result
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package cmd
+
+import "fmt"
+
+// windowsLongPathsEnabled and setWindowsLongPathsEnabled are never called on
+// this platform (checkPathHandling only runs the Windows Long Paths check
+// when runtime.GOOS == "windows"), but must still exist so the package
+// builds for non-Windows targets - the same split lockedfile_unix.go /
+// lockedfile_windows.go uses.
+func windowsLongPathsEnabled() (bool, error) {
+	return false, fmt.Errorf("windows long-path support is only applicable on Windows")
+}
+
+func setWindowsLongPathsEnabled() error {
+	return fmt.Errorf("windows long-path fix is only applicable on Windows")
+}
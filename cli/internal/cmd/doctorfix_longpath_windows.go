@@ -0,0 +1,76 @@
+//go:build windows
+
+package cmd
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// longPathsRegistrySubkey and longPathsValueName locate the DWORD that
+// toggles the Windows 260-character MAX_PATH limit off for processes that
+// opt in (as credlink does via its manifest).
+const longPathsRegistrySubkey = `SYSTEM\CurrentControlSet\Control\FileSystem`
+const longPathsValueName = "LongPathsEnabled"
+
+// windowsLongPathsEnabled reads LongPathsEnabled directly via the syscall
+// package's raw Reg* wrappers, matching lockedfile_windows.go's preference
+// for stdlib syscall over a registry helper package.
+func windowsLongPathsEnabled() (bool, error) {
+	subkey, err := syscall.UTF16PtrFromString(longPathsRegistrySubkey)
+	if err != nil {
+		return false, err
+	}
+
+	var key syscall.Handle
+	if err := syscall.RegOpenKeyEx(syscall.HKEY_LOCAL_MACHINE, subkey, 0, syscall.KEY_QUERY_VALUE, &key); err != nil {
+		return false, fmt.Errorf("open registry key: %w", err)
+	}
+	defer syscall.RegCloseKey(key)
+
+	name, err := syscall.UTF16PtrFromString(longPathsValueName)
+	if err != nil {
+		return false, err
+	}
+
+	var valueType uint32
+	var data uint32
+	dataLen := uint32(4)
+	if err := syscall.RegQueryValueEx(key, name, nil, &valueType, (*byte)(unsafe.Pointer(&data)), &dataLen); err != nil {
+		if err == syscall.ERROR_FILE_NOT_FOUND {
+			return false, nil
+		}
+		return false, fmt.Errorf("read %s: %w", longPathsValueName, err)
+	}
+
+	return data == 1, nil
+}
+
+// setWindowsLongPathsEnabled writes LongPathsEnabled=1, which requires an
+// elevated (Administrator) process - RegOpenKeyEx with KEY_SET_VALUE fails
+// against HKLM otherwise, surfaced here as a clear error rather than a bare
+// "access is denied".
+func setWindowsLongPathsEnabled() error {
+	subkey, err := syscall.UTF16PtrFromString(longPathsRegistrySubkey)
+	if err != nil {
+		return err
+	}
+
+	var key syscall.Handle
+	if err := syscall.RegOpenKeyEx(syscall.HKEY_LOCAL_MACHINE, subkey, 0, syscall.KEY_SET_VALUE, &key); err != nil {
+		return fmt.Errorf("open registry key for write (requires an elevated/Administrator prompt): %w", err)
+	}
+	defer syscall.RegCloseKey(key)
+
+	name, err := syscall.UTF16PtrFromString(longPathsValueName)
+	if err != nil {
+		return err
+	}
+
+	value := uint32(1)
+	if err := syscall.RegSetValueEx(key, name, 0, syscall.REG_DWORD, (*byte)(unsafe.Pointer(&value)), 4); err != nil {
+		return fmt.Errorf("set %s: %w", longPathsValueName, err)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// doctorProbeHost is the TLS endpoint checkCertificates and the HTTP leg
+// of checkNetworkConnectivity probe - the same API host DNS/TCP checks
+// already target.
+const doctorProbeHost = "api.c2concierge.com:443"
+
+// fallbackRootCAs is an optional PEM bundle of additional root CAs to
+// trust alongside the OS store, for environments whose system trust store
+// is incomplete or pruned by a corporate MDM policy. Empty in this
+// checkout; an official release build sets it (via -ldflags -X or an
+// embedded resource) to a real bundle before linking.
+var fallbackRootCAs string
+
+// checkCertificates probes doctorProbeHost's live TLS certificate chain,
+// verifies it against the system trust store (plus fallbackRootCAs),
+// flags any chain certificate expiring within 30 days, checks which TLS
+// versions the server negotiates, and - when CREDLINK_PINNED_SPKI_SHA256
+// is set - verifies the leaf's public key against that pinned fingerprint
+// as a MITM check.
+func checkCertificates() []CheckResult {
+	var results []CheckResult
+
+	PrintMsgf("Checking system certificate store...\n")
+	chain, err := probeCertificateChain(doctorProbeHost)
+	if err != nil {
+		results = append(results, CheckResult{
+			Name:       "System Certificates",
+			Status:     "error",
+			Message:    "Could not retrieve the server's certificate chain",
+			Details:    err.Error(),
+			Suggestion: "Check network connectivity and proxy configuration",
+		})
+		results = append(results, checkTLSVersions(doctorProbeHost))
+		return results
+	}
+
+	results = append(results, verifyCertificateChain(chain)...)
+
+	if pinned := os.Getenv("CREDLINK_PINNED_SPKI_SHA256"); pinned != "" {
+		results = append(results, checkPinnedSPKI(chain[0], pinned))
+	}
+
+	PrintMsgf("Checking TLS version support...\n")
+	results = append(results, checkTLSVersions(doctorProbeHost))
+
+	return results
+}
+
+// probeCertificateChain dials host with InsecureSkipVerify (we verify the
+// chain ourselves below) purely to retrieve the certificates the server
+// presents.
+func probeCertificateChain(host string) ([]*x509.Certificate, error) {
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", host, &tls.Config{
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tls dial %s: %w", host, err)
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("server presented no certificates")
+	}
+	return certs, nil
+}
+
+// verifyCertificateChain checks chain[0] (the leaf) against the system
+// trust store plus fallbackRootCAs, treating the rest of chain as
+// intermediates, and reports one additional CheckResult per certificate
+// warning when it expires within 30 days.
+func verifyCertificateChain(chain []*x509.Certificate) []CheckResult {
+	var results []CheckResult
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if fallbackRootCAs != "" {
+		pool.AppendCertsFromPEM([]byte(fallbackRootCAs))
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	trust := CheckResult{Name: "System Certificates"}
+	if _, err := chain[0].Verify(x509.VerifyOptions{Roots: pool, Intermediates: intermediates}); err != nil {
+		trust.Status = "error"
+		trust.Message = "Server certificate does not chain to a trusted root"
+		trust.Details = err.Error()
+		trust.CanFix = true
+		trust.Suggestion = "Run with --fix to install a missing system root from the bundled fallback store"
+	} else {
+		trust.Status = "ok"
+		trust.Message = "Server certificate chains to a trusted root"
+	}
+	results = append(results, trust)
+
+	expiryCutoff := time.Now().Add(30 * 24 * time.Hour)
+	for i, cert := range chain {
+		role := "Intermediate Certificate"
+		if i == 0 {
+			role = "Leaf Certificate"
+		}
+
+		result := CheckResult{
+			Name:    role,
+			Message: fmt.Sprintf("%s (expires %s)", cert.Subject.CommonName, cert.NotAfter.Format("2006-01-02")),
+		}
+		if cert.NotAfter.Before(expiryCutoff) {
+			result.Status = "warning"
+			result.Details = fmt.Sprintf("NotAfter: %s", cert.NotAfter.Format(time.RFC3339))
+			result.Suggestion = "Certificate expires within 30 days; plan a rotation"
+		} else {
+			result.Status = "ok"
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// checkPinnedSPKI compares leaf's SubjectPublicKeyInfo hash against a
+// user-configured CREDLINK_PINNED_SPKI_SHA256 fingerprint, catching a MITM
+// proxy that presents a differently-keyed (even if otherwise
+// trust-store-valid) certificate for doctorProbeHost.
+func checkPinnedSPKI(leaf *x509.Certificate, pinned string) CheckResult {
+	result := CheckResult{Name: "Pinned Certificate"}
+
+	spki, err := x509.MarshalPKIXPublicKey(leaf.PublicKey)
+	if err != nil {
+		result.Status = "error"
+		result.Message = "Could not compute the leaf certificate's SubjectPublicKeyInfo"
+		result.Details = err.Error()
+		return result
+	}
+
+	sum := sha256.Sum256(spki)
+	got := base64.StdEncoding.EncodeToString(sum[:])
+	if got != pinned {
+		result.Status = "error"
+		result.Message = "Leaf certificate's public key does not match CREDLINK_PINNED_SPKI_SHA256"
+		result.Details = fmt.Sprintf("expected %s, got %s", pinned, got)
+		result.Suggestion = "This may indicate a man-in-the-middle proxy intercepting TLS"
+		return result
+	}
+
+	result.Status = "ok"
+	result.Message = "Leaf certificate matches the pinned SPKI fingerprint"
+	return result
+}
+
+// checkTLSVersions probes TLS 1.2 and TLS 1.3 independently (by pinning
+// MinVersion/MaxVersion to each) so doctor can tell a server offering only
+// TLS 1.2 from one that's fully up to date.
+func checkTLSVersions(host string) CheckResult {
+	result := CheckResult{Name: "TLS Support"}
+
+	tls12, err12 := probeTLSVersion(host, tls.VersionTLS12)
+	tls13, err13 := probeTLSVersion(host, tls.VersionTLS13)
+
+	switch {
+	case tls12 && tls13:
+		result.Status = "ok"
+		result.Message = "TLS 1.2 and TLS 1.3 both negotiable"
+	case tls13 && !tls12:
+		result.Status = "ok"
+		result.Message = "TLS 1.3 negotiable"
+	case tls12 && !tls13:
+		result.Status = "warning"
+		result.Message = "Only TLS 1.2 negotiable; server does not offer TLS 1.3"
+		result.Suggestion = "Upgrade the server's TLS stack to support TLS 1.3"
+	default:
+		result.Status = "error"
+		result.Message = "Could not negotiate TLS 1.2 or TLS 1.3"
+		if err12 != nil {
+			result.Details = err12.Error()
+		} else if err13 != nil {
+			result.Details = err13.Error()
+		}
+	}
+
+	return result
+}
+
+// probeTLSVersion reports whether host accepts a handshake pinned to
+// exactly one TLS version.
+func probeTLSVersion(host string, version uint16) (bool, error) {
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", host, &tls.Config{
+		InsecureSkipVerify: true,
+		MinVersion:         version,
+		MaxVersion:         version,
+	})
+	if err != nil {
+		return false, err
+	}
+	conn.Close()
+	return true, nil
+}
+
+// checkHTTPConnectivity resolves HTTPS_PROXY/HTTP_PROXY/NO_PROXY via
+// httpproxy.FromEnvironment, reports the proxy it resolved (if any) for
+// doctorProbeHost, and performs a real HTTPS GET - through that proxy's
+// CONNECT tunnel when one applies - to confirm end-to-end reachability.
+func checkHTTPConnectivity() CheckResult {
+	result := CheckResult{Name: "HTTP Connectivity"}
+
+	target := &url.URL{Scheme: "https", Host: doctorProbeHost}
+	proxyURL, err := httpproxy.FromEnvironment().ProxyFunc()(target)
+	if err != nil {
+		result.Status = "warning"
+		result.Message = "Could not resolve proxy configuration"
+		result.Details = err.Error()
+		return result
+	}
+
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)},
+	}
+
+	resp, err := client.Get(target.String())
+	if err != nil {
+		result.Status = "warning"
+		result.Message = "Cannot reach the API endpoint over HTTPS"
+		result.Details = err.Error()
+		result.Suggestion = "Check firewall settings and proxy configuration"
+		if proxyURL != nil {
+			result.Suggestion = fmt.Sprintf("%s (CONNECT via proxy %s failed)", result.Suggestion, proxyURL)
+		}
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.Status = "ok"
+	if proxyURL != nil {
+		result.Message = fmt.Sprintf("HTTP connectivity working via proxy %s", proxyURL)
+	} else {
+		result.Message = "HTTP connectivity working (no proxy configured)"
+	}
+	return result
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// markdownSummary renders data (whatever shape a command passes to
+// PrintOutput - typically a map[string]interface{} projection or summary)
+// as a $GITHUB_STEP_SUMMARY-friendly markdown block. Maps become a two
+// column key/value table so a batch verify or dry-run summary reads like a
+// rendered report instead of a JSON blob; anything else falls back to a
+// fenced code block of its JSON encoding.
+func markdownSummary(data interface{}) string {
+	if m, ok := data.(map[string]interface{}); ok {
+		return markdownTable(m)
+	}
+
+	encoded, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("```\n%v\n```", data)
+	}
+	return fmt.Sprintf("```json\n%s\n```", encoded)
+}
+
+// markdownTable renders a flat key/value table, sorting keys for
+// deterministic output, and JSON-encoding any nested map/slice values so
+// the table stays one row per top-level field.
+func markdownTable(m map[string]interface{}) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("| Field | Value |\n")
+	b.WriteString("| --- | --- |\n")
+	for _, k := range keys {
+		b.WriteString(fmt.Sprintf("| %s | %s |\n", k, markdownCell(m[k])))
+	}
+	return b.String()
+}
+
+func markdownCell(v interface{}) string {
+	switch v.(type) {
+	case string, int, int64, float64, bool, nil:
+		return fmt.Sprintf("%v", v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return "`" + string(encoded) + "`"
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 	"time"
 
+	"github.com/credlink/sdk/go/credlink"
+	"github.com/credlink/sdk/go/credlink/events"
 	"github.com/spf13/cobra"
 )
 
@@ -17,6 +19,11 @@ var (
 	ErrNetwork         = errors.New("network error")
 	ErrRateLimit       = errors.New("rate limit error")
 	ErrServer          = errors.New("server error")
+	ErrThresholdNotMet = errors.New("verification threshold not met")
+	ErrRetryTimeout    = errors.New("verification retry timed out")
+	ErrPartialFailure  = errors.New("at least one object failed")
+	ErrAborted         = errors.New("operation aborted")
+	ErrDiffFound       = errors.New("diff found a gating difference")
 )
 
 // Global flags structure
@@ -30,6 +37,7 @@ type GlobalOptions struct {
 	Timeout        time.Duration
 	IdempotencyKey string
 	DryRun         bool
+	Output         string
 }
 
 // Exit codes as specified
@@ -42,6 +50,7 @@ const (
 	ExitRateLimit   = 6
 	ExitNetErr      = 7
 	ExitSrvErr      = 8
+	ExitAborted     = 130 // 128+SIGINT, the shell's usual convention for Ctrl-C
 )
 
 // Global options instance
@@ -52,16 +61,23 @@ func AddGlobalFlags(cmd *cobra.Command) {
 	cmd.PersistentFlags().BoolVar(&globalOpts.JSON, "json", false, "Output JSON format (machine-readable)")
 	cmd.PersistentFlags().BoolVar(&globalOpts.Quiet, "quiet", false, "Suppress non-error output")
 	cmd.PersistentFlags().BoolVar(&globalOpts.Yes, "yes", false, "Auto-confirm prompts")
-	cmd.PersistentFlags().StringVar(&globalOpts.Profile, "profile", "", "Signing profile ID")
+	cmd.PersistentFlags().StringVar(&globalOpts.Profile, "profile", "", "Named config profile to use (overrides the persisted default_profile)")
 	cmd.PersistentFlags().StringVar(&globalOpts.Endpoint, "endpoint", "https://api.credlink.com/v1", "API endpoint URL")
 	cmd.PersistentFlags().IntVar(&globalOpts.Retries, "retries", 5, "Maximum retry attempts")
 	cmd.PersistentFlags().DurationVar(&globalOpts.Timeout, "timeout", 30*time.Second, "Request timeout")
 	cmd.PersistentFlags().StringVar(&globalOpts.IdempotencyKey, "idempotency-key", "", "Idempotency key for safe retries")
 	cmd.PersistentFlags().BoolVar(&globalOpts.DryRun, "dry-run", false, "Show what would be done without executing")
+	cmd.PersistentFlags().StringVar(&globalOpts.Output, "output", "human", "Progress/event output format: human, ndjson, json, or github-actions")
 }
 
 // Print output based on global flags
 func PrintOutput(data interface{}) error {
+	if outputMode() == events.OutputGitHubActions {
+		if err := events.AppendStepSummary(markdownSummary(data)); err != nil {
+			PrintErrf("Warning: failed to write step summary: %v\n", err)
+		}
+	}
+
 	if globalOpts.JSON {
 		encoder := json.NewEncoder(os.Stdout)
 		encoder.SetIndent("", "  ")
@@ -105,3 +121,89 @@ func PrintErrf(format string, args ...interface{}) {
 func PrintErr(message string) {
 	fmt.Fprintln(os.Stderr, message)
 }
+
+// outputMode resolves the active --output mode, falling back to the legacy
+// --json flag (which only ever meant "json") when --output was left at its
+// default so existing scripts that only set --json keep working. When
+// --output is human (its default, whether or not it was passed) and --json
+// wasn't passed either, a run inside
+// GitHub Actions (GITHUB_ACTIONS=true) auto-enables OutputGitHubActions, so
+// `batch verify` run from a workflow gets inline annotations without the
+// operator having to know the flag exists.
+func outputMode() events.OutputMode {
+	if globalOpts.Output != "" && globalOpts.Output != "human" {
+		if mode, err := events.ParseOutputMode(globalOpts.Output); err == nil {
+			return mode
+		}
+	}
+	if globalOpts.JSON {
+		return events.OutputJSON
+	}
+	if events.InGitHubActions() {
+		return events.OutputGitHubActions
+	}
+	return events.OutputHuman
+}
+
+// newEventEmitter builds an Emitter for the active --output mode.
+func newEventEmitter() *events.Emitter {
+	return events.NewEmitter(outputMode())
+}
+
+// mapSDKError translates a typed credlink SDK error into the matching cmd
+// sentinel so main's exit-code switch (which compares by exact equality)
+// branches correctly. Errors that aren't one of the SDK's typed errors pass
+// through unchanged.
+func mapSDKError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch err.(type) {
+	case *credlink.ValidationError:
+		return ErrInputValidation
+	case *credlink.AuthError:
+		return ErrAuthentication
+	case *credlink.RateLimitError:
+		return ErrRateLimit
+	case *credlink.NetworkError:
+		return ErrNetwork
+	case *credlink.ServerError:
+		return ErrServer
+	default:
+		return err
+	}
+}
+
+// newSDKClient builds a credlink SDK client from the active global flags and
+// environment. The API key is read from CREDLINK_API_KEY (falling back to
+// the legacy C2_API_KEY used by the example binaries) rather than a flag, so
+// it never ends up in shell history or process listings.
+func newSDKClient() (*credlink.Client, error) {
+	apiKey := os.Getenv("CREDLINK_API_KEY")
+	if apiKey == "" {
+		apiKey = os.Getenv("C2_API_KEY")
+	}
+	if apiKey == "" {
+		return nil, fmt.Errorf("CREDLINK_API_KEY environment variable is required")
+	}
+	if events.InGitHubActions() {
+		// The key is about to be used on outgoing requests that error
+		// handling or retry logging might echo; mask it before any of
+		// that can happen so it never lands in a workflow's raw log.
+		fmt.Println(events.WorkflowMask(apiKey))
+	}
+
+	config := credlink.DefaultConfig()
+	config.APIKey = apiKey
+	if globalOpts.Endpoint != "" {
+		config.BaseURL = globalOpts.Endpoint
+	}
+	if globalOpts.Timeout > 0 {
+		config.TimeoutMs = globalOpts.Timeout
+	}
+	if globalOpts.Retries > 0 {
+		config.Retries.MaxAttempts = globalOpts.Retries
+	}
+
+	return credlink.NewClient(config), nil
+}
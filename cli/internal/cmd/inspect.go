@@ -1,22 +1,26 @@
 package cmd
 
 import (
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/credlink/cli/internal/manifest"
+	"github.com/credlink/cli/internal/safepath"
 )
 
 // Inspect command options
 type InspectOptions struct {
-	Detailed   bool
-	Remote     bool
-	Format     string
-	ShowClaims bool
-	ShowCert   bool
+	Detailed     bool
+	Remote       bool
+	Format       string
+	ShowClaims   bool
+	ShowCert     bool
+	TrustAnchors string
 }
 
 var inspectOpts InspectOptions
@@ -38,6 +42,7 @@ Follows c2patool conventions for investigator compatibility.`,
 	inspectCmd.Flags().StringVar(&inspectOpts.Format, "format", "json", "Output format: json|yaml|table")
 	inspectCmd.Flags().BoolVar(&inspectOpts.ShowClaims, "claims", false, "Show all claims in detail")
 	inspectCmd.Flags().BoolVar(&inspectOpts.ShowCert, "cert", false, "Show certificate chain details")
+	inspectCmd.Flags().StringVar(&inspectOpts.TrustAnchors, "trust-anchors", "", "PEM file of trusted roots for chain verification (default: verification skipped)")
 
 	rootCmd.AddCommand(inspectCmd)
 }
@@ -53,20 +58,31 @@ func runInspectCommand(cmd *cobra.Command, args []string) error {
 
 	PrintMsgf("Inspecting asset: %s\n", asset)
 
-	// Mock inspection result
+	roots, err := loadTrustAnchors(inspectOpts.TrustAnchors)
+	if err != nil {
+		return err
+	}
+
+	m, err := manifest.Parse(asset, manifest.Options{TrustRoots: roots})
+	if err != nil {
+		return fmt.Errorf("inspect %s: %w", asset, err)
+	}
+
 	result := map[string]interface{}{
-		"asset":     asset,
-		"manifest":  generateMockManifest(asset),
-		"verified":  true,
-		"timestamp": "2025-01-15T10:30:00Z",
+		"asset":          asset,
+		"manifest":       m,
+		"verified":       m.SignatureVerified,
+		"chain_verified": m.ChainVerified,
 	}
 
 	if inspectOpts.Detailed {
 		result["detailed"] = true
-		result["claims"] = generateMockClaims()
-		if inspectOpts.ShowCert {
-			result["certificates"] = generateMockCertificates()
-		}
+	}
+	if inspectOpts.ShowClaims {
+		result["assertions"] = m.Assertions
+	}
+	if inspectOpts.ShowCert {
+		result["certificates"] = m.Certificates
 	}
 
 	// Format output based on format flag
@@ -80,121 +96,36 @@ func runInspectCommand(cmd *cobra.Command, args []string) error {
 	}
 }
 
-func validateInspectInput(asset string) error {
-	if asset == "" {
-		return fmt.Errorf("asset path cannot be empty")
+// loadTrustAnchors loads --trust-anchors into a cert pool, mirroring sign's
+// --tsa-cert-chain handling. No flag means chain verification is skipped
+// rather than silently trusting the system roots: C2PA has no universal
+// default trust store.
+func loadTrustAnchors(path string) (*x509.CertPool, error) {
+	if path == "" {
+		return nil, nil
 	}
-
-	// Check for path traversal attempts in local paths
-	if !strings.HasPrefix(asset, "s3://") && !strings.HasPrefix(asset, "r2://") {
-		// Resolve to absolute path to detect traversal
-		absPath, err := filepath.Abs(asset)
-		if err != nil {
-			return fmt.Errorf("invalid path")
-		}
-
-		// Check for path traversal
-		if strings.Contains(asset, "..") {
-			// For relative paths, check if they go outside current directory
-			cwd, err := os.Getwd()
-			if err != nil {
-				return fmt.Errorf("cannot determine current directory")
-			}
-
-			// If the resolved path is not under current directory, it's traversal
-			if !strings.HasPrefix(absPath, cwd) {
-				return fmt.Errorf("path traversal detected: access outside current directory not allowed")
-			}
-		}
-
-		// Additional check for suspicious patterns
-		if strings.Contains(asset, "../") || strings.Contains(asset, "..\\") {
-			return fmt.Errorf("path traversal patterns not allowed")
-		}
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read --trust-anchors: %w", err)
 	}
-
-	// Validate cloud path format
-	if strings.HasPrefix(asset, "s3://") || strings.HasPrefix(asset, "r2://") {
-		var prefix string
-		if strings.HasPrefix(asset, "s3://") {
-			prefix = "s3://"
-		} else {
-			prefix = "r2://"
-		}
-		parts := strings.SplitN(strings.TrimPrefix(asset, prefix), "/", 2)
-		if len(parts) < 2 {
-			if prefix == "s3://" {
-				return fmt.Errorf("invalid S3 path format, expected: s3://bucket/key")
-			} else {
-				return fmt.Errorf("invalid R2 path format, expected: r2://account/bucket/key")
-			}
-		}
-		if parts[0] == "" {
-			return fmt.Errorf("bucket name cannot be empty")
-		}
-		// Check for path traversal in cloud paths
-		if strings.Contains(parts[1], "..") {
-			return fmt.Errorf("path traversal not allowed in cloud paths")
-		}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("--trust-anchors %s contains no usable certificates", path)
 	}
-
-	return nil
+	return pool, nil
 }
 
-func generateMockManifest(asset string) map[string]interface{} {
-	return map[string]interface{}{
-		"label":       "c2pa-manifest",
-		"manifest_id": fmt.Sprintf("manifest-%x", len(asset)),
-		"title":       "C2 Concierge Manifest",
-		"format":      "application/json",
-		"instance_id": fmt.Sprintf("instance-%x", len(asset)*2),
-		"assertions": []map[string]interface{}{
-			{
-				"label": "c2pa.actions",
-				"data": map[string]interface{}{
-					"actions": []map[string]interface{}{
-						{
-							"action": "c2pa.sign",
-							"when":   "2025-01-15T10:30:00Z",
-						},
-					},
-				},
-			},
-		},
+func validateInspectInput(asset string) error {
+	if asset == "" {
+		return fmt.Errorf("asset path cannot be empty")
 	}
-}
 
-func generateMockClaims() []map[string]interface{} {
-	return []map[string]interface{}{
-		{
-			"url": "self#jumbfs=c2pa.actions",
-			"claim": map[string]interface{}{
-				"algorithm": "sha256",
-				"hash":      "a1b2c3d4e5f6...",
-				"data": map[string]interface{}{
-					"actions": []map[string]interface{}{
-						{
-							"action": "c2pa.sign",
-							"when":   "2025-01-15T10:30:00Z",
-						},
-					},
-				},
-			},
-		},
+	if _, _, hasScheme := strings.Cut(asset, "://"); !hasScheme {
+		return safepath.ValidateLocalPath(asset)
 	}
-}
 
-func generateMockCertificates() []map[string]interface{} {
-	return []map[string]interface{}{
-		{
-			"subject":    "CN=C2 Concierge Signer",
-			"issuer":     "CN=C2 Concierge CA",
-			"serial":     "1234567890ABCDEF",
-			"not_before": "2025-01-01T00:00:00Z",
-			"not_after":  "2026-01-01T00:00:00Z",
-			"thumbprint": "A1B2C3D4E5F67890...",
-		},
-	}
+	_, err := safepath.ParseCloudPath(asset)
+	return err
 }
 
 func printYAML(data interface{}) error {
@@ -207,8 +138,8 @@ func printYAML(data interface{}) error {
 
 func printTable(data interface{}) error {
 	fmt.Println("# Inspection Results")
-	fmt.Println("Asset\t\tStatus\tTimestamp")
-	fmt.Println("-----\t\t------\t--------")
+	fmt.Println("Asset\t\tStatus")
+	fmt.Println("-----\t\t------")
 
 	if result, ok := data.(map[string]interface{}); ok {
 		asset := result["asset"]
@@ -216,9 +147,8 @@ func printTable(data interface{}) error {
 		if v, ok := result["verified"].(bool); ok && !v {
 			status = "Unverified"
 		}
-		timestamp := result["timestamp"]
 
-		fmt.Printf("%v\t%v\t%v\n", asset, status, timestamp)
+		fmt.Printf("%v\t%v\n", asset, status)
 	}
 
 	return nil
@@ -1,7 +1,12 @@
 package cmd
 
 import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
@@ -9,18 +14,26 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/credlink/cli/internal/safepath"
+	"github.com/credlink/cli/pkg/storage"
 )
 
 // Ls command options
 type LsOptions struct {
-	Long      bool
-	Human     bool
-	Recursive bool
-	All       bool
-	Delimiter string
-	Prefix    string
-	Filter    string
-	SortBy    string
+	Long              bool
+	Human             bool
+	Recursive         bool
+	All               bool
+	Delimiter         string
+	Prefix            string
+	Filter            string
+	SortBy            string
+	AWSProfile        string
+	MaxKeys           int
+	Stream            bool
+	Limit             int
+	ContinuationToken string
 }
 
 var lsOpts LsOptions
@@ -55,6 +68,11 @@ via CommonPrefixes.`,
 	lsCmd.Flags().StringVar(&lsOpts.Prefix, "prefix", "", "Filter by prefix")
 	lsCmd.Flags().StringVar(&lsOpts.Filter, "filter", "", "Filter pattern (glob)")
 	lsCmd.Flags().StringVar(&lsOpts.SortBy, "sort", "name", "Sort by: name|size|modified")
+	lsCmd.Flags().StringVar(&lsOpts.AWSProfile, "aws-profile", "", "Named AWS shared-config profile for s3:// and r2:// paths")
+	lsCmd.Flags().IntVar(&lsOpts.MaxKeys, "max-keys", 0, "Stop after this many entries (0 = no cap)")
+	lsCmd.Flags().BoolVar(&lsOpts.Stream, "stream", false, "Emit one JSON object per line as entries arrive, instead of buffering the whole listing")
+	lsCmd.Flags().IntVar(&lsOpts.Limit, "limit", 0, "With --stream and --sort, keep only the top N entries instead of buffering everything (0 = unbounded)")
+	lsCmd.Flags().StringVar(&lsOpts.ContinuationToken, "continue-from", "", "Resume a cloud listing from a checkpointed ContinuationToken")
 
 	rootCmd.AddCommand(lsCmd)
 }
@@ -70,12 +88,16 @@ func runLsCommand(cmd *cobra.Command, args []string) error {
 
 	PrintMsgf("Listing: %s\n", path)
 
+	if lsOpts.Stream {
+		return streamListing(cmd.Context(), path)
+	}
+
 	var items []ListedItem
 	var err error
 
 	// Determine path type and list accordingly
 	if strings.HasPrefix(path, "s3://") || strings.HasPrefix(path, "r2://") {
-		items, err = listCloudPath(path)
+		items, err = listCloudPath(cmd.Context(), path)
 	} else {
 		items, err = listLocalPath(path)
 	}
@@ -95,231 +117,292 @@ func runLsCommand(cmd *cobra.Command, args []string) error {
 	return printListedItems(items)
 }
 
-func validateLsInput(path string) error {
-	if path == "" {
-		return fmt.Errorf("path cannot be empty")
+// streamListing emits one JSON object per line as items arrive via
+// WalkListing, instead of materializing the whole listing first like
+// listLocalPath/listCloudPath do - the mode large prefixes need. With
+// --sort set alongside --limit, a bounded top-N heap is kept in place of a
+// full sort so memory stays proportional to --limit, not to the listing.
+func streamListing(ctx context.Context, path string) error {
+	enc := json.NewEncoder(os.Stdout)
+
+	if lsOpts.SortBy != "" && lsOpts.Limit > 0 {
+		h := &topNHeap{less: lessFn(lsOpts.SortBy)}
+		err := WalkListing(ctx, path, lsOpts, func(item ListedItem, _ string) error {
+			heap.Push(h, item)
+			if h.Len() > lsOpts.Limit {
+				heap.Pop(h)
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errMaxKeysReached) {
+			return err
+		}
+		sort.Slice(h.items, func(i, j int) bool { return !h.less(h.items[i], h.items[j]) })
+		for _, item := range h.items {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
-	// Check for path traversal attempts in local paths
-	if !strings.HasPrefix(path, "s3://") && !strings.HasPrefix(path, "r2://") {
-		// Resolve to absolute path to detect traversal
-		absPath, err := filepath.Abs(path)
-		if err != nil {
-			return fmt.Errorf("invalid path")
-		}
+	err := WalkListing(ctx, path, lsOpts, func(item ListedItem, _ string) error {
+		return enc.Encode(item)
+	})
+	if err != nil && !errors.Is(err, errMaxKeysReached) {
+		return err
+	}
+	return nil
+}
 
-		// Check for path traversal
-		if strings.Contains(path, "..") {
-			// For relative paths, check if they go outside current directory
-			cwd, err := os.Getwd()
-			if err != nil {
-				return fmt.Errorf("cannot determine current directory")
-			}
+// topNHeap is a bounded min-heap keeping the Limit largest-by-less items
+// seen so far: when full, a new item only survives by evicting the current
+// root (the smallest of the kept set).
+type topNHeap struct {
+	items []ListedItem
+	less  func(a, b ListedItem) bool
+}
 
-			// If the resolved path is not under current directory, it's traversal
-			if !strings.HasPrefix(absPath, cwd) {
-				return fmt.Errorf("path traversal detected: access outside current directory not allowed")
-			}
-		}
+func (h *topNHeap) Len() int           { return len(h.items) }
+func (h *topNHeap) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *topNHeap) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *topNHeap) Push(x interface{}) { h.items = append(h.items, x.(ListedItem)) }
+func (h *topNHeap) Pop() interface{} {
+	old := h.items
+	n := len(old)
+	item := old[n-1]
+	h.items = old[:n-1]
+	return item
+}
 
-		// Additional check for suspicious patterns
-		if strings.Contains(path, "../") || strings.Contains(path, "..\\") {
-			return fmt.Errorf("path traversal patterns not allowed")
-		}
+// lessFn returns the ordering sortListedItems and topNHeap both key off of.
+func lessFn(sortBy string) func(a, b ListedItem) bool {
+	switch sortBy {
+	case "size":
+		return func(a, b ListedItem) bool { return a.Size < b.Size }
+	case "modified":
+		return func(a, b ListedItem) bool { return a.Modified.Before(b.Modified) }
+	default: // name
+		return func(a, b ListedItem) bool { return a.Name < b.Name }
 	}
+}
 
-	// Validate cloud path format
+// WalkListing streams path's entries (local or cloud, recursing per
+// opts.Recursive) to fn without materializing the full listing, so callers
+// that only need to visit every entry once - mirror's indexing, --stream,
+// and a future prefix-scoped inspect - don't have to buffer millions of
+// keys. For cloud paths checkpoint is the owning page's ContinuationToken;
+// pass it back as opts.ContinuationToken to resume the walk later. Local
+// walks always pass "".
+func WalkListing(ctx context.Context, path string, opts LsOptions, fn func(item ListedItem, checkpoint string) error) error {
 	if strings.HasPrefix(path, "s3://") || strings.HasPrefix(path, "r2://") {
-		var prefix string
-		if strings.HasPrefix(path, "s3://") {
-			prefix = "s3://"
-		} else {
-			prefix = "r2://"
-		}
-		parts := strings.SplitN(strings.TrimPrefix(path, prefix), "/", 2)
-		if len(parts) < 2 {
-			return fmt.Errorf("invalid cloud path format")
-		}
-		if parts[0] == "" {
-			return fmt.Errorf("bucket name cannot be empty")
-		}
-		// Check for path traversal in cloud paths
-		if strings.Contains(parts[1], "..") {
-			return fmt.Errorf("path traversal not allowed in cloud paths")
-		}
+		return walkCloudPath(ctx, path, opts, fn)
 	}
+	return walkLocalPath(path, opts, fn)
+}
 
-	return nil
+func validateLsInput(path string) error {
+	if path == "" {
+		return fmt.Errorf("path cannot be empty")
+	}
+
+	if _, _, hasScheme := strings.Cut(path, "://"); !hasScheme {
+		return safepath.ValidateLocalPath(path)
+	}
+
+	_, err := safepath.ParseCloudPath(path)
+	return err
 }
 
 func listLocalPath(path string) ([]ListedItem, error) {
 	var items []ListedItem
-
-	// Handle root case
-	if path == "." {
-		path = "."
+	err := walkLocalPath(path, lsOpts, func(item ListedItem, _ string) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errMaxKeysReached) {
+		return nil, err
 	}
+	return items, nil
+}
 
-	info, err := os.Stat(path)
+// errMaxKeysReached is returned from a walk callback to stop paging once
+// MaxKeys entries have been collected; it is never surfaced to the caller
+// as a real failure.
+var errMaxKeysReached = errors.New("ls: max-keys reached")
+
+// walkLocalPath streams a local file or directory's entries to fn.
+// filepath.WalkDir drives the traversal (rather than the hand-rolled
+// recursion this replaced) so it can't blow the call stack on a deep tree;
+// non-recursive listings skip descending past depth 1 by returning
+// filepath.SkipDir right after each top-level directory is emitted.
+func walkLocalPath(root string, opts LsOptions, fn func(ListedItem, string) error) error {
+	info, err := os.Stat(root)
 	if err != nil {
-		return nil, fmt.Errorf("cannot access path: %v", err)
+		return fmt.Errorf("cannot access path: %v", err)
+	}
+	if !info.IsDir() {
+		return fn(ListedItem{
+			Name:     filepath.Base(root),
+			Path:     root,
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+			Type:     "file",
+		}, "")
 	}
 
-	if info.IsDir() {
-		// List directory contents
-		entries, err := os.ReadDir(path)
+	count := 0
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
 		if err != nil {
-			return nil, fmt.Errorf("cannot read directory: %v", err)
+			return err
+		}
+		if p == root {
+			return nil
 		}
 
-		for _, entry := range entries {
-			if !lsOpts.All && strings.HasPrefix(entry.Name(), ".") {
-				continue
+		if !opts.All && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
 			}
+			return nil
+		}
 
-			if lsOpts.Filter != "" {
-				matched, err := filepath.Match(lsOpts.Filter, entry.Name())
-				if err != nil || !matched {
-					continue
+		if opts.Filter != "" {
+			matched, merr := filepath.Match(opts.Filter, d.Name())
+			if merr != nil || !matched {
+				if d.IsDir() && !opts.Recursive {
+					return filepath.SkipDir
 				}
+				return nil
 			}
+		}
 
-			itemInfo, err := entry.Info()
-			if err != nil {
-				continue
-			}
-
-			item := ListedItem{
-				Name:     entry.Name(),
-				Path:     filepath.Join(path, entry.Name()),
-				Size:     itemInfo.Size(),
-				Modified: itemInfo.ModTime(),
-			}
-
-			if entry.IsDir() {
-				item.Type = "directory"
-			} else {
-				item.Type = "file"
-			}
+		itemInfo, ierr := d.Info()
+		if ierr != nil {
+			return nil
+		}
+		itemType := "file"
+		if d.IsDir() {
+			itemType = "directory"
+		}
 
-			items = append(items, item)
+		if err := fn(ListedItem{
+			Name:     d.Name(),
+			Path:     p,
+			Size:     itemInfo.Size(),
+			Modified: itemInfo.ModTime(),
+			Type:     itemType,
+		}, ""); err != nil {
+			return err
+		}
 
-			// Recursive listing
-			if lsOpts.Recursive && entry.IsDir() {
-				subPath := filepath.Join(path, entry.Name())
-				subItems, err := listLocalPath(subPath)
-				if err == nil {
-					items = append(items, subItems...)
-				}
-			}
+		count++
+		if opts.MaxKeys > 0 && count >= opts.MaxKeys {
+			return errMaxKeysReached
 		}
-	} else {
-		// Single file
-		items = append(items, ListedItem{
-			Name:     filepath.Base(path),
-			Path:     path,
-			Size:     info.Size(),
-			Modified: info.ModTime(),
-			Type:     "file",
-		})
-	}
+		if d.IsDir() && !opts.Recursive {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
 
+func listCloudPath(ctx context.Context, path string) ([]ListedItem, error) {
+	var items []ListedItem
+	err := walkCloudPath(ctx, path, lsOpts, func(item ListedItem, _ string) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errMaxKeysReached) {
+		return nil, err
+	}
 	return items, nil
 }
 
-func listCloudPath(path string) ([]ListedItem, error) {
-	// Parse cloud path
-	var prefix string
-	var parts []string
-
-	if strings.HasPrefix(path, "s3://") {
-		prefix = "s3://"
-		parts = strings.SplitN(strings.TrimPrefix(path, prefix), "/", 2)
-	} else if strings.HasPrefix(path, "r2://") {
-		prefix = "r2://"
-		parts = strings.SplitN(strings.TrimPrefix(path, prefix), "/", 2)
-	} else {
-		return nil, fmt.Errorf("unsupported cloud path format: %s", path)
+// walkCloudPath streams a cloud prefix's entries to fn page by page via
+// ListDelimited, applying --prefix/--filter per item as it arrives instead
+// of materializing the page first. checkpoint passed to fn is the owning
+// page's ContinuationToken, which opts.ContinuationToken can later resume
+// from.
+func walkCloudPath(ctx context.Context, path string, opts LsOptions, fn func(ListedItem, string) error) error {
+	backend, prefix, err := storage.FactoryWithOptions(path, storage.FactoryOptions{AWSProfile: opts.AWSProfile})
+	if err != nil {
+		return err
 	}
 
-	bucket := parts[0]
-	prefixPath := ""
-	if len(parts) > 1 {
-		prefixPath = parts[1]
+	lister, ok := backend.(storage.DelimitedLister)
+	if !ok {
+		return fmt.Errorf("%s backend does not support listing", backend.Name())
 	}
 
-	// Mock cloud listing (in production, use AWS SDK)
-	items := []ListedItem{
-		{
-			Name:     "image1.jpg",
-			Path:     fmt.Sprintf("%s%s/%simage1.jpg", prefix, bucket, prefixPath),
-			Size:     1024000,
-			Modified: time.Now().Add(-2 * time.Hour),
-			Type:     "file",
-			ETag:     "\"a1b2c3d4e5f6\"",
-		},
-		{
-			Name:     "image2.png",
-			Path:     fmt.Sprintf("%s%s/%simage2.png", prefix, bucket, prefixPath),
-			Size:     2048000,
-			Modified: time.Now().Add(-1 * time.Hour),
-			Type:     "file",
-			ETag:     "\"f6e5d4c3b2a1\"",
-		},
-		{
-			Name:     "videos/",
-			Path:     fmt.Sprintf("%s%s/%svideos/", prefix, bucket, prefixPath),
-			Size:     0,
-			Modified: time.Now().Add(-3 * time.Hour),
-			Type:     "directory",
-		},
+	delimiter := opts.Delimiter
+	if opts.Recursive {
+		// A flat, fully recursive listing: no delimiter means S3/R2 fold
+		// nothing into CommonPrefixes.
+		delimiter = ""
 	}
 
-	// Apply prefix filter
-	if lsOpts.Prefix != "" {
-		var filtered []ListedItem
-		for _, item := range items {
-			if strings.HasPrefix(item.Name, lsOpts.Prefix) {
-				filtered = append(filtered, item)
+	base := strings.TrimSuffix(path, prefix)
+	count := 0
+
+	emit := func(item ListedItem, checkpoint string) error {
+		if opts.Prefix != "" && !strings.HasPrefix(item.Name, opts.Prefix) {
+			return nil
+		}
+		if opts.Filter != "" {
+			matched, merr := filepath.Match(opts.Filter, item.Name)
+			if merr != nil || !matched {
+				return nil
 			}
 		}
-		items = filtered
+		if err := fn(item, checkpoint); err != nil {
+			return err
+		}
+		count++
+		if opts.MaxKeys > 0 && count >= opts.MaxKeys {
+			return errMaxKeysReached
+		}
+		return nil
 	}
 
-	// Apply filter pattern
-	if lsOpts.Filter != "" {
-		var filtered []ListedItem
-		for _, item := range items {
-			matched, err := filepath.Match(lsOpts.Filter, item.Name)
-			if err != nil {
-				// Skip invalid patterns
-				continue
+	listErr := lister.ListDelimited(ctx, prefix, delimiter, int32(opts.MaxKeys), opts.ContinuationToken, func(page storage.Page) error {
+		for _, obj := range page.Objects {
+			name := strings.TrimPrefix(obj.Key, prefix)
+			if name == "" {
+				continue // the prefix "directory marker" object itself
 			}
-			if matched {
-				filtered = append(filtered, item)
+			if err := emit(ListedItem{
+				Name:     name,
+				Path:     base + obj.Key,
+				Size:     obj.Size,
+				Modified: obj.LastModified,
+				Type:     "file",
+				ETag:     obj.ETag,
+			}, page.ContinuationToken); err != nil {
+				return err
 			}
 		}
-		items = filtered
+		for _, cp := range page.CommonPrefixes {
+			name := strings.TrimSuffix(strings.TrimPrefix(cp, prefix), delimiter)
+			if err := emit(ListedItem{
+				Name: name + "/",
+				Path: base + cp,
+				Type: "directory",
+			}, page.ContinuationToken); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if listErr != nil && !errors.Is(listErr, errMaxKeysReached) {
+		return fmt.Errorf("list %s: %w", backend.Name(), listErr)
 	}
-
-	return items, nil
+	return listErr
 }
 
 func sortListedItems(items []ListedItem) {
-	switch lsOpts.SortBy {
-	case "size":
-		sort.Slice(items, func(i, j int) bool {
-			return items[i].Size < items[j].Size
-		})
-	case "modified":
-		sort.Slice(items, func(i, j int) bool {
-			return items[i].Modified.Before(items[j].Modified)
-		})
-	default: // name
-		sort.Slice(items, func(i, j int) bool {
-			return items[i].Name < items[j].Name
-		})
-	}
+	less := lessFn(lsOpts.SortBy)
+	sort.Slice(items, func(i, j int) bool { return less(items[i], items[j]) })
 }
 
 func printListedItems(items []ListedItem) error {
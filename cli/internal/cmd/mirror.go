@@ -0,0 +1,542 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/credlink/cli/internal/safepath"
+	"github.com/credlink/cli/pkg/storage"
+	"github.com/credlink/sdk/go/credlink"
+	"github.com/credlink/sdk/go/credlink/events"
+	"github.com/spf13/cobra"
+)
+
+// Mirror command options
+type MirrorOptions struct {
+	Overwrite       bool
+	Remove          bool
+	Watch           time.Duration
+	Parallel        int
+	RequireManifest bool
+	RewriteManifest bool
+}
+
+var mirrorOpts MirrorOptions
+
+func InitMirrorCommand(rootCmd *cobra.Command) {
+	var mirrorCmd = &cobra.Command{
+		Use:   "mirror <src> <dst>",
+		Short: "Sync a local path or cloud prefix onto another, C2PA-aware",
+		Long: `Mirror a local path or cloud prefix (s3://, r2://) onto another.
+
+Both sides are enumerated with the same listing plumbing ls uses, then
+diffed by comparing size + ETag, falling back to a content hash when
+an ETag is multipart-composite (contains a "-partcount" suffix) and so
+can't be compared across backends directly - the same fallback mc's mirror
+uses.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runMirrorCommand,
+	}
+
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.Overwrite, "overwrite", false, "Overwrite destination objects that already exist but differ from the source")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.Remove, "remove", false, "Delete destination objects that no longer exist on the source")
+	mirrorCmd.Flags().DurationVar(&mirrorOpts.Watch, "watch", 0, "Re-run the sync on this interval instead of exiting after one pass (0 = run once)")
+	mirrorCmd.Flags().IntVar(&mirrorOpts.Parallel, "parallel", 4, "Parallel copy workers")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.RequireManifest, "require-manifest", false, "Refuse to copy any asset whose C2PA manifest fails verification")
+	mirrorCmd.Flags().BoolVar(&mirrorOpts.RewriteManifest, "rewrite-manifest", false, "Re-sign each copied asset on the destination so its provenance chain includes the copy")
+
+	rootCmd.AddCommand(mirrorCmd)
+}
+
+func runMirrorCommand(cmd *cobra.Command, args []string) error {
+	src, dst := args[0], args[1]
+
+	if err := validateMirrorInput(src, dst); err != nil {
+		PrintErrf("Input error: %v\n", err)
+		return ErrInputValidation
+	}
+
+	srcBackend, srcPrefix, err := storage.Factory(src)
+	if err != nil {
+		PrintErrf("Input error: %v\n", err)
+		return ErrInputValidation
+	}
+	dstBackend, dstPrefix, err := storage.Factory(dst)
+	if err != nil {
+		PrintErrf("Input error: %v\n", err)
+		return ErrInputValidation
+	}
+
+	if globalOpts.DryRun {
+		return runMirrorDryRun(cmd.Context(), src, srcBackend, srcPrefix, dst, dstBackend, dstPrefix)
+	}
+
+	var client *credlink.Client
+	if mirrorOpts.RequireManifest || mirrorOpts.RewriteManifest {
+		client, err = newSDKClient()
+		if err != nil {
+			PrintErrf("%v\n", err)
+			return ErrAuthentication
+		}
+		defer client.Close()
+	}
+
+	// A Ctrl-C must stop in-flight copies instead of leaving partial uploads
+	// hanging, the same as sign and verify --prefix.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	emitter := newEventEmitter()
+
+	for {
+		PrintMsgf("Mirroring %s -> %s\n", src, dst)
+
+		summary, err := runMirrorPass(ctx, client, src, srcBackend, srcPrefix, dst, dstBackend, dstPrefix, emitter)
+		if err != nil {
+			PrintErrf("Mirror pass failed: %v\n", err)
+			return mapSDKError(err)
+		}
+
+		if err := PrintOutput(summary); err != nil {
+			return err
+		}
+
+		if summary.Failed > 0 {
+			return ErrPartialFailure
+		}
+
+		if mirrorOpts.Watch <= 0 {
+			break
+		}
+
+		PrintMsgf("Watching: next pass in %s\n", mirrorOpts.Watch)
+		select {
+		case <-time.After(mirrorOpts.Watch):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	PrintMsg("Mirror completed successfully")
+	return nil
+}
+
+// validateMirrorInput applies the same local-path-traversal and cloud-path-
+// shape checks ls/sign/inspect/verify each already repeat for their own
+// single path argument, once per side.
+func validateMirrorInput(src, dst string) error {
+	if src == "" || dst == "" {
+		return fmt.Errorf("src and dst cannot be empty")
+	}
+	if err := validateMirrorPath(src); err != nil {
+		return fmt.Errorf("src: %w", err)
+	}
+	if err := validateMirrorPath(dst); err != nil {
+		return fmt.Errorf("dst: %w", err)
+	}
+	return nil
+}
+
+func validateMirrorPath(path string) error {
+	if _, _, hasScheme := strings.Cut(path, "://"); !hasScheme {
+		return safepath.ValidateLocalPath(path)
+	}
+
+	_, err := safepath.ParseCloudPath(path)
+	return err
+}
+
+func runMirrorDryRun(ctx context.Context, src string, srcBackend storage.Backend, srcPrefix string, dst string, dstBackend storage.Backend, dstPrefix string) error {
+	srcIndex, err := indexBackend(ctx, srcBackend, srcPrefix)
+	if err != nil {
+		return fmt.Errorf("list src: %w", err)
+	}
+	dstIndex, err := indexBackend(ctx, dstBackend, dstPrefix)
+	if err != nil {
+		return fmt.Errorf("list dst: %w", err)
+	}
+
+	toCopy, toDelete := planMirror(srcIndex, dstIndex)
+
+	projection := map[string]interface{}{
+		"operation": "mirror",
+		"src":       src,
+		"dst":       dst,
+		"dry_run":   true,
+		"estimates": map[string]interface{}{
+			"objects_to_copy":   len(toCopy),
+			"objects_to_delete": len(toDelete),
+		},
+		"options": map[string]interface{}{
+			"overwrite":        mirrorOpts.Overwrite,
+			"remove":           mirrorOpts.Remove,
+			"parallel":         mirrorOpts.Parallel,
+			"require_manifest": mirrorOpts.RequireManifest,
+			"rewrite_manifest": mirrorOpts.RewriteManifest,
+		},
+	}
+
+	return PrintOutput(projection)
+}
+
+// mirrorSummary aggregates a mirror pass for PrintOutput.
+type mirrorSummary struct {
+	Src     string `json:"src"`
+	Dst     string `json:"dst"`
+	Copied  int    `json:"copied"`
+	Skipped int    `json:"skipped"`
+	Deleted int    `json:"deleted"`
+	Failed  int    `json:"failed"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// mirrorJob is one relative key queued to be copied from src to dst.
+// AmbiguousCheck marks jobs whose size+ETag comparison couldn't tell
+// identical from different (a multipart-composite ETag on either side), so
+// the worker must content-hash both sides before deciding whether to copy.
+type mirrorJob struct {
+	RelKey         string
+	SrcMeta        storage.ObjectMeta
+	AmbiguousCheck bool
+}
+
+// mirrorResult is one worker's outcome for a single relative key.
+type mirrorResult struct {
+	RelKey  string
+	Bytes   int64
+	Copied  bool
+	Skipped bool
+	Err     error
+}
+
+// indexBackend lists everything under prefix on backend and returns it keyed
+// by path relative to prefix, the comparison key both sides of a mirror need
+// to share regardless of how deep each one's own prefix is. Sidecar
+// `<key>.credlink.json` files written by a previous --rewrite-manifest pass
+// are excluded, the same way matchesSignFilters excludes them from sign.
+func indexBackend(ctx context.Context, backend storage.Backend, prefix string) (map[string]storage.ObjectMeta, error) {
+	objs, errs := backend.List(ctx, prefix)
+
+	index := make(map[string]storage.ObjectMeta)
+	for obj := range objs {
+		if strings.HasSuffix(obj.Key, ".credlink.json") {
+			continue
+		}
+		rel := strings.TrimPrefix(obj.Key, prefix)
+		if rel == "" {
+			continue
+		}
+		index[rel] = obj
+	}
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// planMirror reports what a mirror pass would copy and delete, without
+// resolving ambiguous (multipart-ETag) comparisons via a content hash - used
+// for the --dry-run projection, where no Get calls should happen.
+func planMirror(srcIndex, dstIndex map[string]storage.ObjectMeta) (toCopy, toDelete []string) {
+	for rel, srcMeta := range srcIndex {
+		dstMeta, exists := dstIndex[rel]
+		if !exists {
+			toCopy = append(toCopy, rel)
+			continue
+		}
+		if !mirrorOpts.Overwrite {
+			continue
+		}
+		if matches, _ := sizeAndETagMatch(srcMeta, dstMeta); !matches {
+			toCopy = append(toCopy, rel)
+		}
+	}
+	sort.Strings(toCopy)
+
+	if mirrorOpts.Remove {
+		for rel := range dstIndex {
+			if _, ok := srcIndex[rel]; !ok {
+				toDelete = append(toDelete, rel)
+			}
+		}
+		sort.Strings(toDelete)
+	}
+
+	return toCopy, toDelete
+}
+
+// isMultipartETag reports whether etag looks like an S3 multipart-upload
+// ETag ("<hex>-<partcount>"), which is a hash of part hashes rather than of
+// the object body and so can never be compared across uploads or backends.
+func isMultipartETag(etag string) bool {
+	return strings.Contains(strings.Trim(etag, `"`), "-")
+}
+
+// sizeAndETagMatch does a metadata-only identity check. ambiguous is true
+// when the ETags can't settle the question (one or both sides are empty or
+// multipart-composite), meaning the caller must fall back to a content hash
+// before deciding whether the objects actually differ.
+func sizeAndETagMatch(a, b storage.ObjectMeta) (matches, ambiguous bool) {
+	if a.Size != b.Size {
+		return false, false
+	}
+	if a.ETag == "" || b.ETag == "" || isMultipartETag(a.ETag) || isMultipartETag(b.ETag) {
+		return false, true
+	}
+	return a.ETag == b.ETag, false
+}
+
+// runMirrorPass lists both sides, fans the objects that need copying out to
+// a --parallel worker pool, then applies --remove deletions. It's re-invoked
+// once per --watch tick.
+func runMirrorPass(ctx context.Context, client *credlink.Client, src string, srcBackend storage.Backend, srcPrefix string, dst string, dstBackend storage.Backend, dstPrefix string, emitter *events.Emitter) (*mirrorSummary, error) {
+	srcIndex, err := indexBackend(ctx, srcBackend, srcPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("list src: %w", err)
+	}
+	dstIndex, err := indexBackend(ctx, dstBackend, dstPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("list dst: %w", err)
+	}
+
+	summary := &mirrorSummary{Src: src, Dst: dst}
+
+	var pending []mirrorJob
+	for rel, srcMeta := range srcIndex {
+		dstMeta, exists := dstIndex[rel]
+		if !exists {
+			pending = append(pending, mirrorJob{RelKey: rel, SrcMeta: srcMeta})
+			continue
+		}
+		if !mirrorOpts.Overwrite {
+			summary.Skipped++
+			continue
+		}
+		matches, ambiguous := sizeAndETagMatch(srcMeta, dstMeta)
+		if matches {
+			summary.Skipped++
+			continue
+		}
+		pending = append(pending, mirrorJob{RelKey: rel, SrcMeta: srcMeta, AmbiguousCheck: ambiguous})
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].RelKey < pending[j].RelKey })
+
+	jobs := make(chan mirrorJob)
+	results := make(chan mirrorResult)
+
+	parallel := mirrorOpts.Parallel
+	if parallel <= 0 {
+		parallel = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mirrorWorker(ctx, client, srcBackend, srcPrefix, dstBackend, dstPrefix, jobs, results)
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, job := range pending {
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for result := range results {
+		summary.Bytes += result.Bytes
+		switch {
+		case result.Err != nil:
+			summary.Failed++
+			emitter.Emit(events.Error("MIRROR_OBJECT_FAILED", fmt.Sprintf("%s: %v", result.RelKey, result.Err)))
+		case result.Skipped:
+			summary.Skipped++
+		default:
+			summary.Copied++
+			emitter.Emit(events.JobComplete(result.RelKey, "copied"))
+		}
+	}
+
+	if mirrorOpts.Remove {
+		for rel := range dstIndex {
+			if _, ok := srcIndex[rel]; ok {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				break
+			}
+			dstKey := dstPrefix + rel
+			if err := dstBackend.Delete(ctx, dstKey); err != nil {
+				summary.Failed++
+				emitter.Emit(events.Error("MIRROR_DELETE_FAILED", fmt.Sprintf("%s: %v", dstKey, err)))
+				continue
+			}
+			_ = dstBackend.Delete(ctx, dstKey+".credlink.json") // best-effort: clean up a sidecar from a prior --rewrite-manifest pass
+			summary.Deleted++
+			emitter.Emit(events.JobComplete(dstKey, "deleted"))
+		}
+	}
+
+	return summary, nil
+}
+
+func mirrorWorker(ctx context.Context, client *credlink.Client, srcBackend storage.Backend, srcPrefix string, dstBackend storage.Backend, dstPrefix string, jobs <-chan mirrorJob, results chan<- mirrorResult) {
+	for job := range jobs {
+		result := mirrorOne(ctx, client, srcBackend, srcPrefix, dstBackend, dstPrefix, job)
+		select {
+		case results <- result:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// mirrorSidecar is the `<key>.credlink.json` body --rewrite-manifest writes
+// alongside a copied object: the manifest registration response, annotated
+// with the source key so the provenance chain records the copy.
+type mirrorSidecar struct {
+	*credlink.ManifestResponse
+	Action     string `json:"action"`
+	CopiedFrom string `json:"copied_from"`
+}
+
+func mirrorOne(ctx context.Context, client *credlink.Client, srcBackend storage.Backend, srcPrefix string, dstBackend storage.Backend, dstPrefix string, job mirrorJob) mirrorResult {
+	res := mirrorResult{RelKey: job.RelKey, Bytes: job.SrcMeta.Size}
+
+	srcKey := srcPrefix + job.RelKey
+	dstKey := dstPrefix + job.RelKey
+
+	body, _, err := srcBackend.Get(ctx, srcKey)
+	if err != nil {
+		res.Err = fmt.Errorf("get %s: %w", srcKey, err)
+		return res
+	}
+	content, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		res.Err = fmt.Errorf("read %s: %w", srcKey, err)
+		return res
+	}
+
+	if job.AmbiguousCheck {
+		identical, err := contentIdentical(ctx, dstBackend, dstKey, content)
+		if err == nil && identical {
+			res.Skipped = true
+			return res
+		}
+	}
+
+	contentType := job.SrcMeta.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(job.RelKey))
+	}
+
+	if mirrorOpts.RequireManifest {
+		verified, err := verifyAssetForMirror(ctx, client, content, contentType)
+		if err != nil {
+			res.Err = fmt.Errorf("verify manifest for %s: %w", srcKey, err)
+			return res
+		}
+		if !verified {
+			res.Err = fmt.Errorf("%s: manifest failed verification, refusing to copy (--require-manifest)", srcKey)
+			return res
+		}
+	}
+
+	if err := dstBackend.Put(ctx, dstKey, bytes.NewReader(content), storage.ObjectMeta{ContentType: contentType}); err != nil {
+		res.Err = fmt.Errorf("put %s: %w", dstKey, err)
+		return res
+	}
+
+	if mirrorOpts.RewriteManifest {
+		if err := rewriteManifestForMirror(ctx, client, dstBackend, srcKey, dstKey, content); err != nil {
+			res.Err = fmt.Errorf("rewrite manifest for %s: %w", dstKey, err)
+			return res
+		}
+	}
+
+	res.Copied = true
+	return res
+}
+
+// contentIdentical content-hashes dst and compares it against src's already-
+// read bytes, the fallback sizeAndETagMatch defers to when an ETag is
+// multipart-composite.
+func contentIdentical(ctx context.Context, dstBackend storage.Backend, dstKey string, srcContent []byte) (bool, error) {
+	body, _, err := dstBackend.Get(ctx, dstKey)
+	if err != nil {
+		return false, err
+	}
+	defer body.Close()
+
+	dstContent, err := io.ReadAll(body)
+	if err != nil {
+		return false, err
+	}
+
+	srcSum := sha256.Sum256(srcContent)
+	dstSum := sha256.Sum256(dstContent)
+	return srcSum == dstSum, nil
+}
+
+// verifyAssetForMirror asks the SDK to verify content the same way verify
+// would, so --require-manifest gates a copy on the identical check inspect
+// would report for that asset.
+func verifyAssetForMirror(ctx context.Context, client *credlink.Client, content []byte, contentType string) (bool, error) {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	resp, err := client.VerifyAsset(ctx, string(content), credlink.VerifyAssetOptions{ContentType: &contentType})
+	if err != nil {
+		return false, err
+	}
+	return resp.Data.Verified, nil
+}
+
+// rewriteManifestForMirror registers a fresh manifest for the copied content
+// under the destination key and writes it as a `<key>.credlink.json`
+// sidecar, the same convention sign's sidecar uses, so the copy shows up as
+// its own c2pa.copy action in the provenance chain instead of silently
+// carrying the source's original manifest forward.
+func rewriteManifestForMirror(ctx context.Context, client *credlink.Client, dstBackend storage.Backend, srcKey, dstKey string, content []byte) error {
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+
+	manifestResp, err := client.PutManifest(ctx, digest, []byte(fmt.Sprintf(`{"action":"c2pa.copy","copied_from":%q,"object_key":%q}`, srcKey, dstKey)), credlink.PutManifestOptions{})
+	if err != nil {
+		return err
+	}
+
+	sidecar := mirrorSidecar{ManifestResponse: manifestResp, Action: "c2pa.copy", CopiedFrom: srcKey}
+	data, err := json.MarshalIndent(sidecar, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal sidecar: %w", err)
+	}
+
+	return dstBackend.Put(ctx, dstKey+".credlink.json", bytes.NewReader(data), storage.ObjectMeta{ContentType: "application/json"})
+}
@@ -1,16 +1,17 @@
 package cmd
 
 import (
-	"archive/tar"
 	"compress/gzip"
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/credlink/cli/internal/sinks"
 	"github.com/spf13/cobra"
 )
 
@@ -23,6 +24,13 @@ type PackOptions struct {
 	Exclude  []string
 	Manifest bool
 	Verbose  bool
+	Mtime    string
+	Retain   time.Duration
+
+	Recipients     []string
+	EncryptEntries []string
+
+	BlobCache string
 }
 
 var packOpts PackOptions
@@ -38,14 +46,20 @@ and provenance data. Designed for WORM storage with immutable evidence preservat
 
 	// Pack-specific flags
 	packCmd.Flags().StringVar(&packOpts.Input, "input", "", "Input glob or cloud prefix (required)")
-	packCmd.Flags().StringVar(&packOpts.Output, "out", "", "Output file (default: auto-generated)")
-	packCmd.Flags().StringVar(&packOpts.Format, "format", "tar.gz", "Output format: tar.gz|tar.zst|zip")
+	packCmd.Flags().StringVar(&packOpts.Output, "out", "", "Output file, \"-\" for stdout, or an s3://, r2://, gs:// object URI (default: auto-generated local file)")
+	packCmd.Flags().StringVar(&packOpts.Format, "format", "tar.gz", "Output format: tar.gz|tar.zst|zip|oci-layout|oci-archive")
 	packCmd.Flags().StringSliceVar(&packOpts.Include, "include", []string{}, "Additional files to include")
 	packCmd.Flags().StringSliceVar(&packOpts.Exclude, "exclude", []string{}, "Patterns to exclude")
 	packCmd.Flags().BoolVar(&packOpts.Manifest, "manifest", true, "Include detailed manifests")
 	packCmd.Flags().BoolVar(&packOpts.Verbose, "verbose", false, "Verbose output")
+	packCmd.Flags().StringVar(&packOpts.Mtime, "mtime", "", "Timestamp (RFC3339) stamped on every pack entry and metadata field; defaults to $SOURCE_DATE_EPOCH, then the current time")
+	packCmd.Flags().StringSliceVar(&packOpts.Recipients, "recipient", []string{}, "Recipient to envelope-encrypt sensitive entries for (repeatable): age1..., pgp:<file>, or jwk:<file>")
+	packCmd.Flags().StringSliceVar(&packOpts.EncryptEntries, "encrypt-entries", []string{}, "Glob(s) of entries to encrypt when --recipient is set (default: certificates.json,provenance.json)")
+	packCmd.Flags().DurationVar(&packOpts.Retain, "retain", 0, "WORM retention period applied when --out is s3:// or gs:// (S3 Object Lock COMPLIANCE mode, GCS Locked retention)")
+	packCmd.Flags().StringVar(&packOpts.BlobCache, "blob-cache", "", "Content-address entries into this directory and add a layout.json index, deduplicating work across sibling packs")
 
 	packCmd.MarkFlagRequired("input")
+	InitPackGCCommand(packCmd)
 	rootCmd.AddCommand(packCmd)
 }
 
@@ -61,8 +75,8 @@ func runPackCommand(cmd *cobra.Command, args []string) error {
 		packOpts.Output = generateOutputFilename()
 	}
 
-	PrintMsgf("Creating Compliance Pack: %s\n", packOpts.Output)
-	PrintMsgf("Input source: %s\n", packOpts.Input)
+	packProgressf("Creating Compliance Pack: %s\n", packOpts.Output)
+	packProgressf("Input source: %s\n", packOpts.Input)
 
 	// Show dry-run projection
 	if globalOpts.DryRun {
@@ -74,10 +88,22 @@ func runPackCommand(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	PrintMsgf("Compliance Pack created successfully: %s\n", packOpts.Output)
+	packProgressf("Compliance Pack created successfully: %s\n", packOpts.Output)
 	return nil
 }
 
+// packProgressf prints pack-creation progress, the same as PrintMsgf,
+// except it's routed to stderr instead of stdout when the pack itself is
+// being streamed to stdout (--out -) - otherwise log lines would interleave
+// with the archive bytes of a `pack --out - | aws s3 cp - s3://...` pipe.
+func packProgressf(format string, args ...interface{}) {
+	if packOpts.Output == sinks.Stdout {
+		PrintErrf(format, args...)
+		return
+	}
+	PrintMsgf(format, args...)
+}
+
 func validatePackInput() error {
 	if packOpts.Input == "" {
 		return fmt.Errorf("--input is required")
@@ -112,7 +138,7 @@ func validatePackInput() error {
 	}
 
 	// Validate format
-	validFormats := []string{"tar.gz", "tar.zst", "zip"}
+	validFormats := []string{"tar.gz", "tar.zst", "zip", "oci-layout", "oci-archive"}
 	valid := false
 	for _, f := range validFormats {
 		if packOpts.Format == f {
@@ -124,14 +150,18 @@ func validatePackInput() error {
 		return fmt.Errorf("invalid format: %s (valid: %v)", packOpts.Format, validFormats)
 	}
 
-	// Validate output path
-	if packOpts.Output != "" {
-		// Check for path traversal in output path
+	// Validate output path - stdout and cloud sinks have no local path to
+	// traverse, so skip the check for them.
+	if packOpts.Output != "" && packOpts.Output != sinks.Stdout && !sinks.IsCloud(packOpts.Output) {
 		if strings.Contains(packOpts.Output, "..") || strings.Contains(packOpts.Output, "../") || strings.Contains(packOpts.Output, "..\\") {
 			return fmt.Errorf("path traversal patterns not allowed in output path")
 		}
 	}
 
+	if packOpts.Retain > 0 && !sinks.IsCloud(packOpts.Output) {
+		return fmt.Errorf("--retain only applies to s3:// and gs:// --out destinations")
+	}
+
 	return nil
 }
 
@@ -141,116 +171,184 @@ func generateOutputFilename() string {
 	return fmt.Sprintf("compliance-pack-%s-%s.%s", safeInput, timestamp, packOpts.Format)
 }
 
-func createCompliancePack() error {
-	// Create output file with secure permissions
-	outFile, err := os.OpenFile(packOpts.Output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %v", err)
+// resolvePackMtime determines the single timestamp stamped into every pack
+// entry and metadata field, so two invocations of `pack` over the same
+// input produce byte-identical output regardless of wall-clock time:
+// --mtime wins if set, then SOURCE_DATE_EPOCH (the reproducible-builds.org
+// convention - a Unix timestamp in seconds), falling back to time.Now()
+// only when neither is present.
+func resolvePackMtime() (time.Time, error) {
+	if packOpts.Mtime != "" {
+		t, err := time.Parse(time.RFC3339, packOpts.Mtime)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("--mtime: %w", err)
+		}
+		return t.UTC(), nil
 	}
-	defer outFile.Close()
-
-	var writer io.Writer = outFile
 
-	// Add compression based on format
-	switch packOpts.Format {
-	case "tar.gz":
-		gzipWriter := gzip.NewWriter(outFile)
-		defer gzipWriter.Close()
-		writer = gzipWriter
-		return createTarPack(writer)
-	case "tar.zst":
-		// TODO: Implement zstd compression
-		return fmt.Errorf("zstd compression not yet implemented")
-	case "zip":
-		return createZipPack(outFile)
-	default:
-		return fmt.Errorf("unsupported format: %s", packOpts.Format)
+	if sde := os.Getenv("SOURCE_DATE_EPOCH"); sde != "" {
+		sec, err := strconv.ParseInt(sde, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("SOURCE_DATE_EPOCH: %w", err)
+		}
+		return time.Unix(sec, 0).UTC(), nil
 	}
-}
 
-func createTarPack(writer io.Writer) error {
-	tarWriter := tar.NewWriter(writer)
-	defer tarWriter.Close()
+	return time.Now().UTC(), nil
+}
 
-	// Create metadata
-	metadata := createPackMetadata()
+func createCompliancePack() error {
+	mtime, err := resolvePackMtime()
+	if err != nil {
+		return err
+	}
 
-	// Add metadata file
-	if err := addFileToTar(tarWriter, "metadata.json", metadata); err != nil {
+	entries, err := buildPackEntries(mtime)
+	if err != nil {
 		return err
 	}
 
-	// Add manifests
-	if packOpts.Manifest {
-		manifests := createMockManifests()
-		if err := addFileToTar(tarWriter, "manifests.json", manifests); err != nil {
+	if packOpts.BlobCache != "" {
+		entries, err = applyPackBlobCache(packOpts.BlobCache, entries)
+		if err != nil {
 			return err
 		}
 	}
 
-	// Add verification reports
-	reports := createMockVerificationReports()
-	if err := addFileToTar(tarWriter, "verification.json", reports); err != nil {
-		return err
-	}
+	switch packOpts.Format {
+	case "tar.gz":
+		sink, err := openPackSink()
+		if err != nil {
+			return err
+		}
+		defer sink.Close()
 
-	// Add certificate chain
-	certs := createMockCertificateChain()
-	if err := addFileToTar(tarWriter, "certificates.json", certs); err != nil {
-		return err
-	}
+		gzipWriter, err := gzip.NewWriterLevel(sink, gzip.BestCompression)
+		if err != nil {
+			return err
+		}
+		// Name/ModTime are left at their zero values so the gzip header
+		// itself doesn't leak a filename or timestamp into the output.
+		defer gzipWriter.Close()
+		return createTarPack(gzipWriter, entries, mtime)
+	case "tar.zst":
+		if packOpts.Output == sinks.Stdout || sinks.IsCloud(packOpts.Output) {
+			return fmt.Errorf("tar.zst packs need random access to build their seekable index; use a local output path, or --format tar.gz/zip to stream")
+		}
+		outFile, err := openPackOutputFile()
+		if err != nil {
+			return err
+		}
+		defer outFile.Close()
 
-	// Add provenance graph
-	provenance := createMockProvenanceGraph()
-	if err := addFileToTar(tarWriter, "provenance.json", provenance); err != nil {
-		return err
+		// Unlike tar.gz/zip, tar.zst is not a tar container at all: it's a
+		// sequence of independently-seekable zstd frames plus a trailing
+		// index, so `verify --from-pack` can pull a single entry out of a
+		// large pack without decompressing the rest. See createZstPack.
+		return createZstPack(outFile, entries, mtime)
+	case "zip":
+		sink, err := openPackSink()
+		if err != nil {
+			return err
+		}
+		defer sink.Close()
+		return createZipPack(sink, entries, mtime)
+	case "oci-layout":
+		if packOpts.Output == sinks.Stdout || sinks.IsCloud(packOpts.Output) {
+			return fmt.Errorf("oci-layout packs are a directory tree, not a single stream; use a local output path, or --format oci-archive to stream")
+		}
+		return createOCILayoutPack(packOpts.Output, entries, mtime, packOpts.BlobCache)
+	case "oci-archive":
+		sink, err := openPackSink()
+		if err != nil {
+			return err
+		}
+		defer sink.Close()
+		return createOCIArchivePack(sink, entries, mtime, packOpts.BlobCache)
+	default:
+		return fmt.Errorf("unsupported format: %s", packOpts.Format)
 	}
+}
 
-	// Add pack signature
-	signature := createPackSignature()
-	if err := addFileToTar(tarWriter, "pack.signature", signature); err != nil {
-		return err
+// openPackOutputFile creates packOpts.Output with the permissions every
+// local single-file pack format writes under. Only tar.zst still uses this
+// directly, since its writer needs to Seek; every other format goes
+// through openPackSink so --out - and cloud destinations work too.
+func openPackOutputFile() (*os.File, error) {
+	outFile, err := os.OpenFile(packOpts.Output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %v", err)
 	}
+	return outFile, nil
+}
 
-	return nil
+// openPackSink resolves packOpts.Output through the sinks package: a local
+// file by default, os.Stdout for "-", or a direct upload for s3://, r2://,
+// gs:// (with --retain applied as WORM retention when the destination
+// supports it).
+func openPackSink() (io.WriteCloser, error) {
+	return sinks.Open(context.Background(), packOpts.Output, sinks.Options{
+		Retain:      packOpts.Retain,
+		ContentType: "application/octet-stream",
+	})
 }
 
-func createZipPack(outFile *os.File) error {
-	// TODO: Implement ZIP creation
-	return fmt.Errorf("ZIP format not yet implemented")
+// packEntry is one file added to a Compliance Pack archive.
+type packEntry struct {
+	name    string
+	content interface{}
 }
 
-func addFileToTar(tarWriter *tar.Writer, filename string, content interface{}) error {
-	// Marshal content to JSON
-	data, err := json.MarshalIndent(content, "", "  ")
-	if err != nil {
-		return err
+// packEntries returns every file a Compliance Pack contains, in a fixed,
+// deterministic order - never discovered by walking a directory - so the
+// archive's member order never depends on filesystem iteration order.
+func packEntries(mtime time.Time) []packEntry {
+	entries := []packEntry{
+		{"metadata.json", createPackMetadata(mtime)},
 	}
+	if packOpts.Manifest {
+		entries = append(entries, packEntry{"manifests.json", createMockManifests()})
+	}
+	entries = append(entries,
+		packEntry{"verification.json", createMockVerificationReports()},
+		packEntry{"certificates.json", createMockCertificateChain()},
+		packEntry{"provenance.json", createMockProvenanceGraph()},
+		packEntry{"pack.signature", createPackSignature(mtime)},
+	)
+	return entries
+}
 
-	// Create tar header
-	header := &tar.Header{
-		Name:     filename,
-		Size:     int64(len(data)),
-		Mode:     0644,
-		ModTime:  time.Now(),
-		Typeflag: tar.TypeReg,
+// buildPackEntries is packEntries plus, when --recipient is set, the
+// envelope-encryption pass from applyPackEncryption.
+func buildPackEntries(mtime time.Time) ([]packEntry, error) {
+	entries := packEntries(mtime)
+	if len(packOpts.Recipients) == 0 {
+		return entries, nil
 	}
+	return applyPackEncryption(entries)
+}
 
-	// Write header
-	if err := tarWriter.WriteHeader(header); err != nil {
+func createTarPack(writer io.Writer, entries []packEntry, mtime time.Time) error {
+	w := newTarPackWriter(writer, mtime)
+	if err := writePackEntries(w, entries); err != nil {
 		return err
 	}
+	return w.Close()
+}
 
-	// Write content
-	_, err = tarWriter.Write(data)
-	return err
+func createZipPack(writer io.Writer, entries []packEntry, mtime time.Time) error {
+	w := newZipPackWriter(writer, mtime)
+	if err := writePackEntries(w, entries); err != nil {
+		return err
+	}
+	return w.Close()
 }
 
-func createPackMetadata() map[string]interface{} {
+func createPackMetadata(mtime time.Time) map[string]interface{} {
 	return map[string]interface{}{
 		"pack_type":    "compliance_pack",
 		"version":      "1.0",
-		"created_at":   time.Now().Format(time.RFC3339),
+		"created_at":   mtime.Format(time.RFC3339),
 		"created_by":   "c2c-cli v1.0.0",
 		"input_source": packOpts.Input,
 		"format":       packOpts.Format,
@@ -373,8 +471,8 @@ func createMockProvenanceGraph() map[string]interface{} {
 	}
 }
 
-func createPackSignature() string {
-	return fmt.Sprintf("c2c-pack-v1-%x-%d", time.Now().Unix(), os.Getpid())
+func createPackSignature(mtime time.Time) string {
+	return fmt.Sprintf("c2c-pack-v1-%x", mtime.Unix())
 }
 
 func runPackDryRun() error {
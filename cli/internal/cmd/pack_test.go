@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestCreateCompliancePackReproducible packs the same input twice under
+// SOURCE_DATE_EPOCH, sleeping past a second boundary in between so the two
+// runs see a different wall clock, and asserts the two output files hash
+// identically.
+func TestCreateCompliancePackReproducible(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	saved := packOpts
+	t.Cleanup(func() { packOpts = saved })
+
+	packOpts = PackOptions{
+		Input:    "test-input",
+		Format:   "tar.gz",
+		Manifest: true,
+	}
+
+	dir := t.TempDir()
+
+	buildHash := func() [sha256.Size]byte {
+		packOpts.Output = filepath.Join(dir, "pack.tar.gz")
+		if err := createCompliancePack(); err != nil {
+			t.Fatalf("createCompliancePack: %v", err)
+		}
+		data, err := os.ReadFile(packOpts.Output)
+		if err != nil {
+			t.Fatalf("read pack: %v", err)
+		}
+		return sha256.Sum256(data)
+	}
+
+	first := buildHash()
+	time.Sleep(1100 * time.Millisecond)
+	second := buildHash()
+
+	if !bytes.Equal(first[:], second[:]) {
+		t.Fatalf("pack output is not reproducible: hashes differ (%x != %x)", first, second)
+	}
+}
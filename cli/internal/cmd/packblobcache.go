@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// packBlobRef is one entry's coordinates in a pack's layout.json: enough
+// for a reader holding the same --blob-cache directory to look the entry's
+// content up by digest instead of re-reading it from the archive.
+type packBlobRef struct {
+	Name      string `json:"name"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+	MediaType string `json:"media_type"`
+}
+
+// packLayout is the layout.json payload --blob-cache adds to every pack,
+// inspired by buildah/containers' local blobcache: a compact index of every
+// entry's content-addressed digest, so overlapping sibling packs can be
+// recognized as sharing blobs without re-hashing full archives.
+type packLayout struct {
+	Entries []packBlobRef `json:"entries"`
+}
+
+// blobCacheDir returns the subdirectory a digest's blob lives under -
+// "sha256" under the cache root, mirroring the OCI Image Layout
+// blobs/sha256/ convention pack already uses for oci-layout/oci-archive.
+func blobCacheDir(cacheDir string) string {
+	return filepath.Join(cacheDir, "sha256")
+}
+
+func blobCachePath(cacheDir, digest string) string {
+	return filepath.Join(blobCacheDir(cacheDir), digest)
+}
+
+// putPackBlob content-addresses data into cacheDir, writing it only if a
+// blob with the same digest isn't already cached there. Callers still
+// marshal every entry to compute its digest; only the write is skipped
+// for a blob that's already cached.
+func putPackBlob(cacheDir string, data []byte) (digest string, size int64, err error) {
+	sum := sha256.Sum256(data)
+	digest = hex.EncodeToString(sum[:])
+	path := blobCachePath(cacheDir, digest)
+
+	if _, err := os.Stat(path); err == nil {
+		return digest, int64(len(data)), nil
+	} else if !os.IsNotExist(err) {
+		return "", 0, fmt.Errorf("stat cached blob %s: %w", digest, err)
+	}
+
+	if err := os.MkdirAll(blobCacheDir(cacheDir), 0755); err != nil {
+		return "", 0, fmt.Errorf("create blob cache directory: %w", err)
+	}
+	// Write to a temp file and rename into place so a run that's
+	// interrupted mid-write never leaves a half-written blob at its final,
+	// trusted path.
+	tmp := path + fmt.Sprintf(".tmp-%d", os.Getpid())
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return "", 0, fmt.Errorf("write cached blob %s: %w", digest, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", 0, fmt.Errorf("commit cached blob %s: %w", digest, err)
+	}
+	return digest, int64(len(data)), nil
+}
+
+// linkPackBlob hard-links cacheDir's copy of digest to dest, so writing an
+// OCI-layout blob pack already has cached is an inode-table update rather
+// than another full copy on disk. Falls back to a plain copy when the
+// cache and destination live on different filesystems, since hard links
+// can't cross devices.
+func linkPackBlob(cacheDir, digest, dest string) error {
+	src := blobCachePath(cacheDir, digest)
+	if err := os.Link(src, dest); err != nil {
+		if !errors.Is(err, syscall.EXDEV) {
+			return fmt.Errorf("link cached blob %s: %w", digest, err)
+		}
+		data, readErr := os.ReadFile(src)
+		if readErr != nil {
+			return fmt.Errorf("read cached blob %s: %w", digest, readErr)
+		}
+		return os.WriteFile(dest, data, 0644)
+	}
+	return nil
+}
+
+// applyPackBlobCache content-addresses every entry's marshaled JSON into
+// --blob-cache and appends a layout.json entry indexing them, leaving the
+// pack's own entries untouched so it stays a self-contained archive even
+// without access to the cache directory; a reader that does have the same
+// cache can use layout.json to skip re-reading entries it already holds.
+func applyPackBlobCache(cacheDir string, entries []packEntry) ([]packEntry, error) {
+	// metadata.json lists layout.json among its contents before it's
+	// hashed, so its layout digest matches the bytes the archive holds.
+	if len(entries) > 0 && entries[0].name == "metadata.json" {
+		if meta, ok := entries[0].content.(map[string]interface{}); ok {
+			meta["contents"] = append(meta["contents"].([]string), "layout.json")
+		}
+	}
+
+	var layout packLayout
+	for _, e := range entries {
+		data, err := json.MarshalIndent(e.content, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshal %s: %w", e.name, err)
+		}
+		digest, size, err := putPackBlob(cacheDir, data)
+		if err != nil {
+			return nil, fmt.Errorf("cache %s: %w", e.name, err)
+		}
+		layout.Entries = append(layout.Entries, packBlobRef{
+			Name:      e.name,
+			Digest:    digest,
+			Size:      size,
+			MediaType: packEntryMediaType(e.name),
+		})
+	}
+
+	out := append([]packEntry{}, entries...)
+	out = append(out, packEntry{name: "layout.json", content: layout})
+
+	return out, nil
+}
+
+// pruneBlobCache removes every cached blob whose file hasn't been modified
+// in at least olderThan. Pack doesn't track which blobs any still-retained
+// pack references, so - like a container builder's layer cache gc - this
+// prunes purely by age, trusting the operator's --older-than to be longer
+// than any pack they still care about rebuilding incrementally against.
+func pruneBlobCache(cacheDir string, olderThan time.Duration, dryRun bool) (pruned int, freed int64, err error) {
+	dir := blobCacheDir(cacheDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, fmt.Errorf("read blob cache: %w", err)
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			return pruned, freed, fmt.Errorf("stat cached blob %s: %w", e.Name(), err)
+		}
+		if info.ModTime().After(cutoff) {
+			continue
+		}
+		pruned++
+		freed += info.Size()
+		if dryRun {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			return pruned, freed, fmt.Errorf("remove cached blob %s: %w", e.Name(), err)
+		}
+	}
+	return pruned, freed, nil
+}
@@ -0,0 +1,638 @@
+package cmd
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultEncryptEntries is the set of pack members --encrypt-entries
+// applies to when the flag isn't given: the sensitive provenance material,
+// leaving metadata.json and pack.signature in the clear so WORM indexers
+// can still enumerate a pack's contents without any identity key.
+var defaultEncryptEntries = []string{"certificates.json", "provenance.json"}
+
+// recipientSpec is one parsed --recipient value. CredLink has no OpenPGP
+// implementation, so the pgp: form is a pragmatic stand-in that expects the
+// recipient's RSA public key in PEM rather than a full OpenPGP certificate;
+// jwk: expects a minimal RSA JSON Web Key.
+type recipientSpec struct {
+	raw    string
+	kind   string // "age" | "pgp" | "jwk"
+	x25519 *ecdh.PublicKey
+	rsa    *rsa.PublicKey
+}
+
+// identitySpec is the private-key counterpart of recipientSpec, loaded from
+// the file passed to `unpack --identity`.
+type identitySpec struct {
+	kind   string // "age" | "rsa"
+	x25519 *ecdh.PrivateKey
+	rsa    *rsa.PrivateKey
+}
+
+// wrappedKey is one recipient's copy of an entry's content-encryption key
+// (CEK), stored in that entry's sibling <entry>.enc.json.
+type wrappedKey struct {
+	Recipient          string `json:"recipient"`
+	Kind               string `json:"kind"`
+	Algorithm          string `json:"algorithm"`
+	WrappedKey         string `json:"wrapped_key"`
+	EphemeralPublicKey string `json:"ephemeral_public_key,omitempty"`
+}
+
+// packCiphertext is what an entry's own pack member holds once it has been
+// envelope-encrypted: its plaintext JSON, sealed under a fresh CEK.
+type packCiphertext struct {
+	Algorithm  string `json:"algorithm"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// packEntryEnvelope is the sibling <entry>.enc.json content: the wrapped
+// CEKs plus enough to identify which algorithm and recipients apply,
+// without which the ciphertext entry can't be opened.
+type packEntryEnvelope struct {
+	Entry          string       `json:"entry"`
+	Algorithm      string       `json:"algorithm"`
+	CEKFingerprint string       `json:"cek_fingerprint"`
+	WrappedKeys    []wrappedKey `json:"wrapped_keys"`
+}
+
+func parseRecipient(spec string) (recipientSpec, error) {
+	switch {
+	case strings.HasPrefix(spec, "age1"):
+		pub, err := decodeAgeRecipient(spec)
+		if err != nil {
+			return recipientSpec{}, fmt.Errorf("recipient %q: %w", spec, err)
+		}
+		return recipientSpec{raw: spec, kind: "age", x25519: pub}, nil
+	case strings.HasPrefix(spec, "pgp:"):
+		pub, err := readRSAPublicKeyPEM(strings.TrimPrefix(spec, "pgp:"))
+		if err != nil {
+			return recipientSpec{}, fmt.Errorf("recipient %q: %w", spec, err)
+		}
+		return recipientSpec{raw: spec, kind: "pgp", rsa: pub}, nil
+	case strings.HasPrefix(spec, "jwk:"):
+		pub, err := readRSAPublicKeyJWK(strings.TrimPrefix(spec, "jwk:"))
+		if err != nil {
+			return recipientSpec{}, fmt.Errorf("recipient %q: %w", spec, err)
+		}
+		return recipientSpec{raw: spec, kind: "jwk", rsa: pub}, nil
+	default:
+		return recipientSpec{}, fmt.Errorf("recipient %q: unrecognized form (want age1…, pgp:<file>, or jwk:<file>)", spec)
+	}
+}
+
+// parseIdentity loads the private-key counterpart of a recipient from path,
+// sniffing an age identity (AGE-SECRET-KEY-1...) vs a PEM-encoded RSA
+// private key vs a minimal RSA JWK.
+func parseIdentity(path string) (identitySpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return identitySpec{}, fmt.Errorf("read identity file: %w", err)
+	}
+	text := strings.TrimSpace(string(data))
+
+	if strings.HasPrefix(strings.ToUpper(text), "AGE-SECRET-KEY-") {
+		priv, err := decodeAgeIdentity(text)
+		if err != nil {
+			return identitySpec{}, err
+		}
+		return identitySpec{kind: "age", x25519: priv}, nil
+	}
+
+	if block, _ := pem.Decode(data); block != nil {
+		if rsaPriv, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+			return identitySpec{kind: "rsa", rsa: rsaPriv}, nil
+		}
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return identitySpec{}, fmt.Errorf("parse identity PEM: %w", err)
+		}
+		rsaPriv, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return identitySpec{}, fmt.Errorf("identity key is not RSA")
+		}
+		return identitySpec{kind: "rsa", rsa: rsaPriv}, nil
+	}
+
+	rsaPriv, err := parseRSAPrivateKeyJWK(data)
+	if err != nil {
+		return identitySpec{}, fmt.Errorf("unrecognized identity file %q: %w", path, err)
+	}
+	return identitySpec{kind: "rsa", rsa: rsaPriv}, nil
+}
+
+// readRSAPublicKeyPEM reads a PEM-encoded SubjectPublicKeyInfo and requires
+// it to wrap an RSA key.
+func readRSAPublicKeyPEM(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read public key file: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block in %q", path)
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key in %q is not RSA", path)
+	}
+	return rsaPub, nil
+}
+
+// jwkRSA is the subset of JSON Web Key fields CredLink understands: an RSA
+// key's modulus/exponent (public), plus the private exponent when loading
+// an identity rather than a recipient.
+type jwkRSA struct {
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	D   string `json:"d,omitempty"`
+}
+
+func jwkBigInt(field string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(field)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
+
+func readRSAPublicKeyJWK(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read JWK file: %w", err)
+	}
+	var jwk jwkRSA
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return nil, fmt.Errorf("decode JWK: %w", err)
+	}
+	if jwk.Kty != "RSA" {
+		return nil, fmt.Errorf("JWK kty %q is not RSA", jwk.Kty)
+	}
+	n, err := jwkBigInt(jwk.N)
+	if err != nil {
+		return nil, fmt.Errorf("decode JWK modulus: %w", err)
+	}
+	e, err := jwkBigInt(jwk.E)
+	if err != nil {
+		return nil, fmt.Errorf("decode JWK exponent: %w", err)
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func parseRSAPrivateKeyJWK(data []byte) (*rsa.PrivateKey, error) {
+	var jwk jwkRSA
+	if err := json.Unmarshal(data, &jwk); err != nil {
+		return nil, fmt.Errorf("decode JWK: %w", err)
+	}
+	if jwk.Kty != "RSA" || jwk.D == "" {
+		return nil, fmt.Errorf("not an RSA private JWK")
+	}
+	n, err := jwkBigInt(jwk.N)
+	if err != nil {
+		return nil, fmt.Errorf("decode JWK modulus: %w", err)
+	}
+	e, err := jwkBigInt(jwk.E)
+	if err != nil {
+		return nil, fmt.Errorf("decode JWK exponent: %w", err)
+	}
+	d, err := jwkBigInt(jwk.D)
+	if err != nil {
+		return nil, fmt.Errorf("decode JWK private exponent: %w", err)
+	}
+	priv := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{N: n, E: int(e.Int64())},
+		D:         d,
+	}
+	priv.Precompute()
+	return priv, nil
+}
+
+func aesGCMSeal(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func aesGCMOpen(key, sealed []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("wrapped key is too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+// ageWrapInfo is the fixed context string mixed into the age-style key
+// agreement below, scoping it to CredLink packs so a derived wrap key can
+// never be replayed against another protocol that also happens to use
+// X25519.
+const ageWrapInfo = "credlink-pack-recipient-wrap/v1"
+
+// wrapCEKForRecipient wraps cek once for r: via X25519 key agreement plus
+// AES-256-GCM for age recipients (modeled on age's own construction, though
+// not wire-compatible with it), or RSA-OAEP directly for the pgp:/jwk:
+// forms, which already resolve to an RSA public key.
+func wrapCEKForRecipient(cek []byte, r recipientSpec) (wrappedKey, error) {
+	switch r.kind {
+	case "age":
+		ephPriv, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return wrappedKey{}, fmt.Errorf("generate ephemeral key: %w", err)
+		}
+		shared, err := ephPriv.ECDH(r.x25519)
+		if err != nil {
+			return wrappedKey{}, fmt.Errorf("X25519 key agreement: %w", err)
+		}
+		wrapKey := sha256.Sum256(append(shared, []byte(ageWrapInfo)...))
+		sealed, err := aesGCMSeal(wrapKey[:], cek)
+		if err != nil {
+			return wrappedKey{}, fmt.Errorf("seal CEK: %w", err)
+		}
+		return wrappedKey{
+			Recipient:          r.raw,
+			Kind:               "age",
+			Algorithm:          "X25519+AES-256-GCM",
+			WrappedKey:         base64.StdEncoding.EncodeToString(sealed),
+			EphemeralPublicKey: base64.StdEncoding.EncodeToString(ephPriv.PublicKey().Bytes()),
+		}, nil
+	case "pgp", "jwk":
+		sealed, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, r.rsa, cek, nil)
+		if err != nil {
+			return wrappedKey{}, fmt.Errorf("RSA-OAEP wrap: %w", err)
+		}
+		return wrappedKey{
+			Recipient:  r.raw,
+			Kind:       r.kind,
+			Algorithm:  "RSA-OAEP-SHA256",
+			WrappedKey: base64.StdEncoding.EncodeToString(sealed),
+		}, nil
+	default:
+		return wrappedKey{}, fmt.Errorf("unsupported recipient kind %q", r.kind)
+	}
+}
+
+// unwrapCEK tries id against every wrapped key until one unwraps, so
+// `unpack --identity` doesn't need to know in advance which --recipient
+// entry it corresponds to.
+func unwrapCEK(wrapped []wrappedKey, id identitySpec) ([]byte, error) {
+	for _, wk := range wrapped {
+		switch {
+		case wk.Kind == "age" && id.kind == "age":
+			ephBytes, err := base64.StdEncoding.DecodeString(wk.EphemeralPublicKey)
+			if err != nil {
+				continue
+			}
+			ephPub, err := ecdh.X25519().NewPublicKey(ephBytes)
+			if err != nil {
+				continue
+			}
+			shared, err := id.x25519.ECDH(ephPub)
+			if err != nil {
+				continue
+			}
+			wrapKey := sha256.Sum256(append(shared, []byte(ageWrapInfo)...))
+			sealed, err := base64.StdEncoding.DecodeString(wk.WrappedKey)
+			if err != nil {
+				continue
+			}
+			cek, err := aesGCMOpen(wrapKey[:], sealed)
+			if err != nil {
+				continue
+			}
+			return cek, nil
+		case (wk.Kind == "pgp" || wk.Kind == "jwk") && id.kind == "rsa":
+			sealed, err := base64.StdEncoding.DecodeString(wk.WrappedKey)
+			if err != nil {
+				continue
+			}
+			cek, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, id.rsa, sealed, nil)
+			if err != nil {
+				continue
+			}
+			return cek, nil
+		}
+	}
+	return nil, fmt.Errorf("identity does not unlock any wrapped key for this entry")
+}
+
+// encryptPackEntry marshals content exactly as writePackEntries/writeZstFrame
+// would, then seals it under a fresh per-entry CEK wrapped once per
+// recipient - returning the ciphertext that replaces the entry's own pack
+// member and the envelope that becomes its <entry>.enc.json sibling.
+func encryptPackEntry(name string, content interface{}, recipients []recipientSpec) (packCiphertext, packEntryEnvelope, error) {
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return packCiphertext{}, packEntryEnvelope{}, fmt.Errorf("marshal %s: %w", name, err)
+	}
+
+	cek := make([]byte, 32)
+	if _, err := rand.Read(cek); err != nil {
+		return packCiphertext{}, packEntryEnvelope{}, fmt.Errorf("generate CEK: %w", err)
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return packCiphertext{}, packEntryEnvelope{}, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return packCiphertext{}, packEntryEnvelope{}, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return packCiphertext{}, packEntryEnvelope{}, err
+	}
+	ciphertext := gcm.Seal(nil, nonce, data, nil)
+
+	wrapped := make([]wrappedKey, 0, len(recipients))
+	for _, r := range recipients {
+		wk, err := wrapCEKForRecipient(cek, r)
+		if err != nil {
+			return packCiphertext{}, packEntryEnvelope{}, fmt.Errorf("wrap CEK for %s: %w", r.raw, err)
+		}
+		wrapped = append(wrapped, wk)
+	}
+
+	fp := sha256.Sum256(cek)
+	return packCiphertext{
+			Algorithm:  "AES-256-GCM",
+			Nonce:      base64.StdEncoding.EncodeToString(nonce),
+			Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+		}, packEntryEnvelope{
+			Entry:          name,
+			Algorithm:      "AES-256-GCM",
+			CEKFingerprint: fmt.Sprintf("sha256:%x", fp),
+			WrappedKeys:    wrapped,
+		}, nil
+}
+
+// decryptPackEntry reverses encryptPackEntry: unwrap the CEK with id, then
+// open the entry's ciphertext.
+func decryptPackEntry(ct packCiphertext, env packEntryEnvelope, id identitySpec) ([]byte, error) {
+	cek, err := unwrapCEK(env.WrappedKeys, id)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := base64.StdEncoding.DecodeString(ct.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("decode nonce: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(ct.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("decode ciphertext: %w", err)
+	}
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func matchesAny(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// applyPackEncryption encrypts every entry in entries matching
+// --encrypt-entries (default: defaultEncryptEntries) for the configured
+// --recipient list, appending each entry's <entry>.enc.json sibling and a
+// top-level encryption.json summary, and extends metadata.json's
+// "standards"/"contents" fields to mention them. metadata.json and
+// pack.signature are never encrypted themselves.
+func applyPackEncryption(entries []packEntry) ([]packEntry, error) {
+	recipients := make([]recipientSpec, 0, len(packOpts.Recipients))
+	for _, spec := range packOpts.Recipients {
+		r, err := parseRecipient(spec)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, r)
+	}
+
+	targets := packOpts.EncryptEntries
+	if len(targets) == 0 {
+		targets = defaultEncryptEntries
+	}
+
+	var out []packEntry
+	var envelopes []packEntryEnvelope
+	for _, e := range entries {
+		if e.name == "metadata.json" || e.name == "pack.signature" || !matchesAny(e.name, targets) {
+			out = append(out, e)
+			continue
+		}
+
+		ct, envelope, err := encryptPackEntry(e.name, e.content, recipients)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt %s: %w", e.name, err)
+		}
+		out = append(out, packEntry{name: e.name, content: ct})
+		out = append(out, packEntry{name: e.name + ".enc.json", content: envelope})
+		envelopes = append(envelopes, envelope)
+	}
+
+	out = append(out, packEntry{name: "encryption.json", content: createEncryptionManifest(recipients, envelopes)})
+
+	if len(out) > 0 && out[0].name == "metadata.json" {
+		if meta, ok := out[0].content.(map[string]interface{}); ok {
+			meta["standards"] = append(meta["standards"].([]string), "Recipient-encrypted (age/PGP/JWK)")
+
+			names := make([]string, 0, len(envelopes)+1)
+			for _, env := range envelopes {
+				names = append(names, env.Entry+".enc.json")
+			}
+			names = append(names, "encryption.json")
+			meta["contents"] = append(meta["contents"].([]string), names...)
+		}
+	}
+
+	return out, nil
+}
+
+func createEncryptionManifest(recipients []recipientSpec, envelopes []packEntryEnvelope) map[string]interface{} {
+	recipientSummaries := make([]map[string]interface{}, 0, len(recipients))
+	for _, r := range recipients {
+		algo := "RSA-OAEP-SHA256"
+		if r.kind == "age" {
+			algo = "X25519+AES-256-GCM"
+		}
+		recipientSummaries = append(recipientSummaries, map[string]interface{}{
+			"recipient":      r.raw,
+			"kind":           r.kind,
+			"wrap_algorithm": algo,
+		})
+	}
+
+	entrySummaries := make([]map[string]interface{}, 0, len(envelopes))
+	for _, env := range envelopes {
+		entrySummaries = append(entrySummaries, map[string]interface{}{
+			"entry":           env.Entry,
+			"algorithm":       env.Algorithm,
+			"cek_fingerprint": env.CEKFingerprint,
+		})
+	}
+
+	return map[string]interface{}{
+		"recipients": recipientSummaries,
+		"entries":    entrySummaries,
+	}
+}
+
+// --- bech32 (BIP-173), used for age1... recipients and AGE-SECRET-KEY-1...
+// identities. CredLink has no other bech32 consumer, so this is a minimal,
+// self-contained decoder rather than a pulled-in dependency.
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+func bech32Decode(s string) (hrp string, data []byte, err error) {
+	s = strings.ToLower(s)
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, fmt.Errorf("invalid bech32 string")
+	}
+	hrp = s[:pos]
+
+	values := make([]byte, len(s)-pos-1)
+	for i, c := range s[pos+1:] {
+		v := strings.IndexByte(bech32Charset, byte(c))
+		if v < 0 {
+			return "", nil, fmt.Errorf("invalid bech32 character %q", c)
+		}
+		values[i] = byte(v)
+	}
+	if len(values) < 6 {
+		return "", nil, fmt.Errorf("bech32 string too short")
+	}
+	if !bech32VerifyChecksum(hrp, values) {
+		return "", nil, fmt.Errorf("invalid bech32 checksum")
+	}
+
+	data, err = bech32ConvertBits(values[:len(values)-6], 5, 8, false)
+	return hrp, data, err
+}
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	out := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]>>5)
+	}
+	out = append(out, 0)
+	for i := 0; i < len(hrp); i++ {
+		out = append(out, hrp[i]&31)
+	}
+	return out
+}
+
+func bech32VerifyChecksum(hrp string, data []byte) bool {
+	combined := append(bech32HRPExpand(hrp), data...)
+	return bech32Polymod(combined) == 1
+}
+
+func bech32ConvertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc uint32
+	var bits uint
+	var out []byte
+	maxv := uint32(1)<<toBits - 1
+
+	for _, value := range data {
+		if uint32(value)>>fromBits != 0 {
+			return nil, fmt.Errorf("bech32 data out of range")
+		}
+		acc = acc<<fromBits | uint32(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			out = append(out, byte(acc>>bits)&byte(maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(toBits-bits))&byte(maxv))
+		}
+	} else if bits >= fromBits || byte(acc<<(toBits-bits))&byte(maxv) != 0 {
+		return nil, fmt.Errorf("bech32 invalid padding")
+	}
+	return out, nil
+}
+
+func decodeAgeRecipient(spec string) (*ecdh.PublicKey, error) {
+	hrp, data, err := bech32Decode(spec)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != "age" || len(data) != 32 {
+		return nil, fmt.Errorf("not an age1 X25519 recipient")
+	}
+	return ecdh.X25519().NewPublicKey(data)
+}
+
+func decodeAgeIdentity(spec string) (*ecdh.PrivateKey, error) {
+	hrp, data, err := bech32Decode(spec)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != "age-secret-key-" || len(data) != 32 {
+		return nil, fmt.Errorf("not an AGE-SECRET-KEY-1 X25519 identity")
+	}
+	return ecdh.X25519().NewPrivateKey(data)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// PackGCOptions holds `pack gc`'s flags.
+type PackGCOptions struct {
+	BlobCache string
+	OlderThan time.Duration
+	DryRun    bool
+}
+
+var packGCOpts PackGCOptions
+
+// InitPackGCCommand adds `pack gc`, which prunes a --blob-cache directory
+// the same way an image builder prunes its layer cache.
+func InitPackGCCommand(packCmd *cobra.Command) {
+	gcCmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Prune old blobs from a --blob-cache directory by age",
+		Long: `Remove cached blobs from a pack --blob-cache directory. pack doesn't track
+which blobs any still-useful pack references, so gc prunes purely by age:
+anything whose cache file hasn't been modified in at least --older-than is
+removed. Reusing a cached blob in a later pack doesn't refresh its age.`,
+		RunE: runPackGCCommand,
+	}
+
+	gcCmd.Flags().StringVar(&packGCOpts.BlobCache, "blob-cache", "", "Blob cache directory to prune (required)")
+	gcCmd.Flags().DurationVar(&packGCOpts.OlderThan, "older-than", 30*24*time.Hour, "Prune blobs whose cache file hasn't been modified in at least this long")
+	gcCmd.Flags().BoolVar(&packGCOpts.DryRun, "dry-run", false, "List blobs that would be pruned without deleting them")
+	gcCmd.MarkFlagRequired("blob-cache")
+
+	packCmd.AddCommand(gcCmd)
+}
+
+func runPackGCCommand(cmd *cobra.Command, args []string) error {
+	pruned, freed, err := pruneBlobCache(packGCOpts.BlobCache, packGCOpts.OlderThan, packGCOpts.DryRun)
+	if err != nil {
+		return err
+	}
+
+	result := map[string]interface{}{
+		"blob_cache":  packGCOpts.BlobCache,
+		"pruned":      pruned,
+		"freed_bytes": freed,
+		"dry_run":     packGCOpts.DryRun,
+	}
+
+	if globalOpts.JSON {
+		return PrintOutput(result)
+	}
+
+	verb := "Pruned"
+	if packGCOpts.DryRun {
+		verb = "Would prune"
+	}
+	PrintMsgf("%s %d blob(s), %d bytes freed from %s\n", verb, pruned, freed, packGCOpts.BlobCache)
+	return nil
+}
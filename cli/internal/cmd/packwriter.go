@@ -0,0 +1,350 @@
+package cmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// PackWriter is the common entry-writing surface behind every Compliance
+// Pack output format: AddEntry streams one named, media-typed member, and
+// Close finalizes the underlying container. tar.gz/zip ignore mediaType;
+// oci-layout uses it as the blob's OCI media type.
+type PackWriter interface {
+	AddEntry(name string, mediaType string, r io.Reader) error
+	Close() error
+}
+
+// packEntryMediaTypes maps a pack member to the OCI media type its content
+// is published under when the pack is written as an oci-layout/oci-archive
+// - so the layers an operator sees with `oras manifest fetch`/`skopeo
+// inspect` describe what they actually contain, not a generic JSON blob.
+var packEntryMediaTypes = map[string]string{
+	"metadata.json":     "application/vnd.credlink.compliance.metadata+json",
+	"manifests.json":    "application/vnd.credlink.compliance.manifests+json",
+	"verification.json": "application/vnd.credlink.compliance.verification+json",
+	"certificates.json": "application/vnd.credlink.compliance.certificates+json",
+	"provenance.json":   "application/vnd.credlink.compliance.provenance+json",
+	"encryption.json":   "application/vnd.credlink.compliance.encryption+json",
+}
+
+// packEntryMediaType looks up name's OCI media type, falling back to
+// generic JSON for entries packEntryMediaTypes doesn't know about (e.g. the
+// <entry>.enc.json sidecar written per recipient-encrypted entry).
+func packEntryMediaType(name string) string {
+	if mt, ok := packEntryMediaTypes[name]; ok {
+		return mt
+	}
+	return "application/json"
+}
+
+// writePackEntries marshals and writes every entry to w. pack.signature is
+// never written as a regular member of an oci-layout/oci-archive pack -
+// since a manifest layer isn't an appropriate place for a detached
+// signature - so it's set as a manifest annotation instead; tar.gz/zip have
+// no such concept and keep writing it as an ordinary entry, same as before
+// this refactor.
+func writePackEntries(w PackWriter, entries []packEntry) error {
+	for _, e := range entries {
+		if ociw, ok := w.(*ociPackWriter); ok && e.name == "pack.signature" {
+			sig, ok := e.content.(string)
+			if !ok {
+				return fmt.Errorf("pack.signature entry is not a string")
+			}
+			ociw.SetManifestAnnotation("com.credlink.pack.signature", sig)
+			continue
+		}
+
+		data, err := json.MarshalIndent(e.content, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal %s: %w", e.name, err)
+		}
+		if err := w.AddEntry(e.name, packEntryMediaType(e.name), bytes.NewReader(data)); err != nil {
+			return fmt.Errorf("add %s: %w", e.name, err)
+		}
+	}
+	return nil
+}
+
+// tarPackWriter is the PackWriter behind the tar.gz format.
+type tarPackWriter struct {
+	tw    *tar.Writer
+	mtime time.Time
+}
+
+func newTarPackWriter(w io.Writer, mtime time.Time) *tarPackWriter {
+	return &tarPackWriter{tw: tar.NewWriter(w), mtime: mtime}
+}
+
+func (w *tarPackWriter) AddEntry(name, mediaType string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	// Stable uid/gid/mode and every timestamp field pinned to mtime, so the
+	// header bytes never depend on who or when the pack was built.
+	header := &tar.Header{
+		Name:       name,
+		Size:       int64(len(data)),
+		Mode:       0644,
+		Uid:        0,
+		Gid:        0,
+		ModTime:    w.mtime,
+		AccessTime: w.mtime,
+		ChangeTime: w.mtime,
+		Typeflag:   tar.TypeReg,
+	}
+	if err := w.tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = w.tw.Write(data)
+	return err
+}
+
+func (w *tarPackWriter) Close() error {
+	return w.tw.Close()
+}
+
+// zipPackWriter is the PackWriter behind the zip format.
+type zipPackWriter struct {
+	zw    *zip.Writer
+	mtime time.Time
+}
+
+func newZipPackWriter(w io.Writer, mtime time.Time) *zipPackWriter {
+	return &zipPackWriter{zw: zip.NewWriter(w), mtime: mtime}
+}
+
+func (w *zipPackWriter) AddEntry(name, mediaType string, r io.Reader) error {
+	fw, err := w.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: w.mtime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fw, r)
+	return err
+}
+
+func (w *zipPackWriter) Close() error {
+	return w.zw.Close()
+}
+
+// ociBlobDescriptor is an OCI content descriptor: enough to locate and
+// validate one blob under blobs/sha256/.
+type ociBlobDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociManifest struct {
+	SchemaVersion int                 `json:"schemaVersion"`
+	MediaType     string              `json:"mediaType"`
+	Config        ociBlobDescriptor   `json:"config"`
+	Layers        []ociBlobDescriptor `json:"layers"`
+	Annotations   map[string]string   `json:"annotations,omitempty"`
+}
+
+type ociIndex struct {
+	SchemaVersion int                 `json:"schemaVersion"`
+	MediaType     string              `json:"mediaType"`
+	Manifests     []ociBlobDescriptor `json:"manifests"`
+}
+
+// ociPackWriter builds an OCI Image Layout directory (an oci-layout marker,
+// content-addressed blobs under blobs/sha256/, and an index.json) so a
+// finished pack is `oras push`/`skopeo copy`-able to any OCI registry, and
+// `cosign sign`/`cosign verify`-able by digest.
+type ociPackWriter struct {
+	dir         string
+	blobCache   string // --blob-cache dir, or "" to disable
+	layers      []ociBlobDescriptor
+	annotations map[string]string
+}
+
+func newOCIPackWriter(dir, blobCache string) (*ociPackWriter, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "blobs", "sha256"), 0755); err != nil {
+		return nil, fmt.Errorf("create OCI layout directories: %w", err)
+	}
+	marker := []byte(`{"imageLayoutVersion":"1.0.0"}`)
+	if err := os.WriteFile(filepath.Join(dir, "oci-layout"), marker, 0644); err != nil {
+		return nil, fmt.Errorf("write oci-layout marker: %w", err)
+	}
+	return &ociPackWriter{dir: dir, blobCache: blobCache, annotations: map[string]string{}}, nil
+}
+
+// writeBlob lands data at blobs/sha256/<digest>. When blobCache is set and
+// already holds this exact digest (a sibling pack produced the same
+// entry), it's hard-linked in instead of written again - the "hard-link
+// the existing blob into the new pack" half of --blob-cache.
+func (w *ociPackWriter) writeBlob(mediaType string, data []byte, title string) (ociBlobDescriptor, error) {
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	dest := filepath.Join(w.dir, "blobs", "sha256", digest)
+
+	if w.blobCache != "" {
+		if _, err := os.Stat(blobCachePath(w.blobCache, digest)); err == nil {
+			if err := linkPackBlob(w.blobCache, digest, dest); err != nil {
+				return ociBlobDescriptor{}, err
+			}
+		} else {
+			if err := os.WriteFile(dest, data, 0644); err != nil {
+				return ociBlobDescriptor{}, fmt.Errorf("write blob: %w", err)
+			}
+			if _, _, err := putPackBlob(w.blobCache, data); err != nil {
+				return ociBlobDescriptor{}, err
+			}
+		}
+	} else if err := os.WriteFile(dest, data, 0644); err != nil {
+		return ociBlobDescriptor{}, fmt.Errorf("write blob: %w", err)
+	}
+
+	desc := ociBlobDescriptor{
+		MediaType: mediaType,
+		Digest:    "sha256:" + digest,
+		Size:      int64(len(data)),
+	}
+	if title != "" {
+		desc.Annotations = map[string]string{"org.opencontainers.image.title": title}
+	}
+	return desc, nil
+}
+
+func (w *ociPackWriter) AddEntry(name, mediaType string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	desc, err := w.writeBlob(mediaType, data, name)
+	if err != nil {
+		return err
+	}
+	w.layers = append(w.layers, desc)
+	return nil
+}
+
+// SetManifestAnnotation records an OCI annotation on the image manifest
+// itself rather than as a layer. Not part of PackWriter - callers that know
+// they're writing an oci-layout pack (writePackEntries, for pack.signature)
+// type-assert for it.
+func (w *ociPackWriter) SetManifestAnnotation(key, value string) {
+	w.annotations[key] = value
+}
+
+func (w *ociPackWriter) Close() error {
+	configDesc, err := w.writeBlob("application/vnd.credlink.compliance.config+json", []byte("{}"), "")
+	if err != nil {
+		return err
+	}
+
+	manifestData, err := json.MarshalIndent(ociManifest{
+		SchemaVersion: 2,
+		MediaType:     "application/vnd.oci.image.manifest.v1+json",
+		Config:        configDesc,
+		Layers:        w.layers,
+		Annotations:   w.annotations,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal OCI manifest: %w", err)
+	}
+	manifestDesc, err := w.writeBlob("application/vnd.oci.image.manifest.v1+json", manifestData, "")
+	if err != nil {
+		return err
+	}
+
+	indexData, err := json.MarshalIndent(ociIndex{
+		SchemaVersion: 2,
+		MediaType:     "application/vnd.oci.image.index.v1+json",
+		Manifests:     []ociBlobDescriptor{manifestDesc},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal OCI index: %w", err)
+	}
+	return os.WriteFile(filepath.Join(w.dir, "index.json"), indexData, 0644)
+}
+
+// createOCILayoutPack writes entries as an OCI Image Layout directory at
+// dir. blobCache, if non-empty, is consulted/seeded via ociPackWriter so
+// blobs shared with sibling packs are hard-linked instead of rewritten.
+func createOCILayoutPack(dir string, entries []packEntry, mtime time.Time, blobCache string) error {
+	w, err := newOCIPackWriter(dir, blobCache)
+	if err != nil {
+		return err
+	}
+	return writePackEntries(w, entries)
+}
+
+// createOCIArchivePack builds an OCI Image Layout in a temporary directory,
+// then tars it into w - the "oci-archive" transport skopeo/oras expect: an
+// uncompressed tarball of an oci-layout directory.
+func createOCIArchivePack(w io.Writer, entries []packEntry, mtime time.Time, blobCache string) error {
+	tmpDir, err := os.MkdirTemp("", "credlink-oci-*")
+	if err != nil {
+		return fmt.Errorf("create temp OCI layout directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := createOCILayoutPack(tmpDir, entries, mtime, blobCache); err != nil {
+		return err
+	}
+
+	return tarDirectory(w, tmpDir, mtime)
+}
+
+// tarDirectory writes every regular file under dir into an uncompressed tar
+// stream in lexical walk order - which, since OCI blob names are their own
+// sha256 digest, already makes the archive's member order content-derived
+// and therefore deterministic without any extra sorting step.
+func tarDirectory(w io.Writer, dir string, mtime time.Time) error {
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		header := &tar.Header{
+			Name:       filepath.ToSlash(rel),
+			Size:       int64(len(data)),
+			Mode:       0644,
+			Uid:        0,
+			Gid:        0,
+			ModTime:    mtime,
+			AccessTime: mtime,
+			ChangeTime: mtime,
+			Typeflag:   tar.TypeReg,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+}
@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdSkippableFrameMagic is the first of the 16 zstd "skippable frame"
+// magic numbers (0x184D2A50-0x184D2A5F per the zstd format spec) - a zstd
+// decoder that doesn't recognize it simply skips frame_size bytes, so the
+// pack index frame appended after the real content frames never confuses a
+// generic zstd tool pointed at this file.
+const zstdSkippableFrameMagic uint32 = 0x184D2A50
+
+// zstPackFooterMagic/zstPackFooterSize mark the fixed-size trailer written
+// after the index skippable frame, so `verify --from-pack` can locate the
+// index without scanning the whole file: the last zstPackFooterSize bytes
+// always hold the index frame's absolute offset plus this magic.
+const zstPackFooterMagic = "CLPKTRLR"
+const zstPackFooterSize = 16 // 8-byte offset + 8-byte magic
+
+// zstEntryIndexRecord is one entry's coordinates in the index frame: enough
+// to seek straight to its zstd frame, decompress only it, and check its
+// hash without touching any other entry.
+type zstEntryIndexRecord struct {
+	Name             string `json:"name"`
+	FrameOffset      int64  `json:"frame_offset"`
+	FrameLength      int64  `json:"frame_length"`
+	UncompressedSize int64  `json:"uncompressed_size"`
+	SHA256           string `json:"sha256"`
+}
+
+// zstPackIndex is the JSON payload carried in the trailing skippable frame.
+type zstPackIndex struct {
+	Entries []zstEntryIndexRecord `json:"entries"`
+}
+
+// createZstPack writes outFile as a sequence of independently-decodable
+// zstd frames, one per entry, followed by a skippable frame
+// holding an index of every entry's {frame_offset, frame_length,
+// uncompressed_size, sha256} - so a WORM auditor can pull one report out of
+// a multi-GB pack (via extractPackEntry) without decompressing the rest.
+//
+// pack.signature is written last among the content entries, and its value
+// covers a hash of every entry that precedes it (preIndexHash below), so
+// the index itself - built from exactly those same entries, with
+// pack.signature's own record appended - is transitively covered by the
+// signature: an auditor who trusts pack.signature can recompute
+// preIndexHash from the index's records and compare.
+func createZstPack(outFile *os.File, entries []packEntry, mtime time.Time) error {
+	var index zstPackIndex
+	var entryHashes []string
+
+	for _, e := range entries {
+		if e.name == "pack.signature" {
+			continue // written last, once preIndexHash is known
+		}
+
+		rec, err := writeZstFrame(outFile, e.name, e.content)
+		if err != nil {
+			return err
+		}
+		index.Entries = append(index.Entries, rec)
+		entryHashes = append(entryHashes, rec.SHA256)
+	}
+
+	preIndexHash := sha256.Sum256([]byte(strings.Join(entryHashes, "")))
+	signature := createZstPackSignature(mtime, hex.EncodeToString(preIndexHash[:]))
+	sigRec, err := writeZstFrame(outFile, "pack.signature", signature)
+	if err != nil {
+		return err
+	}
+	index.Entries = append(index.Entries, sigRec)
+
+	return appendZstIndexFrame(outFile, index)
+}
+
+// createZstPackSignature extends the plain pack signature with a hash
+// covering every content entry that precedes it, so the index appended
+// after this entry is tamper-evident without needing its own signature.
+func createZstPackSignature(mtime time.Time, preIndexHash string) string {
+	return fmt.Sprintf("%s-idx-%s", createPackSignature(mtime), preIndexHash)
+}
+
+// writeZstFrame JSON-marshals content, writes it as its own independent
+// zstd frame at w's current offset, and returns its index record.
+func writeZstFrame(w *os.File, name string, content interface{}) (zstEntryIndexRecord, error) {
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return zstEntryIndexRecord{}, fmt.Errorf("marshal %s: %w", name, err)
+	}
+
+	offset, err := w.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return zstEntryIndexRecord{}, fmt.Errorf("seek pack: %w", err)
+	}
+
+	zw, err := zstd.NewWriter(w)
+	if err != nil {
+		return zstEntryIndexRecord{}, fmt.Errorf("create zstd frame for %s: %w", name, err)
+	}
+	if _, err := zw.Write(data); err != nil {
+		zw.Close()
+		return zstEntryIndexRecord{}, fmt.Errorf("write zstd frame for %s: %w", name, err)
+	}
+	if err := zw.Close(); err != nil {
+		return zstEntryIndexRecord{}, fmt.Errorf("close zstd frame for %s: %w", name, err)
+	}
+
+	end, err := w.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return zstEntryIndexRecord{}, fmt.Errorf("seek pack: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return zstEntryIndexRecord{
+		Name:             name,
+		FrameOffset:      offset,
+		FrameLength:      end - offset,
+		UncompressedSize: int64(len(data)),
+		SHA256:           hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// appendZstIndexFrame writes index as a zstd skippable frame (uncompressed
+// JSON - the index is tiny relative to pack contents, so there's no benefit
+// to paying zstd framing overhead twice), followed by the fixed-size
+// locator footer readZstPackIndex expects.
+func appendZstIndexFrame(w *os.File, index zstPackIndex) error {
+	payload, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("marshal pack index: %w", err)
+	}
+
+	indexOffset, err := w.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("seek pack: %w", err)
+	}
+
+	header := make([]byte, 8)
+	binary.LittleEndian.PutUint32(header[0:4], zstdSkippableFrameMagic)
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(payload)))
+	if _, err := w.Write(header); err != nil {
+		return fmt.Errorf("write index frame header: %w", err)
+	}
+	if _, err := w.Write(payload); err != nil {
+		return fmt.Errorf("write index frame payload: %w", err)
+	}
+
+	footer := make([]byte, zstPackFooterSize)
+	binary.LittleEndian.PutUint64(footer[0:8], uint64(indexOffset))
+	copy(footer[8:], zstPackFooterMagic)
+	_, err = w.Write(footer)
+	return err
+}
+
+// readZstPackIndex locates and decodes the trailing index frame via the
+// fixed-size footer appendZstIndexFrame wrote, without reading anything
+// else in f.
+func readZstPackIndex(f *os.File) (zstPackIndex, error) {
+	var index zstPackIndex
+
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return index, fmt.Errorf("seek pack: %w", err)
+	}
+	if size < zstPackFooterSize {
+		return index, fmt.Errorf("file too small to be a seekable Compliance Pack")
+	}
+
+	footer := make([]byte, zstPackFooterSize)
+	if _, err := f.ReadAt(footer, size-zstPackFooterSize); err != nil {
+		return index, fmt.Errorf("read pack footer: %w", err)
+	}
+	if string(footer[8:]) != zstPackFooterMagic {
+		return index, fmt.Errorf("not a seekable Compliance Pack (missing trailer magic)")
+	}
+	indexOffset := int64(binary.LittleEndian.Uint64(footer[0:8]))
+
+	header := make([]byte, 8)
+	if _, err := f.ReadAt(header, indexOffset); err != nil {
+		return index, fmt.Errorf("read index frame header: %w", err)
+	}
+	if magic := binary.LittleEndian.Uint32(header[0:4]); magic != zstdSkippableFrameMagic {
+		return index, fmt.Errorf("index frame has unexpected magic %#x", magic)
+	}
+	payloadLen := binary.LittleEndian.Uint32(header[4:8])
+
+	payload := make([]byte, payloadLen)
+	if _, err := f.ReadAt(payload, indexOffset+8); err != nil {
+		return index, fmt.Errorf("read index frame payload: %w", err)
+	}
+
+	if err := json.Unmarshal(payload, &index); err != nil {
+		return index, fmt.Errorf("decode pack index: %w", err)
+	}
+	return index, nil
+}
+
+// extractPackEntry opens packPath, reads its trailing index frame, seeks
+// directly to entryName's zstd frame, decompresses only that frame, and
+// validates its hash against the index - none of the pack's other entries
+// are ever read or decompressed.
+func extractPackEntry(packPath, entryName string) ([]byte, error) {
+	f, err := os.Open(packPath)
+	if err != nil {
+		return nil, fmt.Errorf("open pack: %w", err)
+	}
+	defer f.Close()
+
+	index, err := readZstPackIndex(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var rec *zstEntryIndexRecord
+	for i := range index.Entries {
+		if index.Entries[i].Name == entryName {
+			rec = &index.Entries[i]
+			break
+		}
+	}
+	if rec == nil {
+		return nil, fmt.Errorf("entry %q not found in pack index", entryName)
+	}
+
+	zr, err := zstd.NewReader(io.NewSectionReader(f, rec.FrameOffset, rec.FrameLength))
+	if err != nil {
+		return nil, fmt.Errorf("open zstd frame for %q: %w", entryName, err)
+	}
+	defer zr.Close()
+
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("decompress %q: %w", entryName, err)
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != rec.SHA256 {
+		return nil, fmt.Errorf("entry %q failed hash verification", entryName)
+	}
+
+	return data, nil
+}
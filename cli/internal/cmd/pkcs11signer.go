@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strconv"
+
+	"github.com/miekg/pkcs11"
+)
+
+// pkcs11Signer implements crypto.Signer over a key held in a PKCS#11 token
+// (an HSM or a software token like SoftHSM), so the private key material
+// never leaves the token: every Sign call is a C_Sign round trip through
+// the module rather than a local operation.
+type pkcs11Signer struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	object  pkcs11.ObjectHandle
+	pub     crypto.PublicKey
+	isECDSA bool
+}
+
+// newPKCS11Signer resolves ref (everything after "pkcs11://") into a module
+// path, slot, PIN, and object label, opens a session against it, and logs
+// in so the returned signer is ready to use. ref is a query-string-shaped
+// reference, e.g. "module=/usr/lib/softhsm/libsofthsm2.so&slot=0&pin=1234&label=signer1".
+// leafPub, the signer's certificate chain leaf public key, is used as-is for
+// EC keys instead of reconstructing a curve from the token's CKA_EC_POINT/
+// CKA_EC_PARAMS attributes, since the cert already carries it.
+func newPKCS11Signer(ref string, leafPub crypto.PublicKey) (*pkcs11Signer, error) {
+	values, err := url.ParseQuery(ref)
+	if err != nil {
+		return nil, fmt.Errorf("parse pkcs11:// reference: %w", err)
+	}
+
+	modulePath := values.Get("module")
+	if modulePath == "" {
+		return nil, fmt.Errorf("pkcs11:// reference missing module=")
+	}
+	label := values.Get("label")
+	if label == "" {
+		return nil, fmt.Errorf("pkcs11:// reference missing label=")
+	}
+	pin := values.Get("pin")
+	slot := uint(0)
+	if s := values.Get("slot"); s != "" {
+		n, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("pkcs11:// reference has invalid slot %q: %w", s, err)
+		}
+		slot = uint(n)
+	}
+
+	p := pkcs11.New(modulePath)
+	if p == nil {
+		return nil, fmt.Errorf("load PKCS#11 module %s", modulePath)
+	}
+	if err := p.Initialize(); err != nil {
+		return nil, fmt.Errorf("initialize PKCS#11 module %s: %w", modulePath, err)
+	}
+
+	session, err := p.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		return nil, fmt.Errorf("open PKCS#11 session on slot %d: %w", slot, err)
+	}
+	if pin != "" {
+		if err := p.Login(session, pkcs11.CKU_USER, pin); err != nil {
+			return nil, fmt.Errorf("login to PKCS#11 slot %d: %w", slot, err)
+		}
+	}
+
+	privHandle, err := findPKCS11Object(p, session, pkcs11.CKO_PRIVATE_KEY, label)
+	if err != nil {
+		return nil, err
+	}
+	pubHandle, err := findPKCS11Object(p, session, pkcs11.CKO_PUBLIC_KEY, label)
+	if err != nil {
+		return nil, err
+	}
+
+	pub, isECDSA, err := pkcs11PublicKey(p, session, pubHandle, leafPub)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pkcs11Signer{ctx: p, session: session, object: privHandle, pub: pub, isECDSA: isECDSA}, nil
+}
+
+// findPKCS11Object looks up the single object of class with the given
+// label, the conventional way a PKCS#11 token names a key pair's public and
+// private halves identically.
+func findPKCS11Object(p *pkcs11.Ctx, session pkcs11.SessionHandle, class uint, label string) (pkcs11.ObjectHandle, error) {
+	tmpl := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, class),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := p.FindObjectsInit(session, tmpl); err != nil {
+		return 0, fmt.Errorf("find PKCS#11 object %q: %w", label, err)
+	}
+	defer p.FindObjectsFinal(session)
+
+	handles, _, err := p.FindObjects(session, 1)
+	if err != nil {
+		return 0, fmt.Errorf("find PKCS#11 object %q: %w", label, err)
+	}
+	if len(handles) == 0 {
+		return 0, fmt.Errorf("PKCS#11 object %q not found", label)
+	}
+	return handles[0], nil
+}
+
+// pkcs11PublicKey reads the key type off handle to decide whether it's RSA
+// or EC. RSA's modulus/exponent are reconstructed directly from the token's
+// attributes; EC just reuses leafPub, since reconstructing a curve from
+// CKA_EC_POINT/CKA_EC_PARAMS would just reproduce what the certificate
+// already carries. The returned bool reports whether the signer is EC, so
+// Sign knows which mechanism to use.
+func pkcs11PublicKey(p *pkcs11.Ctx, session pkcs11.SessionHandle, handle pkcs11.ObjectHandle, leafPub crypto.PublicKey) (crypto.PublicKey, bool, error) {
+	attrs, err := p.GetAttributeValue(session, handle, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, nil),
+		pkcs11.NewAttribute(pkcs11.CKA_MODULUS, nil),
+		pkcs11.NewAttribute(pkcs11.CKA_PUBLIC_EXPONENT, nil),
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("read PKCS#11 public key attributes: %w", err)
+	}
+
+	keyType := attrs[0].Value
+	isRSA := len(keyType) > 0 && keyType[0] == byte(pkcs11.CKK_RSA)
+	if isRSA {
+		mod := new(big.Int).SetBytes(attrs[1].Value)
+		exp := new(big.Int).SetBytes(attrs[2].Value)
+		return &rsa.PublicKey{N: mod, E: int(exp.Int64())}, false, nil
+	}
+
+	if leafPub == nil {
+		return nil, true, fmt.Errorf("EC key has no certificate chain leaf public key to use")
+	}
+	return leafPub, true, nil
+}
+
+func (s *pkcs11Signer) Public() crypto.PublicKey {
+	return s.pub
+}
+
+// Sign computes the signature over digest using the token's private key via
+// C_Sign, selecting the mechanism from opts.HashFunc() and the key family.
+func (s *pkcs11Signer) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var mechanism uint
+	switch {
+	case s.isECDSA:
+		mechanism = pkcs11.CKM_ECDSA
+	case opts.HashFunc() == crypto.SHA256:
+		// digest is already hashed, so it's signed as a SHA-256 DigestInfo
+		// with plain CKM_RSA_PKCS; CKM_SHA256_RSA_PKCS would hash it again.
+		mechanism = pkcs11.CKM_RSA_PKCS
+		prefix := []byte{0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20}
+		digest = append(prefix, digest...)
+	default:
+		return nil, fmt.Errorf("pkcs11 signer only supports SHA-256 digests, got %v", opts.HashFunc())
+	}
+
+	if err := s.ctx.SignInit(s.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(mechanism, nil)}, s.object); err != nil {
+		return nil, fmt.Errorf("pkcs11 SignInit: %w", err)
+	}
+	sig, err := s.ctx.Sign(s.session, digest)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11 Sign: %w", err)
+	}
+	return sig, nil
+}
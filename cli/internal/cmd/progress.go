@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// isTerminal reports whether f is attached to a terminal rather than a
+// pipe/file/redirect, without pulling in a platform-specific ioctl
+// dependency: a char device is the one bit every OS's file mode exposes
+// consistently enough for this.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// progressBar renders a single-line, carriage-return-redrawn progress bar
+// to stderr - a filled bar plus items/sec, ETA, and success/fail counters,
+// pb.ProgressBar-style. It's a no-op when disabled, so callers can always
+// call update/finish unconditionally.
+type progressBar struct {
+	out     io.Writer
+	total   int
+	start   time.Time
+	enabled bool
+	width   int
+}
+
+// newProgressBar builds a bar for total items. enabled false makes every
+// call a no-op; the batch commands pass false when stdout isn't a terminal
+// or --no-progress was passed, and diff --recursive when stderr isn't a
+// terminal, so scripted/piped runs never get control characters mixed into
+// their output.
+func newProgressBar(total int, enabled bool) *progressBar {
+	return &progressBar{out: os.Stderr, total: total, start: time.Now(), enabled: enabled, width: 30}
+}
+
+// update redraws the bar in place for the given counts. processed must be
+// the count of entries actually finished (success or failure), not the
+// count dispatched to workers.
+func (p *progressBar) update(processed, successes, failures int) {
+	if !p.enabled {
+		return
+	}
+
+	elapsed := time.Since(p.start).Seconds()
+	var rate float64
+	if elapsed > 0 {
+		rate = float64(processed) / elapsed
+	}
+
+	var pct float64
+	filled := 0
+	if p.total > 0 {
+		pct = float64(processed) / float64(p.total) * 100
+		filled = p.width * processed / p.total
+		if filled > p.width {
+			filled = p.width
+		}
+	}
+
+	eta := "?"
+	if rate > 0 && p.total > processed {
+		remaining := float64(p.total-processed) / rate
+		eta = time.Duration(remaining * float64(time.Second)).Round(time.Second).String()
+	}
+
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", p.width-filled)
+	fmt.Fprintf(p.out, "\r[%s] %d/%d (%.1f%%) %.1f/s eta %s ok=%d fail=%d ",
+		bar, processed, p.total, pct, rate, eta, successes, failures)
+}
+
+// finish prints a trailing line so later output doesn't land on top of the
+// bar, distinguishing a clean finish from an abort.
+func (p *progressBar) finish(aborted bool) {
+	if !p.enabled {
+		return
+	}
+	if aborted {
+		fmt.Fprintln(p.out, "\naborted")
+		return
+	}
+	fmt.Fprintln(p.out)
+}
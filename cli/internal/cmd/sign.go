@@ -1,26 +1,33 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"path/filepath"
-	"strconv"
+	"os/signal"
 	"strings"
+	"syscall"
 
+	"github.com/credlink/cli/internal/safepath"
+	"github.com/credlink/cli/pkg/storage"
 	"github.com/spf13/cobra"
 )
 
 // Sign command options
 type SignOptions struct {
-	TSA         bool
-	Recursive   bool
-	Concurrency int
-	Resume      bool
-	Inject      bool
-	Patterns    []string
-	MinBytes    int64
-	MaxBytes    int64
-	TypeFilter  string
+	TSA          bool
+	TSAURL       string
+	TSAPolicy    string
+	TSACertChain string
+	TSACostUSD   float64
+	Recursive    bool
+	Concurrency  int
+	Resume       bool
+	Inject       bool
+	Patterns     []string
+	MinBytes     int64
+	MaxBytes     int64
+	TypeFilter   string
 }
 
 var signOpts SignOptions
@@ -38,6 +45,10 @@ injection for HTML files.`,
 
 	// Sign-specific flags
 	signCmd.Flags().BoolVar(&signOpts.TSA, "tsa", false, "Enable RFC-3161 TSA timestamps")
+	signCmd.Flags().StringVar(&signOpts.TSAURL, "tsa-url", "https://freetsa.org/tsr", "RFC-3161 TSA endpoint URL")
+	signCmd.Flags().StringVar(&signOpts.TSAPolicy, "tsa-policy", "", "Required TSA policy OID (dotted form), empty accepts any")
+	signCmd.Flags().StringVar(&signOpts.TSACertChain, "tsa-cert-chain", "", "PEM file of trusted roots for the TSA's signing certificate (defaults to system roots)")
+	signCmd.Flags().Float64Var(&signOpts.TSACostUSD, "tsa-cost-usd", 0.002, "Per-timestamp cost used for dry-run cost projection")
 	signCmd.Flags().BoolVar(&signOpts.Recursive, "recursive", true, "Process subdirectories")
 	signCmd.Flags().IntVar(&signOpts.Concurrency, "concurrency", 4, "Parallel upload concurrency")
 	signCmd.Flags().BoolVar(&signOpts.Resume, "resume", false, "Resume interrupted operation")
@@ -56,29 +67,47 @@ func runSignCommand(cmd *cobra.Command, args []string) error {
 	// Validate inputs
 	if err := validateSignInput(path); err != nil {
 		PrintErrf("Input error: %v\n", err)
-		return err
+		return ErrInputValidation
+	}
+
+	backend, prefix, err := storage.Factory(path)
+	if err != nil {
+		PrintErrf("Input error: %v\n", err)
+		return ErrInputValidation
 	}
 
 	// Show dry-run projection
 	if globalOpts.DryRun {
-		return runSignDryRun(path)
+		return runSignDryRun(cmd.Context(), path, backend, prefix)
+	}
+
+	client, err := newSDKClient()
+	if err != nil {
+		PrintErrf("%v\n", err)
+		return ErrAuthentication
 	}
+	defer client.Close()
+
+	// A Ctrl-C must stop the worker pool instead of leaving uploads hanging.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// Execute signing
 	PrintMsgf("Starting sign operation for: %s\n", path)
 
-	result := map[string]interface{}{
-		"path":   path,
-		"tsa":    signOpts.TSA,
-		"job_id": "sign-" + generateSignJobID(),
-		"status": "started",
+	summary, err := runSignPipeline(ctx, client, backend, prefix, newEventEmitter())
+	if err != nil {
+		PrintErrf("Sign operation failed: %v\n", err)
+		return mapSDKError(err)
 	}
 
-	if err := PrintOutput(result); err != nil {
+	if err := PrintOutput(summary); err != nil {
 		return err
 	}
 
-	// TODO: Implement actual signing logic
+	if summary.Failed > 0 {
+		return ErrPartialFailure
+	}
+
 	PrintMsg("Sign operation completed successfully")
 	return nil
 }
@@ -88,81 +117,67 @@ func validateSignInput(path string) error {
 		return fmt.Errorf("path cannot be empty")
 	}
 
-	// Check for path traversal attempts in local paths
-	if !strings.HasPrefix(path, "s3://") && !strings.HasPrefix(path, "r2://") {
-		// Resolve to absolute path to detect traversal
-		absPath, err := filepath.Abs(path)
-		if err != nil {
-			return fmt.Errorf("invalid path")
-		}
+	if _, _, hasScheme := strings.Cut(path, "://"); !hasScheme {
+		return safepath.ValidateLocalPath(path)
+	}
 
-		// Check for path traversal
-		if strings.Contains(path, "..") {
-			// For relative paths, check if they go outside current directory
-			cwd, err := os.Getwd()
-			if err != nil {
-				return fmt.Errorf("cannot determine current directory")
-			}
-
-			// If the resolved path is not under current directory, it's traversal
-			if !strings.HasPrefix(absPath, cwd) {
-				return fmt.Errorf("path traversal detected: access outside current directory not allowed")
-			}
-		}
+	_, err := safepath.ParseCloudPath(path)
+	return err
+}
 
-		// Additional check for suspicious patterns
-		if strings.Contains(path, "../") || strings.Contains(path, "..\\") {
-			return fmt.Errorf("path traversal patterns not allowed")
+// runSignDryRun projects what a real sign run would do by calling List then
+// Head on every matched object - no Get/Put/sign calls are made - so the
+// estimate reflects the actual object count and byte total instead of a
+// hard-coded placeholder.
+func runSignDryRun(ctx context.Context, path string, backend storage.Backend, prefix string) error {
+	objs, listErrs := backend.List(ctx, prefix)
+
+	matched := 0
+	var totalBytes int64
+	headCalls := 0
+	for obj := range objs {
+		if !matchesSignFilters(obj) {
+			continue
 		}
-	}
-
-	// Validate cloud path format
-	if strings.HasPrefix(path, "s3://") || strings.HasPrefix(path, "r2://") {
-		var prefix string
-		if strings.HasPrefix(path, "s3://") {
-			prefix = "s3://"
+		matched++
+		headCalls++
+		if meta, err := backend.Head(ctx, obj.Key); err == nil {
+			totalBytes += meta.Size
 		} else {
-			prefix = "r2://"
-		}
-		parts := strings.SplitN(strings.TrimPrefix(path, prefix), "/", 2)
-		if len(parts) < 2 {
-			if prefix == "s3://" {
-				return fmt.Errorf("invalid S3 path format, expected: s3://bucket/prefix")
-			} else {
-				return fmt.Errorf("invalid R2 path format, expected: r2://account/bucket/prefix")
-			}
-		}
-		if parts[0] == "" {
-			return fmt.Errorf("bucket name cannot be empty")
-		}
-		// Check for path traversal in cloud paths
-		if strings.Contains(parts[1], "..") {
-			return fmt.Errorf("path traversal not allowed in cloud paths")
+			totalBytes += obj.Size
 		}
 	}
+	if err := <-listErrs; err != nil {
+		return fmt.Errorf("list %s: %w", prefix, err)
+	}
 
-	return nil
-}
+	tsaRequests := 0
+	tsaCost := 0.0
+	if signOpts.TSA {
+		tsaRequests = matched
+		tsaCost = float64(matched) * signOpts.TSACostUSD
+	}
 
-func runSignDryRun(path string) error {
 	projection := map[string]interface{}{
 		"operation": "sign",
 		"path":      path,
+		"backend":   backend.Name(),
+		"bucket":    backend.Bucket(),
 		"dry_run":   true,
 		"estimates": map[string]interface{}{
-			"files_to_sign": 150,
+			"files_to_sign": matched,
 			"requests": map[string]int{
-				"list":  5,
-				"get":   150,
-				"put":   150,
-				"sign":  150,
-				"total": 455,
+				"list":  1,
+				"head":  headCalls,
+				"get":   matched,
+				"put":   matched,
+				"sign":  matched,
+				"total": 1 + headCalls + matched*3,
 			},
-			"size_estimate": "2.3GB",
-			"duration":      "15m",
-			"tsa_requests": map[string]int{
-				"timestamps": 150,
-				"cost_usd":   7,
+			"size_estimate": formatBytes(totalBytes),
+			"tsa_requests": map[string]interface{}{
+				"timestamps": tsaRequests,
+				"cost_usd":   tsaCost,
 			},
 		},
 		"options": map[string]interface{}{
@@ -175,7 +190,3 @@ func runSignDryRun(path string) error {
 
 	return PrintOutput(projection)
 }
-
-func generateSignJobID() string {
-	return strconv.FormatInt(int64(os.Getpid()), 10)
-}
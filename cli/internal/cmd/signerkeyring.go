@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// signerKeyEntry is one signer's config in a --signer-config file: a key
+// reference (a local PEM path, or a kms://.../pkcs11://... URI so the key
+// never touches disk) plus the PEM certificate chain to embed in signed
+// manifests.
+type signerKeyEntry struct {
+	Key       string `json:"key"`
+	CertChain string `json:"cert_chain"`
+}
+
+// loadedSigner is a signerKeyEntry resolved into a usable crypto.Signer and
+// parsed certificate chain, cached for the lifetime of the batch sign run.
+type loadedSigner struct {
+	ID        string
+	Signer    crypto.Signer
+	CertChain []string // PEM-encoded, leaf first
+}
+
+// signerKeyring loads signer key material from a --signer-config file once
+// per signer_id and caches it, so a feed with thousands of entries sharing a
+// handful of signers only ever touches the KMS/PKCS11/filesystem key source
+// once per signer, not once per entry.
+type signerKeyring struct {
+	mu     sync.Mutex
+	config map[string]signerKeyEntry
+	loaded map[string]*loadedSigner
+}
+
+// newSignerKeyring reads configPath, a JSON object of signer_id ->
+// {key, cert_chain}.
+func newSignerKeyring(configPath string) (*signerKeyring, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("read --signer-config: %w", err)
+	}
+
+	config := make(map[string]signerKeyEntry)
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parse --signer-config: %w", err)
+	}
+
+	return &signerKeyring{
+		config: config,
+		loaded: make(map[string]*loadedSigner),
+	}, nil
+}
+
+// resolve returns the loadedSigner for signerID, loading and caching it on
+// first use.
+func (k *signerKeyring) resolve(ctx context.Context, signerID string) (*loadedSigner, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if signer, ok := k.loaded[signerID]; ok {
+		return signer, nil
+	}
+
+	entry, ok := k.config[signerID]
+	if !ok {
+		return nil, fmt.Errorf("signer_id %q not found in --signer-config", signerID)
+	}
+
+	chainPEM, err := os.ReadFile(entry.CertChain)
+	if err != nil {
+		return nil, fmt.Errorf("read cert_chain for signer_id %q: %w", signerID, err)
+	}
+	chain, leafCert, err := parseCertChainPEM(chainPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parse cert_chain for signer_id %q: %w", signerID, err)
+	}
+
+	keySigner, err := resolveSignerKey(ctx, entry.Key, leafCert.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("resolve key for signer_id %q: %w", signerID, err)
+	}
+
+	loaded := &loadedSigner{ID: signerID, Signer: keySigner, CertChain: chain}
+	k.loaded[signerID] = loaded
+	return loaded, nil
+}
+
+// resolveSignerKey dispatches ref to the matching key source: kms:// for a
+// KMS-backed signer (the key material never leaves the KMS), pkcs11:// for
+// an HSM-backed signer (the key material never leaves the token), or a bare
+// path to a local PEM private key file. leafPub is the signer's certificate
+// chain leaf public key, used by the PKCS#11 source as a fallback for key
+// types it can't reconstruct from token attributes alone (EC points).
+func resolveSignerKey(ctx context.Context, ref string, leafPub crypto.PublicKey) (crypto.Signer, error) {
+	switch {
+	case strings.HasPrefix(ref, "kms://"):
+		return newKMSSigner(ctx, strings.TrimPrefix(ref, "kms://"))
+	case strings.HasPrefix(ref, "pkcs11://"):
+		return newPKCS11Signer(strings.TrimPrefix(ref, "pkcs11://"), leafPub)
+	default:
+		return loadLocalPEMSigner(ref)
+	}
+}
+
+// loadLocalPEMSigner reads a PEM-encoded private key from disk. It's the
+// fallback key source for operators who aren't using KMS or an HSM.
+func loadLocalPEMSigner(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file %s: %w", path, err)
+	}
+	cert, err := tls.X509KeyPair(data, data)
+	if err != nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("parse key file %s: no PEM block found", path)
+		}
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse key file %s: %w", path, err)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("key file %s does not hold a signing key", path)
+		}
+		return signer, nil
+	}
+	signer, ok := cert.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("key file %s does not hold a signing key", path)
+	}
+	return signer, nil
+}
+
+// parseCertChainPEM splits a PEM bundle into its individual certificates,
+// re-encoded to PEM, leaf first, for embedding in a signed manifest, and
+// also returns the parsed leaf certificate itself.
+func parseCertChainPEM(data []byte) (chain []string, leaf *x509.Certificate, err error) {
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		if leaf == nil {
+			leaf = cert
+		}
+		chain = append(chain, string(pem.EncodeToMemory(block)))
+	}
+	if len(chain) == 0 {
+		return nil, nil, fmt.Errorf("no CERTIFICATE blocks found")
+	}
+	return chain, leaf, nil
+}
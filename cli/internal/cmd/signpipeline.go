@@ -0,0 +1,332 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/credlink/cli/pkg/storage"
+	"github.com/credlink/cli/pkg/tsa"
+	"github.com/credlink/sdk/go/credlink"
+	"github.com/credlink/sdk/go/credlink/events"
+)
+
+// signObject is one listed object queued for the sign worker pool.
+type signObject struct {
+	storage.ObjectMeta
+}
+
+// signObjectResult is one worker's outcome for a single object.
+type signObjectResult struct {
+	Key     string
+	Bytes   int64
+	Signed  bool
+	Skipped bool
+	Err     error
+}
+
+// signPipelineSummary aggregates a cloud/local sign run for PrintOutput.
+type signPipelineSummary struct {
+	Backend string `json:"backend"`
+	Bucket  string `json:"bucket,omitempty"`
+	Prefix  string `json:"prefix"`
+	Matched int    `json:"matched"`
+	Signed  int    `json:"signed"`
+	Skipped int    `json:"skipped"`
+	Failed  int    `json:"failed"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// runSignPipeline lists everything under prefix on backend, filters it by
+// the sign command's pattern/size/type flags, and fans the remainder out to
+// a --concurrency worker pool that gets each object, computes its content
+// hash, registers a manifest for it, and puts a `<key>.credlink.json`
+// sidecar back through the same backend. With --resume, objects already
+// recorded in the local checkpoint (keyed by backend+bucket+key+etag) are
+// skipped instead of re-signed. With --tsa, each object's digest is also
+// stamped by an RFC 3161 Time-Stamp Authority and the token is embedded in
+// the sidecar.
+func runSignPipeline(ctx context.Context, client *credlink.Client, backend storage.Backend, prefix string, emitter *events.Emitter) (*signPipelineSummary, error) {
+	var checkpoint *storage.Checkpoint
+	if signOpts.Resume {
+		path := checkpointPath(backend, prefix)
+		cp, err := storage.LoadCheckpoint(path)
+		if err != nil {
+			return nil, fmt.Errorf("load resume checkpoint: %w", err)
+		}
+		checkpoint = cp
+
+		if info, statErr := os.Stat(path); statErr == nil {
+			cacheDir := getCacheDir()
+			if relPath, relErr := filepath.Rel(cacheDir, path); relErr == nil {
+				_ = recordCacheAccess(cacheDir, relPath, info.Size())
+			}
+		}
+	}
+
+	var tsaClient *tsa.Client
+	if signOpts.TSA {
+		tc, err := buildTSAClient()
+		if err != nil {
+			return nil, fmt.Errorf("configure TSA client: %w", err)
+		}
+		tsaClient = tc
+	}
+
+	objs, listErrs := backend.List(ctx, prefix)
+
+	jobs := make(chan signObject)
+	results := make(chan signObjectResult)
+
+	concurrency := signOpts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			signWorker(ctx, client, backend, tsaClient, checkpoint, jobs, results)
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for obj := range objs {
+			if !matchesSignFilters(obj) {
+				continue
+			}
+			select {
+			case jobs <- signObject{obj}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	summary := &signPipelineSummary{Backend: backend.Name(), Bucket: backend.Bucket(), Prefix: prefix}
+	for result := range results {
+		summary.Matched++
+		summary.Bytes += result.Bytes
+		switch {
+		case result.Err != nil:
+			summary.Failed++
+			emitter.Emit(events.Error("SIGN_OBJECT_FAILED", fmt.Sprintf("%s: %v", result.Key, result.Err)))
+		case result.Skipped:
+			summary.Skipped++
+		default:
+			summary.Signed++
+			emitter.Emit(events.JobComplete(result.Key, "signed"))
+		}
+	}
+
+	if err := <-listErrs; err != nil {
+		return summary, fmt.Errorf("list %s: %w", prefix, err)
+	}
+
+	return summary, nil
+}
+
+func signWorker(ctx context.Context, client *credlink.Client, backend storage.Backend, tsaClient *tsa.Client, checkpoint *storage.Checkpoint, jobs <-chan signObject, results chan<- signObjectResult) {
+	for obj := range jobs {
+		result := signOne(ctx, client, backend, tsaClient, checkpoint, obj)
+		select {
+		case results <- result:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// signSidecar is the `<key>.credlink.json` body written alongside a signed
+// object: the manifest registration response, plus an embedded TSA token
+// when --tsa is set.
+type signSidecar struct {
+	*credlink.ManifestResponse
+	TSA *signTSAInfo `json:"tsa,omitempty"`
+}
+
+// signTSAInfo is the embeddable form of a tsa.Token.
+type signTSAInfo struct {
+	Time         string `json:"time"`
+	SerialNumber string `json:"serial_number"`
+	Policy       string `json:"policy,omitempty"`
+	Token        []byte `json:"token"`
+}
+
+func signOne(ctx context.Context, client *credlink.Client, backend storage.Backend, tsaClient *tsa.Client, checkpoint *storage.Checkpoint, obj signObject) signObjectResult {
+	res := signObjectResult{Key: obj.Key, Bytes: obj.Size}
+
+	ckKey := storage.CheckpointKey{Backend: backend.Name(), Bucket: backend.Bucket(), Key: obj.Key, ETag: obj.ETag}
+	if checkpoint != nil && checkpoint.Done(ckKey) {
+		res.Skipped = true
+		return res
+	}
+
+	body, meta, err := backend.Get(ctx, obj.Key)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	defer body.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, body); err != nil {
+		res.Err = fmt.Errorf("hash %s: %w", obj.Key, err)
+		return res
+	}
+	digestBytes := hash.Sum(nil)
+	digest := hex.EncodeToString(digestBytes)
+
+	manifestResp, err := client.PutManifest(ctx, digest, []byte(fmt.Sprintf(`{"object_key":%q,"size":%d}`, obj.Key, meta.Size)), credlink.PutManifestOptions{})
+	if err != nil {
+		res.Err = fmt.Errorf("put manifest for %s: %w", obj.Key, err)
+		return res
+	}
+
+	sidecarData := signSidecar{ManifestResponse: manifestResp}
+	if tsaClient != nil {
+		token, err := tsaClient.Stamp(ctx, digestBytes)
+		if err != nil {
+			res.Err = fmt.Errorf("tsa stamp for %s: %w", obj.Key, err)
+			return res
+		}
+		sidecarData.TSA = &signTSAInfo{
+			Time:         token.Time.UTC().Format("2006-01-02T15:04:05Z"),
+			SerialNumber: token.SerialNumber.String(),
+			Policy:       token.Policy.String(),
+			Token:        token.Raw,
+		}
+	}
+
+	sidecar, err := json.MarshalIndent(sidecarData, "", "  ")
+	if err != nil {
+		res.Err = fmt.Errorf("marshal sidecar for %s: %w", obj.Key, err)
+		return res
+	}
+
+	sidecarKey := obj.Key + ".credlink.json"
+	if err := backend.Put(ctx, sidecarKey, strings.NewReader(string(sidecar)), storage.ObjectMeta{ContentType: "application/json"}); err != nil {
+		res.Err = fmt.Errorf("put sidecar for %s: %w", obj.Key, err)
+		return res
+	}
+
+	if checkpoint != nil {
+		if err := checkpoint.Mark(ckKey); err != nil {
+			res.Err = fmt.Errorf("mark checkpoint for %s: %w", obj.Key, err)
+			return res
+		}
+	}
+
+	res.Signed = true
+	return res
+}
+
+// matchesSignFilters applies the sign command's --patterns/--min-bytes/
+// --max-bytes/--type flags to a listed object.
+func matchesSignFilters(obj storage.ObjectMeta) bool {
+	if strings.HasSuffix(obj.Key, ".credlink.json") {
+		return false
+	}
+	if len(signOpts.Patterns) > 0 {
+		matched := false
+		base := filepath.Base(obj.Key)
+		for _, pattern := range signOpts.Patterns {
+			if ok, _ := filepath.Match(pattern, base); ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if signOpts.MinBytes > 0 && obj.Size < signOpts.MinBytes {
+		return false
+	}
+	if signOpts.MaxBytes > 0 && obj.Size > signOpts.MaxBytes {
+		return false
+	}
+	if signOpts.TypeFilter != "" && !matchesTypeFilter(obj.ContentType, signOpts.TypeFilter) {
+		return false
+	}
+	return true
+}
+
+func matchesTypeFilter(contentType, typeFilter string) bool {
+	if contentType == "" {
+		return true
+	}
+	return strings.HasPrefix(contentType, typeFilter+"/")
+}
+
+// buildTSAClient constructs a tsa.Client from the --tsa-url/--tsa-policy/
+// --tsa-cert-chain flags.
+func buildTSAClient() (*tsa.Client, error) {
+	client := &tsa.Client{URL: signOpts.TSAURL}
+
+	if signOpts.TSAPolicy != "" {
+		oid, err := parseOID(signOpts.TSAPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("parse --tsa-policy: %w", err)
+		}
+		client.Policy = oid
+	}
+
+	if signOpts.TSACertChain != "" {
+		pem, err := os.ReadFile(signOpts.TSACertChain)
+		if err != nil {
+			return nil, fmt.Errorf("read --tsa-cert-chain: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("--tsa-cert-chain %s contains no usable certificates", signOpts.TSACertChain)
+		}
+		client.Roots = pool
+	}
+
+	return client, nil
+}
+
+// parseOID parses a dotted-decimal OID string (e.g. "1.2.3.4") into an
+// asn1.ObjectIdentifier.
+func parseOID(s string) (asn1.ObjectIdentifier, error) {
+	parts := strings.Split(s, ".")
+	oid := make(asn1.ObjectIdentifier, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OID component %q", p)
+		}
+		oid[i] = n
+	}
+	return oid, nil
+}
+
+// checkpointPath derives a stable local checkpoint file for a (backend,
+// bucket, prefix) triple, under the same XDG cache directory the manifest
+// cache uses.
+func checkpointPath(backend storage.Backend, prefix string) string {
+	sum := sha256.Sum256([]byte(backend.Name() + "|" + backend.Bucket() + "|" + prefix))
+	name := hex.EncodeToString(sum[:16])
+	dir := filepath.Join(getCacheDir(), "sign-checkpoints")
+	_ = os.MkdirAll(dir, 0755)
+	return filepath.Join(dir, name+".json")
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// VerifyAggregate summarizes the outcome of a (possibly multi-asset)
+// verification run so a --threshold expression can be evaluated against it.
+type VerifyAggregate struct {
+	Total    int
+	Verified int
+	Failed   int
+}
+
+// Metrics exposes the aggregate as the named variables the threshold DSL
+// understands. survival and verified_ratio are both verified/total, kept as
+// two names since "survival" reads better for content-moderation use cases
+// and "verified_ratio" for everything else; failed is the raw failure count.
+func (a VerifyAggregate) Metrics() map[string]float64 {
+	ratio := 0.0
+	if a.Total > 0 {
+		ratio = float64(a.Verified) / float64(a.Total)
+	}
+	return map[string]float64{
+		"survival":       ratio,
+		"verified_ratio": ratio,
+		"failed":         float64(a.Failed),
+	}
+}
+
+// thresholdExpr is a parsed --threshold expression such as
+// "survival>=0.999 && failed<=2".
+type thresholdExpr struct {
+	terms []thresholdTerm
+	// ops[i] joins terms[i] and terms[i+1]; one of "&&", "||".
+	ops []string
+}
+
+type thresholdTerm struct {
+	variable string
+	operator string
+	value    float64
+}
+
+var thresholdTermPattern = regexp.MustCompile(`^\s*(survival|verified_ratio|failed)\s*(>=|<=|==|!=|>|<)\s*([0-9]*\.?[0-9]+)\s*$`)
+
+// parseThresholdExpr parses a --threshold DSL expression. Supported
+// variables are survival, verified_ratio, and failed; terms are joined with
+// && or || and evaluated left-to-right (no operator precedence or
+// parentheses, which keeps the grammar simple enough to document in --help).
+func parseThresholdExpr(expr string) (*thresholdExpr, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("empty threshold expression")
+	}
+
+	rawTerms, ops := splitThresholdOps(expr)
+	if len(rawTerms) == 0 {
+		return nil, fmt.Errorf("no terms found in threshold expression %q", expr)
+	}
+
+	parsed := &thresholdExpr{ops: ops}
+	for _, raw := range rawTerms {
+		m := thresholdTermPattern.FindStringSubmatch(raw)
+		if m == nil {
+			return nil, fmt.Errorf("invalid threshold term %q (expected e.g. survival>=0.999)", strings.TrimSpace(raw))
+		}
+		value, err := strconv.ParseFloat(m[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold value in %q: %w", raw, err)
+		}
+		parsed.terms = append(parsed.terms, thresholdTerm{variable: m[1], operator: m[2], value: value})
+	}
+
+	return parsed, nil
+}
+
+// splitThresholdOps splits expr on && and || in left-to-right order,
+// returning the terms between operators and the operators themselves.
+func splitThresholdOps(expr string) (terms []string, ops []string) {
+	remaining := expr
+	for {
+		andIdx := strings.Index(remaining, "&&")
+		orIdx := strings.Index(remaining, "||")
+
+		cut := -1
+		op := ""
+		switch {
+		case andIdx == -1 && orIdx == -1:
+			cut = -1
+		case andIdx == -1:
+			cut, op = orIdx, "||"
+		case orIdx == -1:
+			cut, op = andIdx, "&&"
+		case andIdx < orIdx:
+			cut, op = andIdx, "&&"
+		default:
+			cut, op = orIdx, "||"
+		}
+
+		if cut == -1 {
+			terms = append(terms, remaining)
+			return terms, ops
+		}
+
+		terms = append(terms, remaining[:cut])
+		ops = append(ops, op)
+		remaining = remaining[cut+2:]
+	}
+}
+
+// Eval evaluates the expression against a metrics map (see
+// VerifyAggregate.Metrics).
+func (e *thresholdExpr) Eval(metrics map[string]float64) bool {
+	if len(e.terms) == 0 {
+		return false
+	}
+
+	result := e.terms[0].eval(metrics)
+	for i, op := range e.ops {
+		next := e.terms[i+1].eval(metrics)
+		if op == "&&" {
+			result = result && next
+		} else {
+			result = result || next
+		}
+	}
+	return result
+}
+
+func (t thresholdTerm) eval(metrics map[string]float64) bool {
+	actual, ok := metrics[t.variable]
+	if !ok {
+		return false
+	}
+	switch t.operator {
+	case ">=":
+		return actual >= t.value
+	case "<=":
+		return actual <= t.value
+	case ">":
+		return actual > t.value
+	case "<":
+		return actual < t.value
+	case "==":
+		return actual == t.value
+	case "!=":
+		return actual != t.value
+	default:
+		return false
+	}
+}
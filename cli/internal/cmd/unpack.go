@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Unpack command options
+type UnpackOptions struct {
+	Output   string
+	Identity string
+}
+
+var unpackOpts UnpackOptions
+
+func InitUnpackCommand(rootCmd *cobra.Command) {
+	var unpackCmd = &cobra.Command{
+		Use:   "unpack <pack-file>",
+		Short: "Extract a Compliance Pack, decrypting any recipient-encrypted entries",
+		Long: `Extract a Compliance Pack produced by "pack". If the pack was built with
+--recipient, every encrypted entry's <entry>.enc.json sibling is used to
+unwrap its content-encryption key with --identity, and the decrypted entry
+is written in place of its ciphertext. Only tar.gz and tar.zst packs are
+read; zip and OCI packs are not.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runUnpackCommand,
+	}
+
+	unpackCmd.Flags().StringVar(&unpackOpts.Output, "out", "", "Output directory (default: derived from the pack filename)")
+	unpackCmd.Flags().StringVar(&unpackOpts.Identity, "identity", "", "Identity key file to decrypt recipient-encrypted entries")
+
+	rootCmd.AddCommand(unpackCmd)
+}
+
+func runUnpackCommand(cmd *cobra.Command, args []string) error {
+	packPath := args[0]
+
+	raw, err := readPackEntries(packPath)
+	if err != nil {
+		return err
+	}
+
+	outDir := unpackOpts.Output
+	if outDir == "" {
+		outDir = defaultUnpackDir(packPath)
+	}
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+
+	var identity *identitySpec
+	if unpackOpts.Identity != "" {
+		id, err := parseIdentity(unpackOpts.Identity)
+		if err != nil {
+			return fmt.Errorf("--identity: %w", err)
+		}
+		identity = &id
+	}
+
+	envelopes := make(map[string]packEntryEnvelope, len(raw))
+	for name, data := range raw {
+		if !strings.HasSuffix(name, ".enc.json") {
+			continue
+		}
+		var env packEntryEnvelope
+		if err := json.Unmarshal(data, &env); err != nil {
+			return fmt.Errorf("decode %q: %w", name, err)
+		}
+		envelopes[env.Entry] = env
+	}
+
+	for name, data := range raw {
+		if strings.HasSuffix(name, ".enc.json") || name == "encryption.json" {
+			continue // encryption bookkeeping, not restored pack content
+		}
+
+		out := data
+		if env, ok := envelopes[name]; ok {
+			if identity == nil {
+				return fmt.Errorf("entry %q is recipient-encrypted; pass --identity to decrypt it", name)
+			}
+			var ct packCiphertext
+			if err := json.Unmarshal(data, &ct); err != nil {
+				return fmt.Errorf("decode ciphertext for %q: %w", name, err)
+			}
+			plain, err := decryptPackEntry(ct, env, *identity)
+			if err != nil {
+				return fmt.Errorf("decrypt %q: %w", name, err)
+			}
+			out = plain
+		}
+
+		if err := os.WriteFile(filepath.Join(outDir, name), out, 0644); err != nil {
+			return fmt.Errorf("write %q: %w", name, err)
+		}
+	}
+
+	PrintMsgf("Extracted Compliance Pack to %s\n", outDir)
+	return nil
+}
+
+func defaultUnpackDir(packPath string) string {
+	base := filepath.Base(packPath)
+	for _, suffix := range []string{".tar.gz", ".tar.zst", ".zip"} {
+		if strings.HasSuffix(base, suffix) {
+			base = strings.TrimSuffix(base, suffix)
+			break
+		}
+	}
+	return base + "-unpacked"
+}
+
+// readPackEntries reads every member of a tar.gz or tar.zst Compliance Pack
+// into memory, keyed by entry name, regardless of which format produced it.
+func readPackEntries(packPath string) (map[string][]byte, error) {
+	switch {
+	case strings.HasSuffix(packPath, ".tar.gz"):
+		return readTarGzPackEntries(packPath)
+	case strings.HasSuffix(packPath, ".tar.zst"):
+		return readZstPackEntries(packPath)
+	default:
+		return nil, fmt.Errorf("unsupported pack format for %q (want .tar.gz or .tar.zst)", packPath)
+	}
+}
+
+func readTarGzPackEntries(packPath string) (map[string][]byte, error) {
+	f, err := os.Open(packPath)
+	if err != nil {
+		return nil, fmt.Errorf("open pack: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("open gzip stream: %w", err)
+	}
+	defer gz.Close()
+
+	out := map[string][]byte{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read tar entry: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("read %q: %w", hdr.Name, err)
+		}
+		out[hdr.Name] = data
+	}
+	return out, nil
+}
+
+func readZstPackEntries(packPath string) (map[string][]byte, error) {
+	f, err := os.Open(packPath)
+	if err != nil {
+		return nil, fmt.Errorf("open pack: %w", err)
+	}
+	defer f.Close()
+
+	index, err := readZstPackIndex(f)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string][]byte, len(index.Entries))
+	for _, rec := range index.Entries {
+		data, err := extractPackEntry(packPath, rec.Name)
+		if err != nil {
+			return nil, err
+		}
+		out[rec.Name] = data
+	}
+	return out, nil
+}
@@ -1,24 +1,44 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"mime"
 	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/credlink/cli/internal/safepath"
+	"github.com/credlink/cli/pkg/storage"
+	"github.com/credlink/sdk/go/credlink"
+	"github.com/credlink/sdk/go/credlink/events"
 	"github.com/spf13/cobra"
 )
 
 // Verify command options
 type VerifyOptions struct {
-	Page        bool
-	Stream      bool
-	Threshold   string
-	FollowLinks bool
-	MaxDepth    int
-	Prefix      bool
-	Delimiter   string
+	Page          bool
+	Stream        bool
+	Threshold     string
+	FollowLinks   bool
+	MaxDepth      int
+	Prefix        bool
+	Delimiter     string
+	Silent        bool
+	NoProgress    bool
+	RetryTimeout  time.Duration
+	RetrySleep    time.Duration
+	Concurrency   int
+	ObjectTimeout time.Duration
+	AWSProfile    string
+	FromPack      string
+	Entry         string
 }
 
 var verifyOpts VerifyOptions
@@ -30,7 +50,7 @@ func InitVerifyCommand(rootCmd *cobra.Command) {
 		Long: `Verify single or multiple assets with cryptographic provenance checks.
 Can crawl web pages to discover assets, supports streaming NDJSON output,
 and provides machine-readable results for CI/CD pipelines.`,
-		Args: cobra.ExactArgs(1),
+		Args: cobra.MaximumNArgs(1),
 		RunE: runVerifyCommand,
 	}
 
@@ -42,11 +62,29 @@ and provides machine-readable results for CI/CD pipelines.`,
 	verifyCmd.Flags().IntVar(&verifyOpts.MaxDepth, "max-depth", 2, "Maximum depth for page crawl")
 	verifyCmd.Flags().BoolVar(&verifyOpts.Prefix, "prefix", false, "Verify all objects with prefix")
 	verifyCmd.Flags().StringVar(&verifyOpts.Delimiter, "delimiter", "/", "Delimiter for prefix listing")
+	verifyCmd.Flags().BoolVar(&verifyOpts.Silent, "silent", false, "Suppress progress output entirely")
+	verifyCmd.Flags().BoolVar(&verifyOpts.NoProgress, "no-progress", false, "Print status lines instead of a progress bar")
+	verifyCmd.Flags().DurationVar(&verifyOpts.RetryTimeout, "retry-timeout", 0, "Keep retrying until --threshold is met or this much time has elapsed")
+	verifyCmd.Flags().DurationVar(&verifyOpts.RetrySleep, "retry-sleep", 5*time.Second, "Time to sleep between retry attempts")
+	verifyCmd.Flags().IntVar(&verifyOpts.Concurrency, "concurrency", 8, "Parallel workers for --prefix verification")
+	verifyCmd.Flags().DurationVar(&verifyOpts.ObjectTimeout, "object-timeout", 30*time.Second, "Per-object timeout for --prefix verification")
+	// Named aws-profile rather than profile since --profile is already the
+	// global signing-profile flag.
+	verifyCmd.Flags().StringVar(&verifyOpts.AWSProfile, "aws-profile", "", "Named AWS/R2 credentials profile for --prefix verification")
+	verifyCmd.Flags().StringVar(&verifyOpts.FromPack, "from-pack", "", "Verify a single entry from a seekable tar.zst Compliance Pack, without a target")
+	verifyCmd.Flags().StringVar(&verifyOpts.Entry, "entry", "", "Entry name to extract with --from-pack")
 
 	rootCmd.AddCommand(verifyCmd)
 }
 
 func runVerifyCommand(cmd *cobra.Command, args []string) error {
+	if verifyOpts.FromPack != "" {
+		return mapSDKError(runVerifyFromPack())
+	}
+
+	if len(args) != 1 {
+		return fmt.Errorf("accepts 1 arg(s), received %d", len(args))
+	}
 	target := args[0]
 
 	// Validate inputs
@@ -63,12 +101,78 @@ func runVerifyCommand(cmd *cobra.Command, args []string) error {
 	// Execute verification
 	PrintMsgf("Starting verification for: %s\n", target)
 
+	if verifyOpts.Threshold != "" {
+		return mapSDKError(runVerifyWithThreshold(target))
+	}
+
+	_, err := runVerification(target)
+	return mapSDKError(err)
+}
+
+// runVerification dispatches to the configured verification mode and
+// reports back an aggregate so callers (plain and --threshold retry alike)
+// can both act on it.
+func runVerification(target string) (*VerifyAggregate, error) {
 	if verifyOpts.Page {
 		return runPageVerification(target)
 	} else if verifyOpts.Prefix {
 		return runPrefixVerification(target)
-	} else {
-		return runSingleVerification(target)
+	}
+	return runSingleVerification(target)
+}
+
+// runVerifyWithThreshold re-runs verification, sleeping --retry-sleep
+// between attempts, until the --threshold expression evaluates true or
+// --retry-timeout elapses. This is meant for CI pipelines verifying assets
+// right after publish, while provenance manifests are still propagating
+// through CDNs.
+func runVerifyWithThreshold(target string) error {
+	expr, err := parseThresholdExpr(verifyOpts.Threshold)
+	if err != nil {
+		return fmt.Errorf("invalid threshold expression: %w", err)
+	}
+
+	sleep := verifyOpts.RetrySleep
+	if sleep <= 0 {
+		sleep = 5 * time.Second
+	}
+
+	start := time.Now()
+	attempt := 0
+	for {
+		attempt++
+		fmt.Fprintf(os.Stderr, "Attempt #%d\n", attempt)
+
+		// A partial-failure error (some assets unverified) is expected while
+		// polling for propagation and is carried in agg for the threshold to
+		// judge; only a nil aggregate (client/network/listing failure) means
+		// retrying won't help.
+		agg, vErr := runVerification(target)
+		if agg == nil {
+			if vErr != nil {
+				return vErr
+			}
+			return fmt.Errorf("verification produced no result")
+		}
+
+		if expr.Eval(agg.Metrics()) {
+			PrintMsgf("Threshold %q met on attempt #%d\n", verifyOpts.Threshold, attempt)
+			return nil
+		}
+
+		elapsed := time.Since(start)
+		if elapsed+sleep > verifyOpts.RetryTimeout {
+			if verifyOpts.RetryTimeout > 0 {
+				PrintErrf("Threshold %q not met within %s (%d/%d verified, %d failed)\n",
+					verifyOpts.Threshold, verifyOpts.RetryTimeout, agg.Verified, agg.Total, agg.Failed)
+				return ErrRetryTimeout
+			}
+			PrintErrf("Threshold %q not met (%d/%d verified, %d failed)\n",
+				verifyOpts.Threshold, agg.Verified, agg.Total, agg.Failed)
+			return ErrThresholdNotMet
+		}
+
+		time.Sleep(sleep)
 	}
 }
 
@@ -89,60 +193,35 @@ func validateVerifyInput(target string) error {
 		return nil
 	}
 
-	// Check for path traversal attempts in local paths
-	if !strings.HasPrefix(target, "s3://") && !strings.HasPrefix(target, "r2://") {
-		// Resolve to absolute path to detect traversal
-		absPath, err := filepath.Abs(target)
-		if err != nil {
-			return fmt.Errorf("invalid path")
-		}
+	if _, _, hasScheme := strings.Cut(target, "://"); !hasScheme {
+		return safepath.ValidateLocalPath(target)
+	}
 
-		// Check for path traversal
-		if strings.Contains(target, "..") {
-			// For relative paths, check if they go outside current directory
-			cwd, err := os.Getwd()
-			if err != nil {
-				return fmt.Errorf("cannot determine current directory")
-			}
+	_, err := safepath.ParseCloudPath(target)
+	return err
+}
 
-			// If the resolved path is not under current directory, it's traversal
-			if !strings.HasPrefix(absPath, cwd) {
-				return fmt.Errorf("path traversal detected: access outside current directory not allowed")
-			}
-		}
+// runVerifyFromPack extracts and verifies a single named entry from a
+// seekable tar.zst Compliance Pack (see createZstPack) without decompressing
+// or reading any of the pack's other entries - useful for pulling one report
+// out of a multi-GB pack.
+func runVerifyFromPack() error {
+	if verifyOpts.Entry == "" {
+		return fmt.Errorf("--entry is required with --from-pack")
+	}
 
-		// Additional check for suspicious patterns
-		if strings.Contains(target, "../") || strings.Contains(target, "..\\") {
-			return fmt.Errorf("path traversal patterns not allowed")
-		}
+	data, err := extractPackEntry(verifyOpts.FromPack, verifyOpts.Entry)
+	if err != nil {
+		return err
 	}
 
-	// Validate cloud path format
-	if strings.HasPrefix(target, "s3://") || strings.HasPrefix(target, "r2://") {
-		var prefix string
-		if strings.HasPrefix(target, "s3://") {
-			prefix = "s3://"
-		} else {
-			prefix = "r2://"
-		}
-		parts := strings.SplitN(strings.TrimPrefix(target, prefix), "/", 2)
-		if len(parts) < 2 {
-			if prefix == "s3://" {
-				return fmt.Errorf("invalid S3 path format, expected: s3://bucket/prefix")
-			} else {
-				return fmt.Errorf("invalid R2 path format, expected: r2://account/bucket/prefix")
-			}
-		}
-		if parts[0] == "" {
-			return fmt.Errorf("bucket name cannot be empty")
-		}
-		// Check for path traversal in cloud paths
-		if strings.Contains(parts[1], "..") {
-			return fmt.Errorf("path traversal not allowed in cloud paths")
-		}
+	var content interface{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return fmt.Errorf("entry %q is not valid pack content: %w", verifyOpts.Entry, err)
 	}
 
-	return nil
+	PrintMsgf("Entry %q verified against the pack index (%d bytes)\n", verifyOpts.Entry, len(data))
+	return PrintOutput(content)
 }
 
 func runVerifyDryRun(target string) error {
@@ -202,63 +281,217 @@ func runVerifyDryRun(target string) error {
 	return PrintOutput(projection)
 }
 
-func runPageVerification(url string) error {
-	PrintMsgf("Crawling page: %s\n", url)
+func runPageVerification(pageURL string) (*VerifyAggregate, error) {
+	PrintMsgf("Crawling page: %s\n", pageURL)
 
-	result := map[string]interface{}{
-		"url":          url,
-		"job_id":       "verify-page-" + generateVerifyJobID(),
-		"status":       "crawling",
-		"assets_found": 0,
+	client, err := newSDKClient()
+	if err != nil {
+		return nil, err
 	}
+	defer client.Close()
+
+	// A Ctrl-C here must stop the local crawl loop rather than leaving the
+	// process hanging until the underlying HTTP stream is fully drained.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	followLinks := verifyOpts.FollowLinks
+	maxDepth := verifyOpts.MaxDepth
+	stream, err := client.VerifyPage(ctx, pageURL, credlink.VerifyPageOptions{
+		FollowLinks: &followLinks,
+		MaxDepth:    &maxDepth,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
 
-	if err := PrintOutput(result); err != nil {
-		return err
+	verified, total := 0, 0
+	for {
+		result, err := stream.Next(ctx)
+		if err != nil {
+			break
+		}
+		total++
+		if result.Verified {
+			verified++
+		}
+		renderAssetProgress(total, verified, result)
+	}
+
+	agg := &VerifyAggregate{Total: total, Verified: verified, Failed: total - verified}
+
+	if err := ctx.Err(); err != nil {
+		return agg, fmt.Errorf("page verification canceled: %w", err)
 	}
 
-	// TODO: Implement actual page crawling and verification
-	PrintMsg("Page verification completed")
-	return nil
+	PrintMsgf("Page verification completed: %d/%d assets verified\n", verified, total)
+	if verified < total {
+		return agg, fmt.Errorf("%d assets failed verification", total-verified)
+	}
+	return agg, nil
 }
 
-func runPrefixVerification(prefix string) error {
-	PrintMsgf("Verifying prefix: %s\n", prefix)
+// renderAssetProgress prints one line per asset (--no-progress/--silent
+// aware) since VerifyPage streams results rather than exposing a job ID that
+// JobRunner could poll. In --output ndjson/json mode it emits one
+// events.TypeAssetVerified record per asset instead, regardless of
+// --silent/--no-progress, so CI consumers always get a parseable record.
+func renderAssetProgress(total, verified int, result credlink.VerifyPageResult) {
+	assetURL := ""
+	if result.URL != nil {
+		assetURL = *result.URL
+	}
 
-	result := map[string]interface{}{
-		"prefix":        prefix,
-		"job_id":        "verify-prefix-" + generateVerifyJobID(),
-		"status":        "listing",
-		"objects_found": 0,
+	if mode := outputMode(); mode == events.OutputNDJSON || mode == events.OutputJSON {
+		errHint := ""
+		if result.Error != nil {
+			errHint = *result.Error
+		}
+		manifestID := ""
+		if result.ManifestURL != nil {
+			manifestID = *result.ManifestURL
+		}
+		newEventEmitter().Emit(events.AssetVerified(assetURL, result.Verified, manifestID, errHint))
+		return
 	}
 
-	if err := PrintOutput(result); err != nil {
-		return err
+	if verifyOpts.Silent {
+		return
 	}
 
-	// TODO: Implement actual prefix verification
-	PrintMsg("Prefix verification completed")
-	return nil
+	if verifyOpts.NoProgress {
+		if result.Verified {
+			PrintMsgf("  [%d] verified: %s\n", total, assetURL)
+		} else {
+			PrintErrf("  [%d] failed: %s\n", total, assetURL)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "\rverified %d/%d assets", verified, total)
 }
 
-func generateVerifyJobID() string {
-	return fmt.Sprintf("verify-%d", os.Getpid())
+func runPrefixVerification(prefix string) (*VerifyAggregate, error) {
+	PrintMsgf("Verifying prefix: %s\n", prefix)
+
+	client, err := newSDKClient()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	idempotencyKey := globalOpts.IdempotencyKey
+	if idempotencyKey == "" {
+		idempotencyKey = prefix
+	}
+
+	verifier := credlink.NewPrefixVerifier(client, credlink.PrefixVerifierOptions{
+		Delimiter:        verifyOpts.Delimiter,
+		Concurrency:      verifyOpts.Concurrency,
+		PerObjectTimeout: verifyOpts.ObjectTimeout,
+		Profile:          verifyOpts.AWSProfile,
+		Stream:           verifyOpts.Stream,
+		IdempotencyKey:   idempotencyKey,
+	})
+
+	summary, err := verifier.Run(ctx, prefix)
+	if summary == nil {
+		return nil, err
+	}
+
+	agg := &VerifyAggregate{Total: summary.Total, Verified: summary.Verified, Failed: summary.Failed}
+
+	if err != nil {
+		return agg, fmt.Errorf("prefix verification interrupted: %w", err)
+	}
+
+	PrintMsgf("Prefix verification completed: %d/%d objects verified\n", summary.Verified, summary.Total)
+	if summary.Failed > 0 {
+		return agg, fmt.Errorf("%d objects failed verification", summary.Failed)
+	}
+	return agg, nil
 }
 
-func runSingleVerification(target string) error {
+// runSingleVerification verifies one asset (local path, cloud URI, or
+// http(s) URL) through the SDK's real verification endpoint, the same
+// client call runPrefixVerification's workers make per object.
+func runSingleVerification(target string) (*VerifyAggregate, error) {
 	PrintMsgf("Verifying asset: %s\n", target)
 
-	result := map[string]interface{}{
-		"target":   target,
-		"job_id":   "verify-" + generateVerifyJobID(),
-		"status":   "verifying",
-		"verified": false,
+	client, err := newSDKClient()
+	if err != nil {
+		return nil, err
 	}
+	defer client.Close()
 
-	if err := PrintOutput(result); err != nil {
-		return err
+	// A Ctrl-C must cancel an in-flight request/upload rather than leaving
+	// the process hanging on the network call.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	resp, err := verifyAssetByTarget(ctx, client, target)
+	if err != nil {
+		return nil, fmt.Errorf("verify %s: %w", target, err)
+	}
+
+	manifestURL := ""
+	if resp.Data.ManifestURL != nil {
+		manifestURL = *resp.Data.ManifestURL
+	}
+
+	if mode := outputMode(); mode == events.OutputNDJSON || mode == events.OutputJSON {
+		newEventEmitter().Emit(events.AssetVerified(target, resp.Data.Verified, manifestURL, ""))
+	} else if !verifyOpts.Silent {
+		if resp.Data.Verified {
+			PrintMsgf("Asset verification completed: verified (%s)\n", target)
+		} else {
+			PrintErrf("Asset verification completed: not verified (%s)\n", target)
+		}
+	}
+
+	if resp.Data.Verified {
+		return &VerifyAggregate{Total: 1, Verified: 1}, nil
+	}
+	return &VerifyAggregate{Total: 1, Failed: 1}, fmt.Errorf("asset failed verification: %s", target)
+}
+
+// verifyAssetByTarget calls VerifyAsset the way the target was addressed:
+// an http(s):// target is passed straight through as a URL (the server
+// fetches it itself), while a local path or cloud URI is resolved via
+// storage.Factory and its bytes sent as the buffer, so the server verifies
+// the content it's handed instead of fetching it.
+func verifyAssetByTarget(ctx context.Context, client *credlink.Client, target string) (*credlink.VerifyAssetResponse, error) {
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		return client.VerifyAsset(ctx, target, credlink.VerifyAssetOptions{})
+	}
+
+	backend, key, err := storage.Factory(target)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", target, err)
+	}
+
+	body, meta, err := backend.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", target, err)
+	}
+	defer body.Close()
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", target, err)
+	}
+
+	contentType := meta.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(key))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
 	}
 
-	// TODO: Implement actual single asset verification
-	PrintMsg("Asset verification completed")
-	return nil
+	return client.VerifyAsset(ctx, string(content), credlink.VerifyAssetOptions{ContentType: &contentType})
 }
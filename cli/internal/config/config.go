@@ -0,0 +1,150 @@
+// Package config loads persisted CLI configuration via Viper:
+// ~/.credlink/config.yaml, an optional ./.credlink.yaml overlay, and
+// CREDLINK_* environment variables, organized into named
+// "profile.<name>" sections so per-environment defaults (cloud
+// credentials, output formatting) don't have to be re-typed on every
+// invocation.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+)
+
+// Profile is one named "profile.<name>" section: cloud credentials/
+// endpoints plus default values for flags commands opt into binding.
+// Fields are the zero value when unset, meaning "no override" rather
+// than an error - callers only apply a field when it's non-zero.
+type Profile struct {
+	AWSProfile  string `mapstructure:"aws_profile"`
+	AWSRegion   string `mapstructure:"aws_region"`
+	AWSEndpoint string `mapstructure:"aws_endpoint"`
+
+	R2AccountID       string `mapstructure:"r2_account_id"`
+	R2AccessKeyID     string `mapstructure:"r2_access_key_id"`
+	R2SecretAccessKey string `mapstructure:"r2_secret_access_key"`
+
+	// TrustAnchors is a PEM file of trust anchors for C2PA chain
+	// verification, bound to inspect's --trust-anchors.
+	TrustAnchors string `mapstructure:"trust_anchors"`
+
+	// inspect defaults
+	Format string `mapstructure:"format"`
+	Detail bool   `mapstructure:"detail"`
+	Claims bool   `mapstructure:"claims"`
+	Cert   bool   `mapstructure:"cert"`
+
+	// ls defaults
+	Long      bool   `mapstructure:"long"`
+	Human     bool   `mapstructure:"human"`
+	Sort      string `mapstructure:"sort"`
+	Delimiter string `mapstructure:"delimiter"`
+}
+
+var v = viper.New()
+
+// Load reads ~/.credlink/config.yaml, merges ./.credlink.yaml over it if
+// present, and binds CREDLINK_* environment variables. Safe to call more
+// than once; each call re-reads from scratch.
+func Load() error {
+	v = viper.New()
+	v.SetConfigType("yaml")
+
+	if home, err := os.UserHomeDir(); err == nil {
+		homePath := filepath.Join(home, ".credlink", "config.yaml")
+		if _, statErr := os.Stat(homePath); statErr == nil {
+			v.SetConfigFile(homePath)
+			if err := v.ReadInConfig(); err != nil {
+				return fmt.Errorf("config: read %s: %w", homePath, err)
+			}
+		}
+	}
+
+	if _, statErr := os.Stat(".credlink.yaml"); statErr == nil {
+		v.SetConfigFile(".credlink.yaml")
+		if err := v.MergeInConfig(); err != nil {
+			return fmt.Errorf("config: read .credlink.yaml: %w", err)
+		}
+	}
+
+	v.SetEnvPrefix("CREDLINK")
+	v.AutomaticEnv()
+
+	return nil
+}
+
+// ActiveProfileName resolves which profile section is in effect: override
+// (from --profile) if set, else the persisted "default_profile", else
+// "default".
+func ActiveProfileName(override string) string {
+	if override != "" {
+		return override
+	}
+	if def := v.GetString("default_profile"); def != "" {
+		return def
+	}
+	return "default"
+}
+
+// Active decodes the resolved profile's section into a Profile. A profile
+// with no section of its own decodes to the zero value rather than an
+// error, since every field is an optional override.
+func Active(override string) (Profile, error) {
+	name := ActiveProfileName(override)
+	var p Profile
+	sub := v.Sub("profile." + name)
+	if sub == nil {
+		return p, nil
+	}
+	if err := sub.Unmarshal(&p); err != nil {
+		return p, fmt.Errorf("config: decode profile %q: %w", name, err)
+	}
+	return p, nil
+}
+
+// Get returns the string value of a dotted config key (e.g.
+// "profile.work.aws_region"), "" if unset.
+func Get(key string) string {
+	return v.GetString(key)
+}
+
+// Set assigns a dotted config key and persists the change.
+func Set(key, value string) error {
+	v.Set(key, value)
+	return persist()
+}
+
+// List returns every resolved config key, for `credlink config list`.
+func List() map[string]interface{} {
+	return v.AllSettings()
+}
+
+// UseProfile persists default_profile so future invocations use name
+// without needing --profile.
+func UseProfile(name string) error {
+	v.Set("default_profile", name)
+	return persist()
+}
+
+// persist writes the in-memory config to ~/.credlink/config.yaml,
+// creating the directory and file if they don't exist yet.
+func persist() error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("config: resolve home directory: %w", err)
+	}
+	dir := filepath.Join(home, ".credlink")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("config: create %s: %w", dir, err)
+	}
+
+	path := filepath.Join(dir, "config.yaml")
+	v.SetConfigFile(path)
+	if err := v.WriteConfig(); err != nil {
+		return fmt.Errorf("config: write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+package manifest
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// This file implements just enough of RFC 8949 (CBOR) to decode a C2PA
+// claim map and a COSE_Sign1 envelope, and to re-encode the handful of
+// primitives (text strings, byte strings, array headers) needed to rebuild
+// a COSE Sig_structure for signature verification. It is not a general
+// CBOR library: indefinite-length items are supported since COSE envelopes
+// commonly use them, but tags other than passing the tag number through are
+// not specially interpreted.
+
+// Tag wraps a CBOR value tagged with a tag number (major type 6), e.g. the
+// COSE_Sign1 tag (18) wrapping its 4-element array.
+type Tag struct {
+	Number  uint64
+	Content interface{}
+}
+
+// cborDecode decodes one CBOR data item from the front of data and returns
+// it alongside the unconsumed remainder.
+func cborDecode(data []byte) (interface{}, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, fmt.Errorf("cbor: unexpected end of input")
+	}
+
+	head := data[0]
+	major := head >> 5
+	info := head & 0x1f
+	rest := data[1:]
+
+	switch major {
+	case 0: // unsigned int
+		n, rest, err := cborUint(info, rest)
+		return n, rest, err
+
+	case 1: // negative int
+		n, rest, err := cborUint(info, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		return -1 - int64(n), rest, nil
+
+	case 2: // byte string
+		return cborBytesLike(info, rest, false)
+
+	case 3: // text string
+		b, rest, err := cborBytesLike(info, rest, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		return string(b.([]byte)), rest, nil
+
+	case 4: // array
+		return cborArray(info, rest)
+
+	case 5: // map
+		return cborMap(info, rest)
+
+	case 6: // tag
+		tagNum, rest, err := cborUint(info, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		content, rest, err := cborDecode(rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		return Tag{Number: tagNum, Content: content}, rest, nil
+
+	case 7: // simple/float/bool/null
+		return cborSimple(info, rest)
+
+	default:
+		return nil, nil, fmt.Errorf("cbor: impossible major type %d", major)
+	}
+}
+
+// cborUint decodes the argument of a major-type head: info<24 is the value
+// itself, 24/25/26/27 mean a following 1/2/4/8-byte big-endian value.
+func cborUint(info byte, data []byte) (uint64, []byte, error) {
+	switch {
+	case info < 24:
+		return uint64(info), data, nil
+	case info == 24:
+		if len(data) < 1 {
+			return 0, nil, fmt.Errorf("cbor: truncated uint8 argument")
+		}
+		return uint64(data[0]), data[1:], nil
+	case info == 25:
+		if len(data) < 2 {
+			return 0, nil, fmt.Errorf("cbor: truncated uint16 argument")
+		}
+		return uint64(binary.BigEndian.Uint16(data)), data[2:], nil
+	case info == 26:
+		if len(data) < 4 {
+			return 0, nil, fmt.Errorf("cbor: truncated uint32 argument")
+		}
+		return uint64(binary.BigEndian.Uint32(data)), data[4:], nil
+	case info == 27:
+		if len(data) < 8 {
+			return 0, nil, fmt.Errorf("cbor: truncated uint64 argument")
+		}
+		return binary.BigEndian.Uint64(data), data[8:], nil
+	default:
+		return 0, nil, fmt.Errorf("cbor: unsupported/indefinite length argument (info=%d)", info)
+	}
+}
+
+// cborBytesLike decodes a byte or text string body, including the
+// indefinite-length form (a sequence of definite-length chunks terminated
+// by a break byte 0xFF), which COSE payloads occasionally use.
+func cborBytesLike(info byte, data []byte, text bool) (interface{}, []byte, error) {
+	if info == 31 {
+		var out []byte
+		for {
+			if len(data) == 0 {
+				return nil, nil, fmt.Errorf("cbor: truncated indefinite-length string")
+			}
+			if data[0] == 0xff {
+				return out, data[1:], nil
+			}
+			chunkHead := data[0]
+			chunkMajor := chunkHead >> 5
+			wantMajor := byte(2)
+			if text {
+				wantMajor = 3
+			}
+			if chunkMajor != wantMajor {
+				return nil, nil, fmt.Errorf("cbor: mismatched chunk type in indefinite-length string")
+			}
+			n, rest, err := cborUint(chunkHead&0x1f, data[1:])
+			if err != nil {
+				return nil, nil, err
+			}
+			if uint64(len(rest)) < n {
+				return nil, nil, fmt.Errorf("cbor: truncated string chunk")
+			}
+			out = append(out, rest[:n]...)
+			data = rest[n:]
+		}
+	}
+
+	n, rest, err := cborUint(info, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if uint64(len(rest)) < n {
+		return nil, nil, fmt.Errorf("cbor: truncated string of length %d", n)
+	}
+	return append([]byte(nil), rest[:n]...), rest[n:], nil
+}
+
+func cborArray(info byte, data []byte) (interface{}, []byte, error) {
+	if info == 31 {
+		var out []interface{}
+		for {
+			if len(data) == 0 {
+				return nil, nil, fmt.Errorf("cbor: truncated indefinite-length array")
+			}
+			if data[0] == 0xff {
+				return out, data[1:], nil
+			}
+			item, rest, err := cborDecode(data)
+			if err != nil {
+				return nil, nil, err
+			}
+			out = append(out, item)
+			data = rest
+		}
+	}
+
+	n, rest, err := cborUint(info, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	// A malicious header can declare an arbitrarily large count; bound it by
+	// what data could actually hold (1 byte is the smallest possible
+	// element) before allocating, rather than trusting the attacker-chosen
+	// length outright.
+	if n > uint64(len(rest)) {
+		return nil, nil, fmt.Errorf("cbor: array length %d exceeds remaining input", n)
+	}
+	out := make([]interface{}, 0, n)
+	for i := uint64(0); i < n; i++ {
+		item, next, err := cborDecode(rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = append(out, item)
+		rest = next
+	}
+	return out, rest, nil
+}
+
+func cborMap(info byte, data []byte) (interface{}, []byte, error) {
+	out := make(map[interface{}]interface{})
+
+	if info == 31 {
+		for {
+			if len(data) == 0 {
+				return nil, nil, fmt.Errorf("cbor: truncated indefinite-length map")
+			}
+			if data[0] == 0xff {
+				return out, data[1:], nil
+			}
+			key, rest, err := cborDecode(data)
+			if err != nil {
+				return nil, nil, err
+			}
+			val, rest2, err := cborDecode(rest)
+			if err != nil {
+				return nil, nil, err
+			}
+			out[cborMapKey(key)] = val
+			data = rest2
+		}
+	}
+
+	n, rest, err := cborUint(info, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Each entry is at least a 1-byte key plus a 1-byte value; reject a
+	// declared count the remaining input couldn't possibly hold instead of
+	// looping n times over an attacker-chosen header.
+	if n > uint64(len(rest))/2 {
+		return nil, nil, fmt.Errorf("cbor: map length %d exceeds remaining input", n)
+	}
+	for i := uint64(0); i < n; i++ {
+		key, next, err := cborDecode(rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		val, next2, err := cborDecode(next)
+		if err != nil {
+			return nil, nil, err
+		}
+		out[cborMapKey(key)] = val
+		rest = next2
+	}
+	return out, rest, nil
+}
+
+// cborMapKey normalizes decoded byte-string keys to strings so map[string]
+// lookups work regardless of whether a key was encoded as a CBOR text or
+// byte string - C2PA claim maps only ever use text-string keys in practice.
+// Unsigned integer keys are normalized to int64, so a COSE header label is
+// looked up as int64 whether it's positive (e.g. 1, 33) or negative.
+func cborMapKey(key interface{}) interface{} {
+	if b, ok := key.([]byte); ok {
+		return string(b)
+	}
+	if n, ok := key.(uint64); ok && n <= math.MaxInt64 {
+		return int64(n)
+	}
+	return key
+}
+
+func cborSimple(info byte, data []byte) (interface{}, []byte, error) {
+	switch info {
+	case 20:
+		return false, data, nil
+	case 21:
+		return true, data, nil
+	case 22:
+		return nil, data, nil
+	case 23:
+		return nil, data, nil // undefined
+	case 25:
+		if len(data) < 2 {
+			return nil, nil, fmt.Errorf("cbor: truncated float16")
+		}
+		return cborHalfToFloat(binary.BigEndian.Uint16(data)), data[2:], nil
+	case 26:
+		if len(data) < 4 {
+			return nil, nil, fmt.Errorf("cbor: truncated float32")
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data))), data[4:], nil
+	case 27:
+		if len(data) < 8 {
+			return nil, nil, fmt.Errorf("cbor: truncated float64")
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), data[8:], nil
+	default:
+		return nil, nil, fmt.Errorf("cbor: unsupported simple value (info=%d)", info)
+	}
+}
+
+func cborHalfToFloat(h uint16) float64 {
+	sign := uint32(h>>15) & 1
+	exp := uint32(h>>10) & 0x1f
+	frac := uint32(h) & 0x3ff
+	var f uint32
+	switch exp {
+	case 0:
+		f = sign << 31
+		if frac != 0 {
+			return float64(math.Float32frombits(f)) * math.Ldexp(float64(frac), -24)
+		}
+	case 0x1f:
+		f = sign<<31 | 0xff<<23 | frac<<13
+	default:
+		f = sign<<31 | (exp+112)<<23 | frac<<13
+	}
+	return float64(math.Float32frombits(f))
+}
+
+// cborEncodeHead encodes a major-type/argument pair, the building block
+// encodeArrayHeader/encodeTextString/encodeByteString share.
+func cborEncodeHead(major byte, n uint64) []byte {
+	head := major << 5
+	switch {
+	case n < 24:
+		return []byte{head | byte(n)}
+	case n <= 0xff:
+		return []byte{head | 24, byte(n)}
+	case n <= 0xffff:
+		b := make([]byte, 3)
+		b[0] = head | 25
+		binary.BigEndian.PutUint16(b[1:], uint16(n))
+		return b
+	case n <= 0xffffffff:
+		b := make([]byte, 5)
+		b[0] = head | 26
+		binary.BigEndian.PutUint32(b[1:], uint32(n))
+		return b
+	default:
+		b := make([]byte, 9)
+		b[0] = head | 27
+		binary.BigEndian.PutUint64(b[1:], n)
+		return b
+	}
+}
+
+// encodeArrayHeader, encodeTextString, and encodeByteString are the only
+// encoding primitives needed to rebuild a COSE Sig_structure (an array of a
+// fixed text-string context label plus byte strings already on hand).
+func encodeArrayHeader(n int) []byte { return cborEncodeHead(4, uint64(n)) }
+
+func encodeTextString(s string) []byte {
+	return append(cborEncodeHead(3, uint64(len(s))), []byte(s)...)
+}
+
+func encodeByteString(b []byte) []byte {
+	return append(cborEncodeHead(2, uint64(len(b))), b...)
+}
@@ -0,0 +1,24 @@
+package manifest
+
+import "testing"
+
+// TestCborDecodeRejectsOversizedArrayLength guards against a crafted array
+// header (major type 4) declaring a length far beyond what the input could
+// hold - previously this reached make([]interface{}, 0, n) with the raw
+// attacker-controlled count and crashed the process instead of returning a
+// decode error.
+func TestCborDecodeRejectsOversizedArrayLength(t *testing.T) {
+	data := []byte{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	if _, _, err := cborDecode(data); err == nil {
+		t.Fatal("expected error for oversized array length, got nil")
+	}
+}
+
+// TestCborDecodeRejectsOversizedMapLength is the map-header equivalent of
+// the array case above (major type 5).
+func TestCborDecodeRejectsOversizedMapLength(t *testing.T) {
+	data := []byte{0xbb, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	if _, _, err := cborDecode(data); err == nil {
+		t.Fatal("expected error for oversized map length, got nil")
+	}
+}
@@ -0,0 +1,62 @@
+package manifest
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+)
+
+// CertSummary is the subset of an X.509 certificate inspect --cert prints:
+// enough to eyeball trust without dumping the raw DER/PEM.
+type CertSummary struct {
+	Subject    string   `json:"subject"`
+	Issuer     string   `json:"issuer"`
+	SANs       []string `json:"sans,omitempty"`
+	NotBefore  string   `json:"not_before"`
+	NotAfter   string   `json:"not_after"`
+	KeyUsage   []string `json:"key_usage,omitempty"`
+	Thumbprint string   `json:"thumbprint_sha256"`
+}
+
+// SummarizeChain projects a parsed certificate chain (leaf first) into the
+// display form inspect --cert prints.
+func SummarizeChain(chain []*x509.Certificate) []CertSummary {
+	summaries := make([]CertSummary, 0, len(chain))
+	for _, cert := range chain {
+		sum := sha256.Sum256(cert.Raw)
+		summaries = append(summaries, CertSummary{
+			Subject:    cert.Subject.String(),
+			Issuer:     cert.Issuer.String(),
+			SANs:       cert.DNSNames,
+			NotBefore:  cert.NotBefore.UTC().Format("2006-01-02T15:04:05Z"),
+			NotAfter:   cert.NotAfter.UTC().Format("2006-01-02T15:04:05Z"),
+			KeyUsage:   keyUsageNames(cert.KeyUsage),
+			Thumbprint: hex.EncodeToString(sum[:]),
+		})
+	}
+	return summaries
+}
+
+func keyUsageNames(usage x509.KeyUsage) []string {
+	names := []struct {
+		bit  x509.KeyUsage
+		name string
+	}{
+		{x509.KeyUsageDigitalSignature, "digitalSignature"},
+		{x509.KeyUsageContentCommitment, "contentCommitment"},
+		{x509.KeyUsageKeyEncipherment, "keyEncipherment"},
+		{x509.KeyUsageDataEncipherment, "dataEncipherment"},
+		{x509.KeyUsageKeyAgreement, "keyAgreement"},
+		{x509.KeyUsageCertSign, "certSign"},
+		{x509.KeyUsageCRLSign, "crlSign"},
+		{x509.KeyUsageEncipherOnly, "encipherOnly"},
+		{x509.KeyUsageDecipherOnly, "decipherOnly"},
+	}
+	var out []string
+	for _, n := range names {
+		if usage&n.bit != 0 {
+			out = append(out, n.name)
+		}
+	}
+	return out
+}
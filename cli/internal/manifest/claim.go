@@ -0,0 +1,89 @@
+package manifest
+
+import "fmt"
+
+// Claim is the decoded form of a C2PA claim map: identity fields plus the
+// hashed references ("assertions") it binds to.
+type Claim struct {
+	Label        string
+	InstanceID   string
+	Format       string
+	Generator    string
+	Alg          string
+	Assertions   []ClaimAssertionRef
+	SignatureRef string
+}
+
+// ClaimAssertionRef is one entry of the claim's "assertions" array: a
+// hashed reference to an assertion box elsewhere in the manifest.
+type ClaimAssertionRef struct {
+	URL  string
+	Hash []byte
+	Alg  string
+}
+
+// decodeClaim projects a generically-decoded CBOR map (map[interface{}]interface{})
+// into a Claim, defaulting each assertion's algorithm to the claim's own
+// when not overridden.
+func decodeClaim(value interface{}) (*Claim, error) {
+	m, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("claim: top-level CBOR value is not a map")
+	}
+
+	claim := &Claim{
+		InstanceID:   cborMapString(m, "instanceID"),
+		Format:       cborMapString(m, "dc:format"),
+		Generator:    cborMapString(m, "claim_generator"),
+		Alg:          cborMapString(m, "alg"),
+		SignatureRef: cborMapString(m, "signature"),
+	}
+	if claim.Alg == "" {
+		claim.Alg = "sha256"
+	}
+
+	rawAssertions, _ := m["assertions"].([]interface{})
+	for _, item := range rawAssertions {
+		entry, ok := item.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		ref := ClaimAssertionRef{
+			URL: cborMapString(entry, "url"),
+			Alg: cborMapString(entry, "alg"),
+		}
+		if ref.Alg == "" {
+			ref.Alg = claim.Alg
+		}
+		if hashEntry, ok := entry["hash"]; ok {
+			if h, ok := hashEntry.([]byte); ok {
+				ref.Hash = h
+			}
+		}
+		claim.Assertions = append(claim.Assertions, ref)
+	}
+
+	return claim, nil
+}
+
+func cborMapString(m map[interface{}]interface{}, key string) string {
+	v, ok := m[key]
+	if !ok {
+		return ""
+	}
+	s, _ := v.(string)
+	return s
+}
+
+// assertionLabel extracts the trailing path component of a claim
+// assertion's JUMBF URL (e.g. "self#jumbf=c2pa.assertions/c2pa.hash.data"
+// -> "c2pa.hash.data"), which is what each assertion superbox's own jumd
+// label is set to.
+func assertionLabel(url string) string {
+	for i := len(url) - 1; i >= 0; i-- {
+		if url[i] == '/' {
+			return url[i+1:]
+		}
+	}
+	return url
+}
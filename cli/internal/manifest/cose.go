@@ -0,0 +1,253 @@
+package manifest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"fmt"
+	"hash"
+	"math/big"
+)
+
+// Sign1 is a decoded COSE_Sign1 structure (RFC 9052 §4.2): a protected
+// header, an unprotected header, the signed payload, and the signature
+// itself.
+type Sign1 struct {
+	Protected   []byte
+	Unprotected map[interface{}]interface{}
+	Payload     []byte
+	Signature   []byte
+}
+
+// COSE header labels this package looks at.
+const (
+	coseLabelAlg     = int64(1)
+	coseLabelX5Chain = int64(33)
+)
+
+// COSE algorithm identifiers (RFC 9053 §2.1) this package can verify.
+const (
+	coseAlgES256 = -7
+	coseAlgES384 = -35
+	coseAlgES512 = -36
+	coseAlgRS256 = -257
+)
+
+// DecodeSign1 decodes a COSE_Sign1 item, unwrapping the CBOR tag 18 wrapper
+// if present (c2pa.signature content boxes may or may not include it).
+func DecodeSign1(data []byte) (*Sign1, error) {
+	// Trailing bytes after the Sign1 array are tolerated (box payloads are
+	// sometimes padded) as long as the array itself decodes cleanly.
+	value, _, err := cborDecode(data)
+	if err != nil {
+		return nil, fmt.Errorf("cose: decode: %w", err)
+	}
+
+	if tag, ok := value.(Tag); ok {
+		value = tag.Content
+	}
+
+	arr, ok := value.([]interface{})
+	if !ok || len(arr) != 4 {
+		return nil, fmt.Errorf("cose: expected a 4-element COSE_Sign1 array")
+	}
+
+	protected, ok := arr[0].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("cose: protected header must be a byte string")
+	}
+
+	unprotected, _ := arr[1].(map[interface{}]interface{})
+
+	var payload []byte
+	if p, ok := arr[2].([]byte); ok {
+		payload = p
+	}
+
+	signature, ok := arr[3].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("cose: signature must be a byte string")
+	}
+
+	return &Sign1{Protected: protected, Unprotected: unprotected, Payload: payload, Signature: signature}, nil
+}
+
+// protectedHeader decodes the protected header's CBOR map.
+func (s *Sign1) protectedHeader() (map[interface{}]interface{}, error) {
+	if len(s.Protected) == 0 {
+		return map[interface{}]interface{}{}, nil
+	}
+	value, _, err := cborDecode(s.Protected)
+	if err != nil {
+		return nil, fmt.Errorf("cose: decode protected header: %w", err)
+	}
+	m, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("cose: protected header is not a map")
+	}
+	return m, nil
+}
+
+// headerInt looks a label up in protected first, then unprotected, the
+// order COSE mandates for resolving effective header values.
+func headerInt(protected, unprotected map[interface{}]interface{}, label int64) (int64, bool) {
+	if v, ok := protected[label]; ok {
+		if n, ok := v.(int64); ok {
+			return n, true
+		}
+	}
+	if v, ok := unprotected[label]; ok {
+		if n, ok := v.(int64); ok {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// Chain returns the signer's X.509 certificate chain (leaf first) from the
+// x5chain header (COSE/X.509 draft, label 33): either a single byte string
+// or an array of them.
+func (s *Sign1) Chain() ([]*x509.Certificate, error) {
+	protected, err := s.protectedHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	raw, ok := protected[coseLabelX5Chain]
+	if !ok {
+		raw, ok = s.Unprotected[coseLabelX5Chain]
+	}
+	if !ok {
+		return nil, fmt.Errorf("cose: no x5chain header present")
+	}
+
+	var der [][]byte
+	switch v := raw.(type) {
+	case []byte:
+		der = [][]byte{v}
+	case []interface{}:
+		for _, item := range v {
+			b, ok := item.([]byte)
+			if !ok {
+				return nil, fmt.Errorf("cose: x5chain entry is not a byte string")
+			}
+			der = append(der, b)
+		}
+	default:
+		return nil, fmt.Errorf("cose: unrecognized x5chain shape %T", v)
+	}
+
+	certs := make([]*x509.Certificate, 0, len(der))
+	for i, d := range der {
+		cert, err := x509.ParseCertificate(d)
+		if err != nil {
+			return nil, fmt.Errorf("cose: parse x5chain[%d]: %w", i, err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// VerifyResult reports the outcome of verifying a Sign1's signature and
+// chain against a trust anchor set.
+type VerifyResult struct {
+	Chain         []*x509.Certificate
+	ChainVerified bool
+	ChainError    error
+	SigAlg        string
+	SigVerified   bool
+	SigError      error
+}
+
+// Verify rebuilds the COSE Sig_structure over payload, checks the signature
+// against the chain's leaf certificate, and (when roots is non-nil) checks
+// the chain against it.
+func (s *Sign1) Verify(payload []byte, roots *x509.CertPool) (*VerifyResult, error) {
+	protected, err := s.protectedHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VerifyResult{}
+
+	chain, err := s.Chain()
+	if err != nil {
+		return nil, err
+	}
+	result.Chain = chain
+
+	if roots != nil {
+		intermediates := x509.NewCertPool()
+		for _, cert := range chain[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, verr := chain[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		result.ChainVerified = verr == nil
+		result.ChainError = verr
+	}
+
+	alg, ok := headerInt(protected, s.Unprotected, coseLabelAlg)
+	if !ok {
+		return nil, fmt.Errorf("cose: no alg header present")
+	}
+
+	sigStructure := append(encodeArrayHeader(4),
+		encodeTextString("Signature1")...)
+	sigStructure = append(sigStructure, encodeByteString(s.Protected)...)
+	sigStructure = append(sigStructure, encodeByteString(nil)...) // external_aad: always empty for C2PA
+	sigStructure = append(sigStructure, encodeByteString(payload)...)
+
+	verified, sigAlg, err := verifyCOSESignature(chain[0], alg, sigStructure, s.Signature)
+	result.SigAlg = sigAlg
+	result.SigVerified = verified
+	result.SigError = err
+
+	return result, nil
+}
+
+func verifyCOSESignature(cert *x509.Certificate, alg int64, signed, sig []byte) (bool, string, error) {
+	var h hash.Hash
+	var name string
+	switch alg {
+	case coseAlgES256:
+		h, name = sha256.New(), "ES256"
+	case coseAlgES384:
+		h, name = sha512.New384(), "ES384"
+	case coseAlgES512:
+		h, name = sha512.New(), "ES512"
+	case coseAlgRS256:
+		h, name = sha256.New(), "RS256"
+	default:
+		return false, fmt.Sprintf("COSE alg %d", alg), fmt.Errorf("cose: unsupported signature algorithm %d", alg)
+	}
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch pub := cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		if len(sig) != 2*((pub.Curve.Params().BitSize+7)/8) {
+			return false, name, fmt.Errorf("cose: ECDSA signature has unexpected length %d", len(sig))
+		}
+		n := len(sig) / 2
+		r := new(big.Int).SetBytes(sig[:n])
+		sVal := new(big.Int).SetBytes(sig[n:])
+		if !ecdsa.Verify(pub, digest, r, sVal) {
+			return false, name, fmt.Errorf("cose: ECDSA signature invalid")
+		}
+		return true, name, nil
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest, sig); err != nil {
+			return false, name, fmt.Errorf("cose: RSA signature invalid: %w", err)
+		}
+		return true, name, nil
+	default:
+		return false, name, fmt.Errorf("cose: unsupported signer public key type %T", pub)
+	}
+}
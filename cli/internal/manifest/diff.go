@@ -0,0 +1,162 @@
+package manifest
+
+import "sort"
+
+// EditOp is one operation of a Myers shortest-edit-script: Keep an element
+// common to both sequences, Delete one only A has, or Insert one only B
+// has. Value holds the element itself so a caller can render a hunk
+// without re-indexing back into the original slices.
+type EditOp struct {
+	Op    string // "equal", "delete", "insert"
+	Value string
+}
+
+const (
+	OpEqual  = "equal"
+	OpDelete = "delete"
+	OpInsert = "insert"
+)
+
+// DiffOrdered computes the Myers shortest-edit-script between a and b,
+// the way `diff a b` would treat them as ordered lines: common runs come
+// back as OpEqual, everything else as an OpDelete from a followed by (or
+// interleaved with) an OpInsert from b. Used for C2PA's ordered lists -
+// the claim's actions assertion and its ingredient (provenance) edges -
+// where position, not just membership, is part of what changed.
+func DiffOrdered(a, b []string) []EditOp {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	// trace[d] holds the x-values of the furthest-reaching path ending on
+	// diagonal k for each step d, so the edit script can be replayed
+	// backwards once a path reaches the bottom-right corner.
+	max := n + m
+	v := make(map[int]int, 2*max+1)
+	v[1] = 0
+	var trace []map[int]int
+
+	found := false
+	var finalD int
+	for d := 0; d <= max && !found; d++ {
+		snapshot := make(map[int]int, len(v))
+		for k, x := range v {
+			snapshot[k] = x
+		}
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1] < v[k+1]) {
+				x = v[k+1]
+			} else {
+				x = v[k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k] = x
+			if x >= n && y >= m {
+				finalD = d
+				found = true
+				break
+			}
+		}
+	}
+
+	if !found {
+		// n == m == 0 was already handled above; this path is otherwise
+		// unreachable since d == max always reaches (n, m).
+		return nil
+	}
+
+	return backtrack(a, b, trace, finalD)
+}
+
+// backtrack replays the recorded furthest-reaching paths from (n, m) back
+// to (0, 0), emitting edits in forward order.
+func backtrack(a, b []string, trace []map[int]int, finalD int) []EditOp {
+	x, y := len(a), len(b)
+	var ops []EditOp
+
+	for d := finalD; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+
+		var prevK int
+		if k == -d || (k != d && v[k-1] < v[k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, EditOp{Op: OpEqual, Value: a[x-1]})
+			x--
+			y--
+		}
+
+		if x == prevX {
+			ops = append(ops, EditOp{Op: OpInsert, Value: b[y-1]})
+			y--
+		} else {
+			ops = append(ops, EditOp{Op: OpDelete, Value: a[x-1]})
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		ops = append(ops, EditOp{Op: OpEqual, Value: a[x-1]})
+		x--
+		y--
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// KeyedChange describes one key present in both keyed sets whose value
+// differs between them.
+type KeyedChange struct {
+	Key    string
+	Before string
+	After  string
+}
+
+// DiffKeyed diffs two unordered sets of key/value pairs - C2PA claims,
+// where what matters is which claim labels are present and whether their
+// value changed, not the order they were declared in. Keys in a but not b
+// are "removed", keys in b but not a are "added", and keys in both whose
+// values differ come back as modified.
+func DiffKeyed(a, b map[string]string) (added, removed []string, modified []KeyedChange) {
+	for k, av := range a {
+		bv, ok := b[k]
+		if !ok {
+			removed = append(removed, k)
+			continue
+		}
+		if av != bv {
+			modified = append(modified, KeyedChange{Key: k, Before: av, After: bv})
+		}
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			added = append(added, k)
+		}
+	}
+
+	// Map iteration order is random; sort so repeat runs on identical input
+	// produce byte-identical output, the same as changedInA/changedInB/
+	// conflicting in computeMergeBase.
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Slice(modified, func(i, j int) bool { return modified[i].Key < modified[j].Key })
+
+	return added, removed, modified
+}
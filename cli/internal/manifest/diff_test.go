@@ -0,0 +1,41 @@
+package manifest
+
+import "testing"
+
+// TestDiffKeyedIsSorted guards against DiffKeyed's added/removed/modified
+// slices coming back in map iteration order (which varies between runs on
+// identical input) instead of sorted by key.
+func TestDiffKeyedIsSorted(t *testing.T) {
+	a := map[string]string{"c": "1", "d": "1", "e": "1"}
+	b := map[string]string{"z": "1", "a": "1", "e": "2"}
+
+	for i := 0; i < 20; i++ {
+		added, removed, modified := DiffKeyed(a, b)
+
+		wantAdded := []string{"a", "z"}
+		if !equalStrings(added, wantAdded) {
+			t.Fatalf("added = %v, want %v", added, wantAdded)
+		}
+
+		wantRemoved := []string{"c", "d"}
+		if !equalStrings(removed, wantRemoved) {
+			t.Fatalf("removed = %v, want %v", removed, wantRemoved)
+		}
+
+		if len(modified) != 1 || modified[0].Key != "e" {
+			t.Fatalf("modified = %v, want a single change for key %q", modified, "e")
+		}
+	}
+}
+
+func equalStrings(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
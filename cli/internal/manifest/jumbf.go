@@ -0,0 +1,178 @@
+package manifest
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Box is one ISO/IEC 19566-5 (JUMBF) box. Superboxes (Type == "jumb") carry
+// their children parsed out of Payload; leaf boxes (content boxes such as
+// "cbor", "json", a "jumd" description box, or a raw "bfdb" databox) only
+// carry Payload.
+type Box struct {
+	Type     string
+	Payload  []byte
+	Children []*Box
+	// Raw is the box's full encoded bytes (header + payload), which C2PA's
+	// "hashed URI" assertion references hash over instead of just the box's
+	// content.
+	Raw []byte
+}
+
+// ParseBoxes parses a flat sequence of boxes from data (either a whole
+// JUMBF superbox's payload, or a container's top-level box list), expanding
+// any "jumb" box's children recursively.
+func ParseBoxes(data []byte) ([]*Box, error) {
+	var boxes []*Box
+	for len(data) > 0 {
+		box, consumed, err := parseBox(data)
+		if err != nil {
+			return nil, err
+		}
+		boxes = append(boxes, box)
+		data = data[consumed:]
+	}
+	return boxes, nil
+}
+
+// parseBox reads one box header (LBox/TBox, with the 64-bit XLBox extension
+// when LBox == 1) off the front of data and returns it plus the number of
+// bytes consumed.
+func parseBox(data []byte) (*Box, int, error) {
+	if len(data) < 8 {
+		return nil, 0, fmt.Errorf("jumbf: truncated box header")
+	}
+
+	lbox := binary.BigEndian.Uint32(data[0:4])
+	tbox := string(data[4:8])
+	headerLen := 8
+	var size uint64
+
+	switch lbox {
+	case 0:
+		size = uint64(len(data))
+	case 1:
+		if len(data) < 16 {
+			return nil, 0, fmt.Errorf("jumbf: truncated 64-bit box size")
+		}
+		size = binary.BigEndian.Uint64(data[8:16])
+		headerLen = 16
+	default:
+		size = uint64(lbox)
+	}
+
+	if size < uint64(headerLen) || size > uint64(len(data)) {
+		return nil, 0, fmt.Errorf("jumbf: box %q declares size %d beyond available %d bytes", tbox, size, len(data))
+	}
+
+	payload := data[headerLen:size]
+	box := &Box{Type: tbox, Payload: payload, Raw: data[:size]}
+
+	if tbox == "jumb" {
+		children, err := ParseBoxes(payload)
+		if err != nil {
+			return nil, 0, fmt.Errorf("jumbf: parse jumb children: %w", err)
+		}
+		box.Children = children
+	}
+
+	return box, int(size), nil
+}
+
+// jumdInfo is the parsed form of a "jumd" (JUMBF description) box: a UUID
+// identifying the superbox's content type, plus an optional human-readable
+// label C2PA uses pervasively to address boxes (e.g. "c2pa.claim",
+// "c2pa.assertions", "c2pa.signature", or an individual assertion's label).
+type jumdInfo struct {
+	UUID  [16]byte
+	Label string
+}
+
+// toggle bits within a jumd box, per ISO/IEC 19566-5 Table 3.
+const (
+	jumdToggleRequestable = 1 << 0
+	jumdToggleLabel       = 1 << 1
+	jumdToggleID          = 1 << 2
+	jumdToggleSignature   = 1 << 3
+)
+
+func parseJumd(payload []byte) (*jumdInfo, error) {
+	if len(payload) < 17 {
+		return nil, fmt.Errorf("jumbf: truncated jumd box")
+	}
+	info := &jumdInfo{}
+	copy(info.UUID[:], payload[:16])
+	toggles := payload[16]
+	rest := payload[17:]
+
+	if toggles&jumdToggleLabel != 0 {
+		end := indexByte(rest, 0)
+		if end < 0 {
+			return nil, fmt.Errorf("jumbf: jumd label missing null terminator")
+		}
+		info.Label = string(rest[:end])
+		rest = rest[end+1:]
+	}
+	if toggles&jumdToggleID != 0 {
+		if len(rest) < 4 {
+			return nil, fmt.Errorf("jumbf: truncated jumd id")
+		}
+		rest = rest[4:]
+	}
+	if toggles&jumdToggleSignature != 0 {
+		if len(rest) < 32 {
+			return nil, fmt.Errorf("jumbf: truncated jumd signature")
+		}
+		rest = rest[32:]
+	}
+
+	return info, nil
+}
+
+func indexByte(b []byte, c byte) int {
+	for i, v := range b {
+		if v == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// Label returns the box's own jumd-declared label, or "" if it has none or
+// isn't a superbox.
+func (b *Box) Label() string {
+	if b.Type != "jumb" || len(b.Children) == 0 || b.Children[0].Type != "jumd" {
+		return ""
+	}
+	info, err := parseJumd(b.Children[0].Payload)
+	if err != nil {
+		return ""
+	}
+	return info.Label
+}
+
+// ContentBox returns the first non-"jumd" child, i.e. the actual content
+// box (typically "cbor" or "json") a C2PA superbox wraps alongside its jumd
+// description.
+func (b *Box) ContentBox() *Box {
+	for _, child := range b.Children {
+		if child.Type != "jumd" {
+			return child
+		}
+	}
+	return nil
+}
+
+// FindByLabel walks the box tree depth-first for the first superbox whose
+// jumd label equals label.
+func (b *Box) FindByLabel(label string) *Box {
+	if b.Label() == label {
+		return b
+	}
+	for _, child := range b.Children {
+		if found := child.FindByLabel(label); found != nil {
+			return found
+		}
+	}
+	return nil
+}
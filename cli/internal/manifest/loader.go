@@ -0,0 +1,56 @@
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/credlink/cli/pkg/storage"
+)
+
+// Loader fetches an asset's bytes from wherever it lives - a local path or
+// an s3://, r2://, gs://, oss://, or azblob:// URI - and parses its
+// embedded C2PA manifest. It exists so commands like `diff`, which accept
+// either kind of path, don't need their own copy of storage.Factory's
+// scheme dispatch.
+type Loader struct {
+	Options storage.FactoryOptions
+}
+
+// NewLoader creates a Loader. The zero Loader is also usable and behaves
+// identically, since storage.FactoryOptions's zero value means "no
+// overrides".
+func NewLoader() *Loader {
+	return &Loader{}
+}
+
+// Load resolves path to a Backend via storage.FactoryWithOptions and
+// returns its bytes.
+func (l *Loader) Load(ctx context.Context, path string) ([]byte, error) {
+	backend, key, err := storage.FactoryWithOptions(path, l.Options)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: resolve %s: %w", path, err)
+	}
+
+	body, _, err := backend.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: fetch %s: %w", path, err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// Parse loads path and parses its embedded C2PA manifest, the Loader
+// equivalent of Parse for paths that may be local or cloud-resident.
+func (l *Loader) Parse(ctx context.Context, path string, opts Options) (*Manifest, error) {
+	data, err := l.Load(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBytes(data, opts)
+}
@@ -0,0 +1,180 @@
+// Package manifest extracts and verifies C2PA manifests embedded in
+// assets: locating the JUMBF box in its container (JPEG APP11, PNG caBX,
+// ISOBMFF "jumb"), parsing the box tree, decoding the CBOR claim and its
+// COSE_Sign1 signature, and recomputing each assertion's hash. inspect and
+// verify share it so both report the same parsed structure.
+package manifest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"os"
+)
+
+// Options configures manifest parsing/verification.
+type Options struct {
+	// TrustRoots, when non-nil, is checked against the signer's chain. When
+	// nil, chain verification is skipped entirely (reported as
+	// unverified rather than failed) since C2PA has no universal default
+	// trust store the way TLS does.
+	TrustRoots *x509.CertPool
+}
+
+// AssertionResult is one claim assertion reference alongside the outcome of
+// recomputing its hash against the referenced assertion box.
+type AssertionResult struct {
+	URI            string `json:"uri"`
+	Alg            string `json:"alg"`
+	Hash           string `json:"hash"`
+	RecomputedHash string `json:"recomputed_hash,omitempty"`
+	Match          bool   `json:"match"`
+}
+
+// Manifest is the parsed, verified form of an asset's embedded C2PA
+// manifest.
+type Manifest struct {
+	Label             string            `json:"label"`
+	InstanceID        string            `json:"instance_id"`
+	Format            string            `json:"format"`
+	Generator         string            `json:"generator,omitempty"`
+	Assertions        []AssertionResult `json:"assertions"`
+	Actions           []string          `json:"actions,omitempty"`
+	Ingredients       []Ingredient      `json:"ingredients,omitempty"`
+	Certificates      []CertSummary     `json:"certificates,omitempty"`
+	SignatureAlg      string            `json:"signature_algorithm,omitempty"`
+	SignatureVerified bool              `json:"signature_verified"`
+	ChainVerified     bool              `json:"chain_verified"`
+	VerifyError       string            `json:"verify_error,omitempty"`
+}
+
+// Parse reads assetPath, extracts its embedded JUMBF manifest, and
+// verifies the claim's COSE_Sign1 signature (and, with opts.TrustRoots set,
+// the signer's certificate chain).
+func Parse(assetPath string, opts Options) (*Manifest, error) {
+	data, err := os.ReadFile(assetPath)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: read %s: %w", assetPath, err)
+	}
+	return ParseBytes(data, opts)
+}
+
+// ParseBytes is Parse for an asset already in memory - e.g. fetched from a
+// cloud object store by a Loader rather than read off local disk.
+func ParseBytes(data []byte, opts Options) (*Manifest, error) {
+	jumbfBytes, err := ExtractJUMBF(data)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: extract JUMBF: %w", err)
+	}
+
+	boxes, err := ParseBoxes(jumbfBytes)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: parse JUMBF: %w", err)
+	}
+	if len(boxes) == 0 || boxes[0].Type != "jumb" {
+		return nil, fmt.Errorf("manifest: expected a top-level jumb box")
+	}
+
+	manifestBox := boxes[0].ContentBox()
+	if manifestBox == nil {
+		return nil, fmt.Errorf("manifest: manifest store has no manifest box")
+	}
+
+	claimBox := manifestBox.FindByLabel("c2pa.claim")
+	if claimBox == nil {
+		return nil, fmt.Errorf("manifest: no c2pa.claim box found")
+	}
+	claimContent := claimBox.ContentBox()
+	if claimContent == nil || claimContent.Type != "cbor" {
+		return nil, fmt.Errorf("manifest: c2pa.claim has no cbor content box")
+	}
+	claimValue, _, err := cborDecode(claimContent.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: decode claim cbor: %w", err)
+	}
+	claim, err := decodeClaim(claimValue)
+	if err != nil {
+		return nil, fmt.Errorf("manifest: %w", err)
+	}
+
+	result := &Manifest{
+		Label:      manifestBox.Label(),
+		InstanceID: claim.InstanceID,
+		Format:     claim.Format,
+		Generator:  claim.Generator,
+	}
+
+	assertionStore := manifestBox.FindByLabel("c2pa.assertions")
+	for _, ref := range claim.Assertions {
+		ar := AssertionResult{
+			URI:  ref.URL,
+			Alg:  ref.Alg,
+			Hash: hex.EncodeToString(ref.Hash),
+		}
+		if assertionStore != nil {
+			if box := assertionStore.FindByLabel(assertionLabel(ref.URL)); box != nil {
+				recomputed := hashWithAlg(ref.Alg, box.Raw)
+				ar.RecomputedHash = hex.EncodeToString(recomputed)
+				ar.Match = bytes.Equal(recomputed, ref.Hash)
+			}
+		}
+		result.Assertions = append(result.Assertions, ar)
+	}
+
+	if assertionStore != nil {
+		result.Actions = decodeActionsAssertion(assertionStore)
+		result.Ingredients = decodeIngredientAssertions(assertionStore)
+	}
+
+	sigBox := manifestBox.FindByLabel("c2pa.signature")
+	if sigBox == nil {
+		result.VerifyError = "no c2pa.signature box found"
+		return result, nil
+	}
+	sigContent := sigBox.ContentBox()
+	if sigContent == nil {
+		result.VerifyError = "c2pa.signature has no content box"
+		return result, nil
+	}
+
+	sign1, err := DecodeSign1(sigContent.Payload)
+	if err != nil {
+		result.VerifyError = err.Error()
+		return result, nil
+	}
+
+	verifyResult, err := sign1.Verify(claimContent.Payload, opts.TrustRoots)
+	if err != nil {
+		result.VerifyError = err.Error()
+		return result, nil
+	}
+
+	result.Certificates = SummarizeChain(verifyResult.Chain)
+	result.SignatureAlg = verifyResult.SigAlg
+	result.SignatureVerified = verifyResult.SigVerified
+	result.ChainVerified = verifyResult.ChainVerified
+	if verifyResult.SigError != nil {
+		result.VerifyError = verifyResult.SigError.Error()
+	} else if opts.TrustRoots != nil && verifyResult.ChainError != nil {
+		result.VerifyError = verifyResult.ChainError.Error()
+	}
+
+	return result, nil
+}
+
+func hashWithAlg(alg string, data []byte) []byte {
+	switch alg {
+	case "sha384":
+		sum := sha512.Sum384(data)
+		return sum[:]
+	case "sha512":
+		sum := sha512.Sum512(data)
+		return sum[:]
+	default: // "sha256" and anything unrecognized: the claim's own default
+		sum := sha256.Sum256(data)
+		return sum[:]
+	}
+}
@@ -0,0 +1,102 @@
+package manifest
+
+// Ingredient is a decoded "c2pa.ingredient" assertion: a reference to
+// another asset this manifest's asset was derived from or composed with.
+// Relationship follows the C2PA vocabulary ("parentOf" for a direct
+// derivation, "componentOf" for an asset folded in as one input among
+// several).
+type Ingredient struct {
+	Title        string `json:"title,omitempty"`
+	Relationship string `json:"relationship"`
+	DocumentID   string `json:"document_id,omitempty"`
+	InstanceID   string `json:"instance_id,omitempty"`
+}
+
+// decodeActionsAssertion finds the "c2pa.actions" assertion under store (if
+// any) and returns its actions array as an ordered list of action names
+// (e.g. "c2pa.created", "c2pa.edited"), in the order the assertion declares
+// them - the order a diff's Myers pass treats as significant.
+func decodeActionsAssertion(store *Box) []string {
+	box := store.FindByLabel("c2pa.actions")
+	if box == nil {
+		return nil
+	}
+	content := box.ContentBox()
+	if content == nil || content.Type != "cbor" {
+		return nil
+	}
+	value, _, err := cborDecode(content.Payload)
+	if err != nil {
+		return nil
+	}
+	m, ok := value.(map[interface{}]interface{})
+	if !ok {
+		return nil
+	}
+	rawActions, _ := m["actions"].([]interface{})
+
+	var actions []string
+	for _, item := range rawActions {
+		entry, ok := item.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		if action := cborMapString(entry, "action"); action != "" {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+// decodeIngredientAssertions finds every "c2pa.ingredient" assertion under
+// store - C2PA allows more than one, each disambiguated with a "__N" label
+// suffix - and decodes them into Ingredients. Order follows box order
+// within the assertion store, which is the order the manifest declared
+// them in.
+func decodeIngredientAssertions(store *Box) []Ingredient {
+	var ingredients []Ingredient
+	for _, child := range store.Children {
+		if child.Type == "jumd" {
+			continue
+		}
+		label := child.Label()
+		if label != "c2pa.ingredient" && !isIngredientVariantLabel(label) {
+			continue
+		}
+		content := child.ContentBox()
+		if content == nil || content.Type != "cbor" {
+			continue
+		}
+		value, _, err := cborDecode(content.Payload)
+		if err != nil {
+			continue
+		}
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		ingredients = append(ingredients, Ingredient{
+			Title:        cborMapString(m, "title"),
+			Relationship: cborMapString(m, "relationship"),
+			DocumentID:   cborMapString(m, "documentID"),
+			InstanceID:   cborMapString(m, "instanceID"),
+		})
+	}
+	return ingredients
+}
+
+// isIngredientVariantLabel matches the "c2pa.ingredient__N" labels JUMBF
+// assigns to the second and later ingredient assertions in a manifest,
+// since jumd labels must be unique within their superbox.
+func isIngredientVariantLabel(label string) bool {
+	const prefix = "c2pa.ingredient__"
+	if len(label) <= len(prefix) || label[:len(prefix)] != prefix {
+		return false
+	}
+	for _, r := range label[len(prefix):] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,221 @@
+// Package safepath centralizes the path-traversal and cloud-URI
+// validation every command in cli/internal/cmd used to hand-roll with its
+// own "strings.Contains(path, \"..\")" heuristics and a byte-prefix
+// comparison against the current directory - which both false-positives
+// (cwd "/tmp/foo" vs. sibling "/tmp/foobar") and false-negatives (symlink
+// escapes, ".." segments Clean already normalized away, mixed separators).
+package safepath
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// Resolve cleans userPath, resolves any symlinks along the way, and
+// verifies the result is lexically within root, returning the resolved
+// absolute path. Both root and userPath may not yet exist on disk (e.g. a
+// destination about to be written); only the existing prefix of the path
+// is consulted when resolving symlinks.
+func Resolve(root, userPath string) (string, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", fmt.Errorf("safepath: resolve root %q: %w", root, err)
+	}
+	absRoot = filepath.Clean(absRoot)
+
+	var absPath string
+	if filepath.IsAbs(userPath) {
+		absPath = filepath.Clean(userPath)
+	} else {
+		absPath = filepath.Clean(filepath.Join(absRoot, userPath))
+	}
+
+	resolvedRoot, err := resolveExistingSymlinks(absRoot)
+	if err != nil {
+		return "", fmt.Errorf("safepath: resolve root %q: %w", root, err)
+	}
+	resolved, err := resolveExistingSymlinks(absPath)
+	if err != nil {
+		return "", fmt.Errorf("safepath: resolve %q: %w", userPath, err)
+	}
+
+	if !withinRoot(resolvedRoot, resolved) {
+		return "", fmt.Errorf("safepath: %q escapes %q", userPath, root)
+	}
+
+	return resolved, nil
+}
+
+// ValidateLocalPath is Resolve scoped to the current working directory,
+// the check every command's validate*Input ran for non-cloud paths.
+func ValidateLocalPath(path string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("safepath: determine current directory: %w", err)
+	}
+	_, err = Resolve(cwd, path)
+	return err
+}
+
+// resolveExistingSymlinks walks up from path until it finds an ancestor
+// that actually exists, resolves that ancestor's symlinks, and rejoins the
+// remaining (not-yet-existing) components - so a path for a file that
+// hasn't been created yet can still be checked against root.
+func resolveExistingSymlinks(path string) (string, error) {
+	rest := ""
+	cur := path
+	for {
+		if _, err := os.Lstat(cur); err == nil {
+			resolved, err := filepath.EvalSymlinks(cur)
+			if err != nil {
+				return "", err
+			}
+			if rest == "" {
+				return resolved, nil
+			}
+			return filepath.Join(resolved, rest), nil
+		} else if !os.IsNotExist(err) {
+			return "", err
+		}
+
+		parent := filepath.Dir(cur)
+		if parent == cur {
+			// Reached the filesystem root without finding anything that
+			// exists on disk; there's nothing left to resolve.
+			return path, nil
+		}
+		if rest == "" {
+			rest = filepath.Base(cur)
+		} else {
+			rest = filepath.Join(filepath.Base(cur), rest)
+		}
+		cur = parent
+	}
+}
+
+// withinRoot reports whether path is root or a descendant of it, comparing
+// path components (split on filepath.Separator) instead of a raw string
+// prefix match, which would wrongly treat a sibling directory whose name
+// merely starts with root's own name as contained.
+func withinRoot(root, path string) bool {
+	root = filepath.Clean(root)
+	path = filepath.Clean(path)
+	if root == path {
+		return true
+	}
+	rootParts := strings.Split(root, string(filepath.Separator))
+	pathParts := strings.Split(path, string(filepath.Separator))
+	if len(pathParts) < len(rootParts) {
+		return false
+	}
+	for i, part := range rootParts {
+		if !samePathComponent(part, pathParts[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// samePathComponent compares one path component, case-insensitively on
+// platforms with case-insensitive filesystems by default (Windows, macOS).
+func samePathComponent(a, b string) bool {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// CloudPath is a parsed "scheme://..." cloud storage URI.
+type CloudPath struct {
+	Scheme  string // "s3", "r2", "gs", or "oss"
+	Account string // r2 only: the Cloudflare account the bucket is scoped to
+	Bucket  string
+	Key     string
+}
+
+var ipv4Like = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+\.[0-9]+$`)
+
+// ParseCloudPath validates and splits a cloud storage URI, applying
+// S3-style bucket-naming rules (3-63 characters, lowercase alphanumerics
+// plus '.'/'-', no leading/trailing/adjacent '.', not formatted as an IPv4
+// address) to the bucket component of every scheme, and r2's extra
+// "account/bucket/key" structure (R2 buckets are scoped to a Cloudflare
+// account rather than being globally unique like S3's).
+func ParseCloudPath(uri string) (CloudPath, error) {
+	scheme, rest, ok := strings.Cut(uri, "://")
+	if !ok {
+		return CloudPath{}, fmt.Errorf("safepath: %q has no scheme", uri)
+	}
+
+	var cp CloudPath
+	cp.Scheme = scheme
+
+	switch scheme {
+	case "s3", "gs", "oss":
+		bucket, key, _ := strings.Cut(rest, "/")
+		cp.Bucket, cp.Key = bucket, key
+
+	case "r2":
+		parts := strings.SplitN(rest, "/", 3)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return CloudPath{}, fmt.Errorf("safepath: invalid r2 path %q, expected r2://account/bucket/key", uri)
+		}
+		cp.Account = parts[0]
+		cp.Bucket = parts[1]
+		if len(parts) == 3 {
+			cp.Key = parts[2]
+		}
+
+	default:
+		return CloudPath{}, fmt.Errorf("safepath: unsupported storage scheme %q", scheme)
+	}
+
+	if err := validateBucketName(cp.Bucket); err != nil {
+		return CloudPath{}, err
+	}
+	if err := validateKey(cp.Key); err != nil {
+		return CloudPath{}, err
+	}
+
+	return cp, nil
+}
+
+func validateBucketName(bucket string) error {
+	if len(bucket) < 3 || len(bucket) > 63 {
+		return fmt.Errorf("safepath: bucket name %q must be 3-63 characters", bucket)
+	}
+	if ipv4Like.MatchString(bucket) {
+		return fmt.Errorf("safepath: bucket name %q must not be formatted as an IP address", bucket)
+	}
+	if strings.Contains(bucket, "..") {
+		return fmt.Errorf("safepath: bucket name %q cannot contain adjacent periods", bucket)
+	}
+	for i, r := range bucket {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		case r == '.' || r == '-':
+			if i == 0 || i == len(bucket)-1 {
+				return fmt.Errorf("safepath: bucket name %q cannot start or end with %q", bucket, string(r))
+			}
+		default:
+			return fmt.Errorf("safepath: bucket name %q contains invalid character %q", bucket, string(r))
+		}
+	}
+	return nil
+}
+
+// validateKey rejects ".." path-traversal segments in a cloud object
+// key/prefix. Keys are otherwise unconstrained: S3-compatible stores allow
+// almost any UTF-8 byte sequence.
+func validateKey(key string) error {
+	for _, segment := range strings.Split(key, "/") {
+		if segment == ".." {
+			return fmt.Errorf("safepath: key %q contains a path-traversal segment", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package safepath
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestResolveRejectsSymlinkEscape(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(root, "escape")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Resolve(root, filepath.Join("escape", "secret.txt")); err == nil {
+		t.Fatal("expected symlink escape to be rejected")
+	}
+}
+
+func TestResolveAllowsWithinRoot(t *testing.T) {
+	root := t.TempDir()
+	if _, err := Resolve(root, "sub/file.txt"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestResolveRejectsDotDotEscape(t *testing.T) {
+	root := t.TempDir()
+	if _, err := Resolve(root, "../outside.txt"); err == nil {
+		t.Fatal("expected .. escape to be rejected")
+	}
+}
+
+func TestResolveRejectsSiblingPrefixCollision(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "foo")
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sibling := root + "bar"
+	if err := os.MkdirAll(sibling, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Resolve(root, filepath.Join("..", "foobar", "file.txt")); err == nil {
+		t.Fatal("expected sibling-prefix path to be rejected")
+	}
+}
+
+func FuzzResolve(f *testing.F) {
+	f.Add("file.txt")
+	f.Add("../../../etc/passwd")
+	f.Add("sub/../../escape")
+	f.Add(`sub\..\..\escape`)
+	f.Add("./a/./b/../c")
+	f.Add("ESCAPE")
+
+	root := f.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0o755); err != nil {
+		f.Fatal(err)
+	}
+
+	f.Fuzz(func(t *testing.T, userPath string) {
+		resolved, err := Resolve(root, userPath)
+		if err != nil {
+			return
+		}
+		if !withinRoot(root, resolved) {
+			t.Fatalf("Resolve(%q, %q) = %q, escapes root %q", root, userPath, resolved, root)
+		}
+	})
+}
+
+func FuzzParseCloudPath(f *testing.F) {
+	f.Add("s3://bucket/key")
+	f.Add("r2://account/bucket/key")
+	f.Add("gs://bucket/a/../b")
+	f.Add("oss://bucket..name/key")
+	f.Add("s3://")
+	f.Add("ftp://bucket/key")
+
+	f.Fuzz(func(t *testing.T, uri string) {
+		cp, err := ParseCloudPath(uri)
+		if err != nil {
+			return
+		}
+		for _, segment := range splitKey(cp.Key) {
+			if segment == ".." {
+				t.Fatalf("ParseCloudPath(%q) accepted a traversal segment in key %q", uri, cp.Key)
+			}
+		}
+	})
+}
+
+func splitKey(key string) []string {
+	var parts []string
+	cur := ""
+	for _, r := range key {
+		if r == '/' {
+			parts = append(parts, cur)
+			cur = ""
+			continue
+		}
+		cur += string(r)
+	}
+	parts = append(parts, cur)
+	return parts
+}
+
+func TestSamePathComponentCaseSensitivity(t *testing.T) {
+	wantInsensitive := runtime.GOOS == "windows" || runtime.GOOS == "darwin"
+	if got := samePathComponent("Foo", "foo"); got != wantInsensitive {
+		t.Fatalf("samePathComponent(Foo, foo) = %v, want %v on %s", got, wantInsensitive, runtime.GOOS)
+	}
+}
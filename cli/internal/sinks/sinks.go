@@ -0,0 +1,113 @@
+// Package sinks provides pluggable io.WriteCloser destinations for
+// streamed output: local files, stdout (BuildKit's "dest=-" convention),
+// and the s3://, r2://, gs:// object stores `pack` writes Compliance Pack
+// archives to directly when an operator wants the artifact to land in WORM
+// storage without a local intermediate file.
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/credlink/cli/pkg/storage"
+)
+
+// Stdout is the dest value that streams to os.Stdout instead of a file,
+// mirroring BuildKit's `--output dest=-` convention.
+const Stdout = "-"
+
+// IsCloud reports whether dest names an s3://, r2://, or gs:// object
+// rather than a local path or Stdout.
+func IsCloud(dest string) bool {
+	for _, scheme := range []string{"s3://", "r2://", "gs://"} {
+		if strings.HasPrefix(dest, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// Options customizes how a sink writes its object.
+type Options struct {
+	// Retain, if non-zero, is applied as a WORM retention period (S3
+	// Object Lock COMPLIANCE mode, GCS Locked retention) measured from the
+	// moment Open is called. Ignored by the local and Stdout sinks, and by
+	// cloud backends that have no locking primitive (r2, oss).
+	Retain time.Duration
+	// ContentType is passed through to cloud backends' Put.
+	ContentType string
+}
+
+// nopCloser wraps a writer a sink must not close itself (os.Stdout), so
+// Close still has a uniform "finalize this destination" meaning across
+// every sink kind.
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// cloudSink pipes Write calls into a storage.Backend.Put running on its
+// own goroutine, since Put takes an io.Reader but a sink is written to
+// incrementally by a streaming pack writer.
+type cloudSink struct {
+	pw   *io.PipeWriter
+	done chan error
+}
+
+func newCloudSink(ctx context.Context, dest string, opts Options) (*cloudSink, error) {
+	backend, key, err := storage.Factory(dest)
+	if err != nil {
+		return nil, err
+	}
+	if key == "" {
+		return nil, fmt.Errorf("%s: missing object key", dest)
+	}
+
+	meta := storage.ObjectMeta{ContentType: opts.ContentType}
+	if opts.Retain > 0 {
+		meta.RetainUntil = time.Now().Add(opts.Retain)
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := backend.Put(ctx, key, pr, meta)
+		pr.CloseWithError(err)
+		done <- err
+	}()
+	return &cloudSink{pw: pw, done: done}, nil
+}
+
+func (s *cloudSink) Write(p []byte) (int, error) { return s.pw.Write(p) }
+
+// Close finishes the upload and waits for Put to return, so a caller that
+// checks Close's error learns whether the object actually landed.
+func (s *cloudSink) Close() error {
+	if err := s.pw.Close(); err != nil {
+		return err
+	}
+	return <-s.done
+}
+
+// Open resolves dest to a WriteCloser: Stdout ("-") streams to os.Stdout,
+// s3://, r2://, and gs:// upload through pkg/storage, and anything else is
+// created as a local file.
+func Open(ctx context.Context, dest string, opts Options) (io.WriteCloser, error) {
+	switch {
+	case dest == Stdout:
+		return nopCloser{os.Stdout}, nil
+	case IsCloud(dest):
+		return newCloudSink(ctx, dest, opts)
+	default:
+		f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("create %s: %w", dest, err)
+		}
+		return f, nil
+	}
+}
@@ -30,6 +30,7 @@ predictable exit codes, dry-run cost projection, and Compliance Pack export.`,
 
 	// Add global flags
 	cmd.AddGlobalFlags(rootCmd)
+	rootCmd.PersistentPreRunE = cmd.ApplyConfig
 
 	// Initialize all subcommands
 	cmd.InitSignCommand(rootCmd)
@@ -38,9 +39,12 @@ predictable exit codes, dry-run cost projection, and Compliance Pack export.`,
 	cmd.InitDiffCommand(rootCmd)
 	cmd.InitBatchCommand(rootCmd)
 	cmd.InitPackCommand(rootCmd)
+	cmd.InitUnpackCommand(rootCmd)
 	cmd.InitCacheCommand(rootCmd)
 	cmd.InitLsCommand(rootCmd)
+	cmd.InitMirrorCommand(rootCmd)
 	cmd.InitDoctorCommand(rootCmd)
+	cmd.InitConfigCommand(rootCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		// Determine appropriate exit code based on error type
@@ -55,6 +59,16 @@ predictable exit codes, dry-run cost projection, and Compliance Pack export.`,
 			os.Exit(cmd.ExitRateLimit)
 		case cmd.ErrServer:
 			os.Exit(cmd.ExitSrvErr)
+		case cmd.ErrThresholdNotMet:
+			os.Exit(cmd.ExitVerifyFail)
+		case cmd.ErrDiffFound:
+			os.Exit(cmd.ExitVerifyFail)
+		case cmd.ErrRetryTimeout:
+			os.Exit(cmd.ExitPartialFail)
+		case cmd.ErrPartialFailure:
+			os.Exit(cmd.ExitPartialFail)
+		case cmd.ErrAborted:
+			os.Exit(cmd.ExitAborted)
 		default:
 			os.Exit(cmd.ExitSrvErr)
 		}
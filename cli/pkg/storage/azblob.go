@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+)
+
+// azblobBackend implements Backend over Azure Blob Storage, scoped to a
+// single container the way s3Backend is scoped to a single bucket.
+type azblobBackend struct {
+	client    *azblob.Client
+	container string
+}
+
+// newAzblobBackend authenticates from AZURE_STORAGE_ACCOUNT plus either
+// AZURE_STORAGE_KEY (shared key) or AZURE_STORAGE_CONNECTION_STRING,
+// following the same "read the usual env vars" convention as the other
+// cloud backends.
+func newAzblobBackend(container string) (*azblobBackend, error) {
+	if connStr := os.Getenv("AZURE_STORAGE_CONNECTION_STRING"); connStr != "" {
+		client, err := azblob.NewClientFromConnectionString(connStr, nil)
+		if err != nil {
+			return nil, fmt.Errorf("create azblob client: %w", err)
+		}
+		return &azblobBackend{client: client, container: container}, nil
+	}
+
+	account := os.Getenv("AZURE_STORAGE_ACCOUNT")
+	key := os.Getenv("AZURE_STORAGE_KEY")
+	if account == "" || key == "" {
+		return nil, fmt.Errorf("AZURE_STORAGE_ACCOUNT and AZURE_STORAGE_KEY (or AZURE_STORAGE_CONNECTION_STRING) are required for azblob:// paths")
+	}
+
+	cred, err := azblob.NewSharedKeyCredential(account, key)
+	if err != nil {
+		return nil, fmt.Errorf("create azblob credential: %w", err)
+	}
+	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", account)
+	client, err := azblob.NewClientWithSharedKeyCredential(serviceURL, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create azblob client: %w", err)
+	}
+	return &azblobBackend{client: client, container: container}, nil
+}
+
+func (b *azblobBackend) Name() string   { return "azblob" }
+func (b *azblobBackend) Bucket() string { return b.container }
+
+func (b *azblobBackend) List(ctx context.Context, prefix string) (<-chan ObjectMeta, <-chan error) {
+	objs := make(chan ObjectMeta)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(objs)
+		defer close(errs)
+
+		pager := b.client.NewListBlobsFlatPager(b.container, &azblob.ListBlobsFlatOptions{Prefix: &prefix})
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				errs <- fmt.Errorf("list azblob objects: %w", err)
+				return
+			}
+			for _, item := range page.Segment.BlobItems {
+				meta := ObjectMeta{Key: *item.Name}
+				if item.Properties != nil {
+					if item.Properties.ContentLength != nil {
+						meta.Size = *item.Properties.ContentLength
+					}
+					if item.Properties.ETag != nil {
+						meta.ETag = string(*item.Properties.ETag)
+					}
+					if item.Properties.LastModified != nil {
+						meta.LastModified = *item.Properties.LastModified
+					}
+					if item.Properties.ContentType != nil {
+						meta.ContentType = *item.Properties.ContentType
+					}
+				}
+				select {
+				case objs <- meta:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+		}
+	}()
+
+	return objs, errs
+}
+
+func (b *azblobBackend) Get(ctx context.Context, key string) (io.ReadCloser, ObjectMeta, error) {
+	meta, err := b.Head(ctx, key)
+	if err != nil {
+		return nil, ObjectMeta{}, err
+	}
+	resp, err := b.client.DownloadStream(ctx, b.container, key, nil)
+	if err != nil {
+		return nil, ObjectMeta{}, fmt.Errorf("get azblob://%s/%s: %w", b.container, key, err)
+	}
+	return resp.Body, meta, nil
+}
+
+func (b *azblobBackend) Put(ctx context.Context, key string, body io.Reader, meta ObjectMeta) error {
+	var opts *azblob.UploadStreamOptions
+	if meta.ContentType != "" {
+		opts = &azblob.UploadStreamOptions{
+			HTTPHeaders: &blob.HTTPHeaders{BlobContentType: to.Ptr(meta.ContentType)},
+		}
+	}
+	if _, err := b.client.UploadStream(ctx, b.container, key, body, opts); err != nil {
+		return fmt.Errorf("put azblob://%s/%s: %w", b.container, key, err)
+	}
+	return nil
+}
+
+func (b *azblobBackend) Head(ctx context.Context, key string) (ObjectMeta, error) {
+	props, err := b.client.ServiceClient().NewContainerClient(b.container).NewBlobClient(key).GetProperties(ctx, nil)
+	if err != nil {
+		return ObjectMeta{}, fmt.Errorf("head azblob://%s/%s: %w", b.container, key, err)
+	}
+	meta := ObjectMeta{Key: key}
+	if props.ContentLength != nil {
+		meta.Size = *props.ContentLength
+	}
+	if props.ETag != nil {
+		meta.ETag = string(*props.ETag)
+	}
+	if props.LastModified != nil {
+		meta.LastModified = *props.LastModified
+	}
+	if props.ContentType != nil {
+		meta.ContentType = *props.ContentType
+	}
+	return meta, nil
+}
+
+func (b *azblobBackend) Delete(ctx context.Context, key string) error {
+	_, err := b.client.DeleteBlob(ctx, b.container, key, nil)
+	if err != nil && !bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return fmt.Errorf("delete azblob://%s/%s: %w", b.container, key, err)
+	}
+	return nil
+}
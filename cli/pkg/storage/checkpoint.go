@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// CheckpointKey identifies one already-processed object, so a resumed `sign`
+// run can skip it. ETag is included because a key can be re-uploaded with
+// different content between runs.
+type CheckpointKey struct {
+	Backend string `json:"backend"`
+	Bucket  string `json:"bucket"`
+	Key     string `json:"key"`
+	ETag    string `json:"etag"`
+}
+
+func (k CheckpointKey) String() string {
+	return fmt.Sprintf("%s|%s|%s|%s", k.Backend, k.Bucket, k.Key, k.ETag)
+}
+
+// Checkpoint is a small on-disk set of CheckpointKeys already signed, used
+// by `sign --resume` to skip objects a previous, interrupted run already
+// finished. Persisted as JSON via a temp-file-then-rename, the same pattern
+// FolderSignPlanner uses for its plan file.
+type Checkpoint struct {
+	mu   sync.Mutex
+	path string
+	done map[string]CheckpointKey
+}
+
+// LoadCheckpoint reads path if it exists, or starts empty otherwise.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	c := &Checkpoint{path: path, done: make(map[string]CheckpointKey)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint %s: %w", path, err)
+	}
+
+	var keys []CheckpointKey
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("parse checkpoint %s: %w", path, err)
+	}
+	for _, k := range keys {
+		c.done[k.String()] = k
+	}
+	return c, nil
+}
+
+// Done reports whether key was recorded as already processed.
+func (c *Checkpoint) Done(key CheckpointKey) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.done[key.String()]
+	return ok
+}
+
+// Mark records key as processed and persists the checkpoint to disk.
+func (c *Checkpoint) Mark(key CheckpointKey) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.done[key.String()] = key
+	return c.save()
+}
+
+func (c *Checkpoint) save() error {
+	keys := make([]CheckpointKey, 0, len(c.done))
+	for _, k := range c.done {
+		keys = append(keys, k)
+	}
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint: %w", err)
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write checkpoint %s: %w", tmp, err)
+	}
+	return os.Rename(tmp, c.path)
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// gcsBackend implements Backend over Google Cloud Storage.
+type gcsBackend struct {
+	client *storage.Client
+	bucket *storage.BucketHandle
+	name   string
+}
+
+func newGCSBackend(bucket string) (*gcsBackend, error) {
+	client, err := storage.NewClient(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("create GCS client: %w", err)
+	}
+	return &gcsBackend{client: client, bucket: client.Bucket(bucket), name: bucket}, nil
+}
+
+func (b *gcsBackend) Name() string   { return "gcs" }
+func (b *gcsBackend) Bucket() string { return b.name }
+
+func (b *gcsBackend) List(ctx context.Context, prefix string) (<-chan ObjectMeta, <-chan error) {
+	objs := make(chan ObjectMeta)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(objs)
+		defer close(errs)
+
+		it := b.bucket.Objects(ctx, &storage.Query{Prefix: prefix})
+		for {
+			attrs, err := it.Next()
+			if err == iterator.Done {
+				return
+			}
+			if err != nil {
+				errs <- fmt.Errorf("list gcs objects: %w", err)
+				return
+			}
+			select {
+			case objs <- ObjectMeta{
+				Key:          attrs.Name,
+				Size:         attrs.Size,
+				ETag:         attrs.Etag,
+				LastModified: attrs.Updated,
+				ContentType:  attrs.ContentType,
+			}:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return objs, errs
+}
+
+func (b *gcsBackend) Get(ctx context.Context, key string) (io.ReadCloser, ObjectMeta, error) {
+	obj := b.bucket.Object(key)
+	attrs, err := obj.Attrs(ctx)
+	if err != nil {
+		return nil, ObjectMeta{}, fmt.Errorf("stat gs://%s/%s: %w", b.name, key, err)
+	}
+	r, err := obj.NewReader(ctx)
+	if err != nil {
+		return nil, ObjectMeta{}, fmt.Errorf("get gs://%s/%s: %w", b.name, key, err)
+	}
+	return r, ObjectMeta{
+		Key:          key,
+		Size:         attrs.Size,
+		ETag:         attrs.Etag,
+		LastModified: attrs.Updated,
+		ContentType:  attrs.ContentType,
+	}, nil
+}
+
+func (b *gcsBackend) Put(ctx context.Context, key string, body io.Reader, meta ObjectMeta) error {
+	w := b.bucket.Object(key).NewWriter(ctx)
+	if meta.ContentType != "" {
+		w.ContentType = meta.ContentType
+	}
+	if !meta.RetainUntil.IsZero() {
+		// Locked mode is GCS's Object Lock equivalent: once applied, not
+		// even a project owner can delete or shorten RetainUntil early.
+		w.Retention = &storage.ObjectRetention{
+			Mode:            "Locked",
+			RetainUntilDate: meta.RetainUntil,
+		}
+	}
+	if _, err := io.Copy(w, body); err != nil {
+		w.Close()
+		return fmt.Errorf("put gs://%s/%s: %w", b.name, key, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("put gs://%s/%s: %w", b.name, key, err)
+	}
+	return nil
+}
+
+func (b *gcsBackend) Head(ctx context.Context, key string) (ObjectMeta, error) {
+	attrs, err := b.bucket.Object(key).Attrs(ctx)
+	if err != nil {
+		return ObjectMeta{}, fmt.Errorf("head gs://%s/%s: %w", b.name, key, err)
+	}
+	return ObjectMeta{
+		Key:          key,
+		Size:         attrs.Size,
+		ETag:         attrs.Etag,
+		LastModified: attrs.Updated,
+		ContentType:  attrs.ContentType,
+	}, nil
+}
+
+func (b *gcsBackend) Delete(ctx context.Context, key string) error {
+	err := b.bucket.Object(key).Delete(ctx)
+	if err != nil && err != storage.ErrObjectNotExist {
+		return fmt.Errorf("delete gs://%s/%s: %w", b.name, key, err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package storage
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+)
+
+// localBackend implements Backend over the local filesystem, rooted at an
+// arbitrary directory so the same Backend interface covers plain `sign
+// ./folder` invocations alongside cloud prefixes.
+type localBackend struct {
+	root string
+}
+
+func newLocalBackend(root string) *localBackend {
+	return &localBackend{root: root}
+}
+
+func (b *localBackend) Name() string   { return "local" }
+func (b *localBackend) Bucket() string { return "" }
+
+func (b *localBackend) abs(key string) string {
+	if key == "" {
+		return b.root
+	}
+	return filepath.Join(b.root, key)
+}
+
+func (b *localBackend) List(ctx context.Context, prefix string) (<-chan ObjectMeta, <-chan error) {
+	objs := make(chan ObjectMeta)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(objs)
+		defer close(errs)
+
+		root := b.abs(prefix)
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if d.IsDir() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(b.root, path)
+			if err != nil {
+				return err
+			}
+			select {
+			case objs <- ObjectMeta{
+				Key:          filepath.ToSlash(rel),
+				Size:         info.Size(),
+				ETag:         localETag(info),
+				LastModified: info.ModTime(),
+				ContentType:  mime.TypeByExtension(filepath.Ext(path)),
+			}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			return nil
+		})
+		if err != nil && err != ctx.Err() {
+			errs <- err
+		}
+	}()
+
+	return objs, errs
+}
+
+func (b *localBackend) Get(ctx context.Context, key string) (io.ReadCloser, ObjectMeta, error) {
+	path := b.abs(key)
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, ObjectMeta{}, fmt.Errorf("open %s: %w", path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, ObjectMeta{}, fmt.Errorf("stat %s: %w", path, err)
+	}
+	return f, ObjectMeta{
+		Key:          key,
+		Size:         info.Size(),
+		ETag:         localETag(info),
+		LastModified: info.ModTime(),
+		ContentType:  mime.TypeByExtension(filepath.Ext(path)),
+	}, nil
+}
+
+func (b *localBackend) Put(ctx context.Context, key string, body io.Reader, meta ObjectMeta) error {
+	path := b.abs(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("mkdir %s: %w", filepath.Dir(path), err)
+	}
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", tmp, err)
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("write %s: %w", tmp, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (b *localBackend) Head(ctx context.Context, key string) (ObjectMeta, error) {
+	path := b.abs(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return ObjectMeta{}, err
+	}
+	return ObjectMeta{
+		Key:          key,
+		Size:         info.Size(),
+		ETag:         localETag(info),
+		LastModified: info.ModTime(),
+		ContentType:  mime.TypeByExtension(filepath.Ext(path)),
+	}, nil
+}
+
+func (b *localBackend) Delete(ctx context.Context, key string) error {
+	err := os.Remove(b.abs(key))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// localETag fakes an S3-style etag from size+mtime so local runs can use the
+// same (backend, bucket, key, etag) checkpoint identity as cloud ones,
+// without hashing every file's full contents on every listing.
+func localETag(info os.FileInfo) string {
+	sum := md5.Sum([]byte(fmt.Sprintf("%d-%d", info.Size(), info.ModTime().UnixNano())))
+	return hex.EncodeToString(sum[:])
+}
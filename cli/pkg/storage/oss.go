@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// ossBackend implements Backend over Aliyun Object Storage Service.
+type ossBackend struct {
+	bucket *oss.Bucket
+	name   string
+}
+
+// newOSSBackend authenticates from ALIYUN_OSS_ENDPOINT / ALIYUN_ACCESS_KEY_ID
+// / ALIYUN_ACCESS_KEY_SECRET, following the same "read the usual env vars"
+// convention as the S3 and R2 backends.
+func newOSSBackend(bucket string) (*ossBackend, error) {
+	endpoint := os.Getenv("ALIYUN_OSS_ENDPOINT")
+	if endpoint == "" {
+		return nil, fmt.Errorf("ALIYUN_OSS_ENDPOINT environment variable is required for oss:// paths")
+	}
+	client, err := oss.New(endpoint, os.Getenv("ALIYUN_ACCESS_KEY_ID"), os.Getenv("ALIYUN_ACCESS_KEY_SECRET"))
+	if err != nil {
+		return nil, fmt.Errorf("create OSS client: %w", err)
+	}
+	b, err := client.Bucket(bucket)
+	if err != nil {
+		return nil, fmt.Errorf("open OSS bucket %s: %w", bucket, err)
+	}
+	return &ossBackend{bucket: b, name: bucket}, nil
+}
+
+func (b *ossBackend) Name() string   { return "oss" }
+func (b *ossBackend) Bucket() string { return b.name }
+
+func (b *ossBackend) List(ctx context.Context, prefix string) (<-chan ObjectMeta, <-chan error) {
+	objs := make(chan ObjectMeta)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(objs)
+		defer close(errs)
+
+		marker := ""
+		for {
+			if ctx.Err() != nil {
+				errs <- ctx.Err()
+				return
+			}
+			result, err := b.bucket.ListObjects(oss.Prefix(prefix), oss.Marker(marker))
+			if err != nil {
+				errs <- fmt.Errorf("list oss objects: %w", err)
+				return
+			}
+			for _, obj := range result.Objects {
+				select {
+				case objs <- ObjectMeta{
+					Key:          obj.Key,
+					Size:         obj.Size,
+					ETag:         obj.ETag,
+					LastModified: obj.LastModified,
+					ContentType:  obj.Type,
+				}:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+			if !result.IsTruncated {
+				return
+			}
+			marker = result.NextMarker
+		}
+	}()
+
+	return objs, errs
+}
+
+func (b *ossBackend) Get(ctx context.Context, key string) (io.ReadCloser, ObjectMeta, error) {
+	meta, err := b.Head(ctx, key)
+	if err != nil {
+		return nil, ObjectMeta{}, err
+	}
+	body, err := b.bucket.GetObject(key)
+	if err != nil {
+		return nil, ObjectMeta{}, fmt.Errorf("get oss://%s/%s: %w", b.name, key, err)
+	}
+	return body, meta, nil
+}
+
+func (b *ossBackend) Put(ctx context.Context, key string, body io.Reader, meta ObjectMeta) error {
+	var opts []oss.Option
+	if meta.ContentType != "" {
+		opts = append(opts, oss.ContentType(meta.ContentType))
+	}
+	if err := b.bucket.PutObject(key, body, opts...); err != nil {
+		return fmt.Errorf("put oss://%s/%s: %w", b.name, key, err)
+	}
+	return nil
+}
+
+func (b *ossBackend) Head(ctx context.Context, key string) (ObjectMeta, error) {
+	header, err := b.bucket.GetObjectMeta(key)
+	if err != nil {
+		return ObjectMeta{}, fmt.Errorf("head oss://%s/%s: %w", b.name, key, err)
+	}
+	return objectMetaFromHeader(key, header), nil
+}
+
+func (b *ossBackend) Delete(ctx context.Context, key string) error {
+	if err := b.bucket.DeleteObject(key); err != nil {
+		return fmt.Errorf("delete oss://%s/%s: %w", b.name, key, err)
+	}
+	return nil
+}
+
+// objectMetaFromHeader builds an ObjectMeta from GetObjectMeta's raw
+// http.Header response, since the OSS SDK doesn't parse it into a struct.
+func objectMetaFromHeader(key string, header http.Header) ObjectMeta {
+	meta := ObjectMeta{Key: key, ContentType: header.Get("Content-Type")}
+	if size, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil {
+		meta.Size = size
+	}
+	meta.ETag = header.Get("ETag")
+	if t, err := http.ParseTime(header.Get("Last-Modified")); err == nil {
+		meta.LastModified = t
+	} else {
+		meta.LastModified = time.Time{}
+	}
+	return meta
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// newR2Backend builds an s3Backend pointed at Cloudflare R2's S3-compatible
+// endpoint for the given account, which is scoped into the URL per R2's
+// convention rather than being part of the bucket path. Credentials come
+// from R2_ACCESS_KEY_ID / R2_SECRET_ACCESS_KEY, falling back to the
+// standard AWS env vars (and, if profile is non-empty, a named profile from
+// the shared config/credentials files) if unset.
+func newR2Backend(account, bucket, profile string) (*s3Backend, error) {
+	endpoint := fmt.Sprintf("https://%s.r2.cloudflarestorage.com", account)
+
+	accessKey := os.Getenv("R2_ACCESS_KEY_ID")
+	secretKey := os.Getenv("R2_SECRET_ACCESS_KEY")
+
+	loadOpts := []func(*config.LoadOptions) error{config.WithRegion("auto")}
+	if accessKey != "" && secretKey != "" {
+		loadOpts = append(loadOpts, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")))
+	} else if profile != "" {
+		loadOpts = append(loadOpts, config.WithSharedConfigProfile(profile))
+	}
+	cfg, err := config.LoadDefaultConfig(context.Background(), loadOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("load R2 config: %w", err)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.BaseEndpoint = aws.String(endpoint)
+		o.UsePathStyle = true
+	})
+
+	return &s3Backend{client: client, bucket: bucket, kind: "r2"}, nil
+}
@@ -0,0 +1,201 @@
+// Package storage provides a pluggable Backend over local disk and cloud
+// object stores (S3, R2, GCS, Aliyun OSS, Azure Blob Storage), so commands
+// like `sign` can walk and read/write `s3://`, `r2://`, `gs://`, `oss://`,
+// and `azblob://` paths the same way they walk a local directory.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ObjectMeta describes an object's identity for change detection and
+// checkpointing: Key+ETag+Size is what a resume checkpoint keys off of.
+type ObjectMeta struct {
+	Key          string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+	ContentType  string
+
+	// RetainUntil, if non-zero, asks Put to apply WORM-style retention to
+	// the object through whatever locking primitive the backend offers
+	// (S3 Object Lock in COMPLIANCE mode, GCS object retention). Backends
+	// without a locking primitive (local, r2, oss) ignore it, and so does
+	// azblob.
+	RetainUntil time.Time
+}
+
+// Backend is a minimal object-store abstraction. Implementations must be
+// safe for concurrent use, since the sign worker pool calls Get/Put/Head
+// from multiple goroutines at once.
+type Backend interface {
+	// List streams every object under prefix on objs, closing both channels
+	// when the listing completes or ctx is canceled. At most one error is
+	// ever sent on errs.
+	List(ctx context.Context, prefix string) (objs <-chan ObjectMeta, errs <-chan error)
+	// Get returns the object body and its metadata. Callers must close the
+	// returned ReadCloser.
+	Get(ctx context.Context, key string) (io.ReadCloser, ObjectMeta, error)
+	// Put uploads body as key, using meta.ContentType if set.
+	Put(ctx context.Context, key string, body io.Reader, meta ObjectMeta) error
+	// Head fetches an object's metadata without downloading its body.
+	Head(ctx context.Context, key string) (ObjectMeta, error)
+	// Delete removes an object. Implementations treat a missing key as success.
+	Delete(ctx context.Context, key string) error
+	// Name identifies the backend kind ("local", "s3", "r2", "gcs", "oss",
+	// "azblob"), used as part of a checkpoint's (backend, bucket, key,
+	// etag) identity.
+	Name() string
+	// Bucket returns the bucket/container this backend is scoped to (empty
+	// for the local backend).
+	Bucket() string
+}
+
+// Page is one page of a delimited (CommonPrefixes-aware) listing returned
+// by DelimitedLister.
+type Page struct {
+	// Objects are the keys found directly under the listed prefix - those
+	// that don't contain the delimiter again past it.
+	Objects []ObjectMeta
+	// CommonPrefixes are the pseudo-directories the delimiter folded deeper
+	// keys into, e.g. "photos/2024/" when listing "photos/" with "/".
+	CommonPrefixes []string
+	// ContinuationToken resumes the listing immediately after this page,
+	// so a long-running walk can checkpoint it and pick back up (as
+	// startToken) across a process restart instead of starting over.
+	ContinuationToken string
+}
+
+// DelimitedLister is implemented by backends that support S3-style
+// delimited listing (Prefix+Delimiter, surfacing CommonPrefixes as
+// pseudo-directories) instead of Backend.List's flat recursive walk. `ls`
+// uses it to show one directory level at a time.
+type DelimitedLister interface {
+	// ListDelimited pages through prefix, calling pageFn once per page
+	// until pageFn returns an error, ctx is canceled, or the listing is
+	// exhausted (ContinuationToken followed until IsTruncated is false).
+	// maxKeys bounds each page's size; 0 uses the backend's default.
+	// startToken, if non-empty, resumes a previously checkpointed listing
+	// from Page.ContinuationToken instead of starting from the beginning.
+	ListDelimited(ctx context.Context, prefix, delimiter string, maxKeys int32, startToken string, pageFn func(Page) error) error
+}
+
+// FactoryOptions customizes backend construction beyond the URI itself.
+type FactoryOptions struct {
+	// AWSProfile selects a named profile from the shared AWS credentials/
+	// config files for s3:// and r2:// backends. Ignored for gs://, oss://,
+	// and local paths.
+	AWSProfile string
+}
+
+// Factory parses uri's scheme and returns a Backend scoped to its
+// bucket/account plus the remaining key or prefix, equivalent to
+// FactoryWithOptions(uri, FactoryOptions{}).
+//
+// Supported forms:
+//
+//	s3://bucket/key-or-prefix
+//	r2://account/bucket/key-or-prefix
+//	gs://bucket/key-or-prefix
+//	oss://bucket/key-or-prefix
+//	azblob://container/key-or-prefix
+//	/local/path or ./relative/path (anything without a "scheme://")
+func Factory(uri string) (Backend, string, error) {
+	return FactoryWithOptions(uri, FactoryOptions{})
+}
+
+// FactoryWithOptions is Factory with additional construction options, e.g.
+// the AWS shared-config profile `ls --aws-profile` selects.
+func FactoryWithOptions(uri string, opts FactoryOptions) (Backend, string, error) {
+	scheme, rest, hasScheme := strings.Cut(uri, "://")
+	if !hasScheme {
+		return newLocalBackend(uri), "", nil
+	}
+
+	switch scheme {
+	case "s3":
+		bucket, key, err := splitBucketKey(rest)
+		if err != nil {
+			return nil, "", fmt.Errorf("parse s3 uri: %w", err)
+		}
+		backend, err := newS3Backend(bucket, opts.AWSProfile)
+		if err != nil {
+			return nil, "", err
+		}
+		return backend, key, nil
+
+	case "r2":
+		account, bucket, key, err := splitAccountBucketKey(rest)
+		if err != nil {
+			return nil, "", fmt.Errorf("parse r2 uri: %w", err)
+		}
+		backend, err := newR2Backend(account, bucket, opts.AWSProfile)
+		if err != nil {
+			return nil, "", err
+		}
+		return backend, key, nil
+
+	case "gs":
+		bucket, key, err := splitBucketKey(rest)
+		if err != nil {
+			return nil, "", fmt.Errorf("parse gs uri: %w", err)
+		}
+		backend, err := newGCSBackend(bucket)
+		if err != nil {
+			return nil, "", err
+		}
+		return backend, key, nil
+
+	case "oss":
+		bucket, key, err := splitBucketKey(rest)
+		if err != nil {
+			return nil, "", fmt.Errorf("parse oss uri: %w", err)
+		}
+		backend, err := newOSSBackend(bucket)
+		if err != nil {
+			return nil, "", err
+		}
+		return backend, key, nil
+
+	case "azblob":
+		container, key, err := splitBucketKey(rest)
+		if err != nil {
+			return nil, "", fmt.Errorf("parse azblob uri: %w", err)
+		}
+		backend, err := newAzblobBackend(container)
+		if err != nil {
+			return nil, "", err
+		}
+		return backend, key, nil
+
+	default:
+		return nil, "", fmt.Errorf("unsupported storage scheme %q", scheme)
+	}
+}
+
+// splitBucketKey splits "bucket/key-or-prefix" into its parts; key is ""
+// when rest is just "bucket" (a whole-bucket listing).
+func splitBucketKey(rest string) (bucket, key string, err error) {
+	bucket, key, _ = strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("bucket name cannot be empty")
+	}
+	return bucket, key, nil
+}
+
+// splitAccountBucketKey splits "account/bucket/key-or-prefix", the shape
+// r2:// URIs use since R2 buckets are scoped to a Cloudflare account.
+func splitAccountBucketKey(rest string) (account, bucket, key string, err error) {
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", fmt.Errorf("expected account/bucket/prefix")
+	}
+	if len(parts) == 3 {
+		return parts[0], parts[1], parts[2], nil
+	}
+	return parts[0], parts[1], "", nil
+}
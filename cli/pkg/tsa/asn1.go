@@ -0,0 +1,121 @@
+package tsa
+
+import (
+	"encoding/asn1"
+	"time"
+)
+
+// ============================================================================
+// RFC 3161 wire types (ASN.1 DER)
+// ============================================================================
+
+// algorithmIdentifier is the standard AlgorithmIdentifier SEQUENCE used
+// throughout PKIX and CMS.
+type algorithmIdentifier struct {
+	Algorithm  asn1.ObjectIdentifier
+	Parameters asn1.RawValue `asn1:"optional"`
+}
+
+// messageImprint ::= SEQUENCE { hashAlgorithm AlgorithmIdentifier, hashedMessage OCTET STRING }
+type messageImprint struct {
+	HashAlgorithm algorithmIdentifier
+	HashedMessage []byte
+}
+
+// timeStampReq ::= SEQUENCE {
+//
+//	version         INTEGER,
+//	messageImprint  MessageImprint,
+//	reqPolicy       TSAPolicyId OPTIONAL,
+//	nonce           INTEGER OPTIONAL,
+//	certReq         BOOLEAN DEFAULT FALSE,
+//	extensions      [0] IMPLICIT Extensions OPTIONAL }
+type timeStampReq struct {
+	Version        int
+	MessageImprint messageImprint
+	ReqPolicy      asn1.ObjectIdentifier `asn1:"optional"`
+	Nonce          int64                 `asn1:"optional"`
+	CertReq        bool                  `asn1:"optional,default:false"`
+}
+
+// pkiFreeText ::= SEQUENCE SIZE (1..MAX) OF UTF8String
+type pkiFreeText []string
+
+// pkiStatusInfo ::= SEQUENCE {
+//
+//	status        INTEGER,
+//	statusString  PKIFreeText OPTIONAL,
+//	failInfo      BIT STRING OPTIONAL }
+type pkiStatusInfo struct {
+	Status       int
+	StatusString pkiFreeText  `asn1:"optional"`
+	FailInfo     asn1.BitString `asn1:"optional"`
+}
+
+// timeStampResp ::= SEQUENCE { status PKIStatusInfo, timeStampToken ContentInfo OPTIONAL }
+type timeStampResp struct {
+	Status         pkiStatusInfo
+	TimeStampToken asn1.RawValue `asn1:"optional"`
+}
+
+// contentInfo ::= SEQUENCE { contentType OBJECT IDENTIFIER, content [0] EXPLICIT ANY DEFINED BY contentType }
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+// signedData is CMS SignedData, trimmed to the fields a TSA response needs.
+type signedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	EncapContentInfo encapsulatedContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+type encapsulatedContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type signerInfo struct {
+	Version                   int
+	SignerIdentifier          asn1.RawValue
+	DigestAlgorithm           algorithmIdentifier
+	SignedAttrs               asn1.RawValue `asn1:"optional,tag:0"`
+	DigestEncryptionAlgorithm algorithmIdentifier
+	EncryptedDigest           []byte
+	UnsignedAttrs             asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// accuracy ::= SEQUENCE { seconds INTEGER OPTIONAL, millis [0] INTEGER OPTIONAL, micros [1] INTEGER OPTIONAL }
+type accuracy struct {
+	Seconds int `asn1:"optional"`
+	Millis  int `asn1:"optional,tag:0"`
+	Micros  int `asn1:"optional,tag:1"`
+}
+
+// tstInfo ::= SEQUENCE {
+//
+//	version        INTEGER,
+//	policy         TSAPolicyId,
+//	messageImprint MessageImprint,
+//	serialNumber   INTEGER,
+//	genTime        GeneralizedTime,
+//	accuracy       Accuracy OPTIONAL,
+//	ordering       BOOLEAN DEFAULT FALSE,
+//	nonce          INTEGER OPTIONAL,
+//	tsa            [0] EXPLICIT ANY OPTIONAL,
+//	extensions     [1] IMPLICIT Extensions OPTIONAL }
+type tstInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint messageImprint
+	SerialNumber   asn1.RawValue
+	GenTime        time.Time
+	Accuracy       accuracy      `asn1:"optional"`
+	Ordering       bool          `asn1:"optional,default:false"`
+	Nonce          int64         `asn1:"optional"`
+	TSA            asn1.RawValue `asn1:"optional,explicit,tag:0"`
+}
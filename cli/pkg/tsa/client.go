@@ -0,0 +1,210 @@
+// Package tsa implements an RFC 3161 Time-Stamp Protocol client over HTTP,
+// so `sign --tsa` can embed a trusted timestamp in each signature bundle
+// instead of relying on the signer's local clock.
+package tsa
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+)
+
+const (
+	contentTypeTSQuery = "application/timestamp-query"
+	contentTypeTSReply = "application/timestamp-reply"
+
+	statusGranted          = 0
+	statusGrantedWithMods  = 1
+)
+
+// oidSHA256 etc. map crypto.Hash values to their AlgorithmIdentifier OIDs,
+// since RFC 3161's MessageImprint names the hash algorithm explicitly.
+var hashOIDs = map[crypto.Hash]asn1.ObjectIdentifier{
+	crypto.SHA256: {2, 16, 840, 1, 101, 3, 4, 2, 1},
+	crypto.SHA384: {2, 16, 840, 1, 101, 3, 4, 2, 2},
+	crypto.SHA512: {2, 16, 840, 1, 101, 3, 4, 2, 3},
+}
+
+// Client stamps content digests against an RFC 3161 Time-Stamp Authority.
+type Client struct {
+	// URL is the TSA's HTTP endpoint.
+	URL string
+	// HTTPClient is used for the POST request. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+	// Policy, if set, is sent as the request's reqPolicy and must match the
+	// TSTInfo.Policy the TSA returns.
+	Policy asn1.ObjectIdentifier
+	// HashAlgo names the hash algorithm digest was computed with. Defaults
+	// to crypto.SHA256.
+	HashAlgo crypto.Hash
+	// Roots verifies the TSA's signing certificate chain. A nil pool falls
+	// back to the system root pool.
+	Roots *x509.CertPool
+	// IsRetryable classifies transport/HTTP errors as retryable, mirroring
+	// the SDK transport's own retry predicate (429/5xx/network errors).
+	IsRetryable func(error) bool
+	// MaxAttempts bounds retries. Defaults to 3.
+	MaxAttempts int
+}
+
+// Token is a verified RFC 3161 timestamp token.
+type Token struct {
+	// Raw is the DER-encoded TimeStampToken (CMS SignedData), suitable for
+	// embedding verbatim in a signature bundle.
+	Raw []byte
+	// Time is TSTInfo.genTime: when the TSA asserts the digest existed.
+	Time time.Time
+	// SerialNumber is the TSA's serial number for this token.
+	SerialNumber *big.Int
+	// Policy is the TSA policy OID the token was issued under.
+	Policy asn1.ObjectIdentifier
+}
+
+// Stamp requests a timestamp over digest (already hashed with c.HashAlgo),
+// retrying transient failures, and verifies the response before returning.
+func (c *Client) Stamp(ctx context.Context, digest []byte) (*Token, error) {
+	hashAlgo := c.HashAlgo
+	if hashAlgo == 0 {
+		hashAlgo = crypto.SHA256
+	}
+	oid, ok := hashOIDs[hashAlgo]
+	if !ok {
+		return nil, fmt.Errorf("tsa: unsupported hash algorithm %v", hashAlgo)
+	}
+
+	nonce, err := randomNonce()
+	if err != nil {
+		return nil, fmt.Errorf("tsa: generate nonce: %w", err)
+	}
+
+	reqDER, err := asn1.Marshal(timeStampReq{
+		Version: 1,
+		MessageImprint: messageImprint{
+			HashAlgorithm: algorithmIdentifier{Algorithm: oid},
+			HashedMessage: digest,
+		},
+		ReqPolicy: c.Policy,
+		Nonce:     nonce,
+		CertReq:   true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tsa: encode TimeStampReq: %w", err)
+	}
+
+	maxAttempts := c.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff(attempt)):
+			}
+		}
+
+		respDER, err := c.post(ctx, reqDER)
+		if err != nil {
+			lastErr = err
+			if c.isRetryable(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		token, err := c.parseAndVerify(respDER, digest, nonce)
+		if err != nil {
+			return nil, err
+		}
+		return token, nil
+	}
+
+	return nil, fmt.Errorf("tsa: request failed after %d attempts: %w", maxAttempts, lastErr)
+}
+
+func (c *Client) post(ctx context.Context, reqDER []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(reqDER))
+	if err != nil {
+		return nil, fmt.Errorf("tsa: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentTypeTSQuery)
+	req.Header.Set("Accept", contentTypeTSReply)
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("tsa: request to %s: %w", c.URL, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("tsa: read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPError{URL: c.URL, StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	return body, nil
+}
+
+// HTTPError is returned by Client.Stamp when the TSA responds with a
+// non-200 status, so callers can classify it the same way the SDK
+// transport classifies 429/5xx responses as retryable.
+type HTTPError struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("tsa: %s returned HTTP %d: %s", e.URL, e.StatusCode, e.Body)
+}
+
+func (c *Client) isRetryable(err error) bool {
+	if c.IsRetryable != nil {
+		return c.IsRetryable(err)
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= 500
+	}
+	// A non-HTTPError here means the request itself failed (dial/timeout/etc).
+	return true
+}
+
+func randomNonce() (int64, error) {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return 0, err
+	}
+	// Clear the sign bit so the value round-trips as a positive ASN.1 INTEGER.
+	buf[0] &= 0x7f
+	return int64(binary.BigEndian.Uint64(buf[:])), nil
+}
+
+func backoff(attempt int) time.Duration {
+	d := time.Duration(attempt) * 250 * time.Millisecond
+	if d > 2*time.Second {
+		return 2 * time.Second
+	}
+	return d
+}
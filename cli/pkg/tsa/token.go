@@ -0,0 +1,266 @@
+package tsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+)
+
+// oidContentTypeSignedData / oidContentTypeTSTInfo identify CMS content
+// types; a TimeStampToken is a SignedData ContentInfo whose inner content
+// is a TSTInfo.
+var (
+	oidContentTypeSignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidContentTypeTSTInfo    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+)
+
+// parseAndVerify decodes respDER as a TimeStampResp, checks its status,
+// unwraps the embedded CMS SignedData, verifies the signer's certificate
+// chain and signature, and asserts the returned TSTInfo actually covers the
+// digest and nonce this client submitted.
+func (c *Client) parseAndVerify(respDER, digest []byte, nonce int64) (*Token, error) {
+	var resp timeStampResp
+	if _, err := asn1.Unmarshal(respDER, &resp); err != nil {
+		return nil, fmt.Errorf("tsa: decode TimeStampResp: %w", err)
+	}
+
+	if resp.Status.Status != statusGranted && resp.Status.Status != statusGrantedWithMods {
+		return nil, fmt.Errorf("tsa: request rejected, status=%d: %s", resp.Status.Status, joinFreeText(resp.Status.StatusString))
+	}
+
+	if len(resp.TimeStampToken.FullBytes) == 0 {
+		return nil, fmt.Errorf("tsa: response granted but no timeStampToken present")
+	}
+
+	var ci contentInfo
+	if _, err := asn1.Unmarshal(resp.TimeStampToken.FullBytes, &ci); err != nil {
+		return nil, fmt.Errorf("tsa: decode TimeStampToken ContentInfo: %w", err)
+	}
+	if !ci.ContentType.Equal(oidContentTypeSignedData) {
+		return nil, fmt.Errorf("tsa: timeStampToken is not CMS SignedData (got %s)", ci.ContentType)
+	}
+
+	var sd signedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return nil, fmt.Errorf("tsa: decode SignedData: %w", err)
+	}
+	if !sd.EncapContentInfo.ContentType.Equal(oidContentTypeTSTInfo) {
+		return nil, fmt.Errorf("tsa: SignedData does not encapsulate TSTInfo (got %s)", sd.EncapContentInfo.ContentType)
+	}
+
+	// eContent is an OCTET STRING wrapping the DER-encoded TSTInfo.
+	var tstInfoDER []byte
+	if _, err := asn1.Unmarshal(sd.EncapContentInfo.Content.Bytes, &tstInfoDER); err != nil {
+		return nil, fmt.Errorf("tsa: decode TSTInfo eContent: %w", err)
+	}
+	var info tstInfo
+	if _, err := asn1.Unmarshal(tstInfoDER, &info); err != nil {
+		return nil, fmt.Errorf("tsa: decode TSTInfo: %w", err)
+	}
+
+	if !bytes.Equal(info.MessageImprint.HashedMessage, digest) {
+		return nil, fmt.Errorf("tsa: TSTInfo.messageImprint does not match the submitted digest")
+	}
+	if info.Nonce != nonce {
+		return nil, fmt.Errorf("tsa: TSTInfo.nonce does not match the request nonce")
+	}
+	if len(c.Policy) > 0 && !info.Policy.Equal(c.Policy) {
+		return nil, fmt.Errorf("tsa: TSTInfo.policy %s does not match requested policy %s", info.Policy, c.Policy)
+	}
+
+	if err := c.verifySigner(sd, tstInfoDER); err != nil {
+		return nil, fmt.Errorf("tsa: %w", err)
+	}
+
+	serial := new(big.Int)
+	if len(info.SerialNumber.Bytes) > 0 {
+		serial.SetBytes(info.SerialNumber.Bytes)
+	}
+
+	return &Token{
+		Raw:          resp.TimeStampToken.FullBytes,
+		Time:         info.GenTime,
+		SerialNumber: serial,
+		Policy:       info.Policy,
+	}, nil
+}
+
+// verifySigner checks that at least one SignerInfo's signature over its
+// signed attributes (whose message-digest attribute must match tstInfoDER's
+// hash) verifies against a certificate in sd.Certificates whose chain
+// validates against c.Roots.
+func (c *Client) verifySigner(sd signedData, tstInfoDER []byte) error {
+	if len(sd.Certificates.Bytes) == 0 {
+		return fmt.Errorf("SignedData carries no certificates to verify against")
+	}
+	certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil {
+		return fmt.Errorf("parse embedded certificates: %w", err)
+	}
+	if len(sd.SignerInfos) == 0 {
+		return fmt.Errorf("SignedData has no SignerInfos")
+	}
+
+	pool := c.Roots
+	if pool == nil {
+		pool, err = x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs {
+		intermediates.AddCert(cert)
+	}
+
+	var lastErr error
+	for _, si := range sd.SignerInfos {
+		signer := findSignerCert(certs, si)
+		if signer == nil {
+			lastErr = fmt.Errorf("no embedded certificate matches a SignerInfo")
+			continue
+		}
+
+		if _, err := signer.Verify(x509.VerifyOptions{
+			Roots:         pool,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping, x509.ExtKeyUsageAny},
+		}); err != nil {
+			lastErr = fmt.Errorf("signer certificate chain: %w", err)
+			continue
+		}
+
+		signedBytes, hashAlgo, err := signedAttrDigestInput(si, tstInfoDER)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if err := verifySignature(signer, hashAlgo, signedBytes, si.EncryptedDigest); err != nil {
+			lastErr = fmt.Errorf("signature verification failed: %w", err)
+			continue
+		}
+
+		return nil
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no SignerInfo could be verified")
+	}
+	return lastErr
+}
+
+// findSignerCert matches a SignerInfo's SignerIdentifier (an
+// IssuerAndSerialNumber) against the embedded certificate set.
+func findSignerCert(certs []*x509.Certificate, si signerInfo) *x509.Certificate {
+	var ias struct {
+		Issuer asn1.RawValue
+		Serial *big.Int
+	}
+	if _, err := asn1.Unmarshal(si.SignerIdentifier.FullBytes, &ias); err != nil {
+		if len(certs) == 1 {
+			return certs[0]
+		}
+		return nil
+	}
+	for _, cert := range certs {
+		if cert.SerialNumber != nil && ias.Serial != nil && cert.SerialNumber.Cmp(ias.Serial) == 0 {
+			return cert
+		}
+	}
+	if len(certs) == 1 {
+		return certs[0]
+	}
+	return nil
+}
+
+// signedAttrDigestInput returns the bytes the signature was computed over
+// (the DER-reencoded SignedAttrs SET, per CMS) and the hash algorithm to use,
+// after asserting the SignedAttrs' message-digest attribute covers tstInfoDER.
+func signedAttrDigestInput(si signerInfo, tstInfoDER []byte) ([]byte, crypto.Hash, error) {
+	hashAlgo, err := hashAlgoFromOID(si.DigestAlgorithm.Algorithm)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(si.SignedAttrs.Bytes) == 0 {
+		// No signed attributes: the signature covers the content directly.
+		return tstInfoDER, hashAlgo, nil
+	}
+
+	h := hashAlgo.New()
+	h.Write(tstInfoDER)
+	digest := h.Sum(nil)
+
+	// RFC 5652 §5.4: the signature covers the SignedAttrs re-encoded as a
+	// SET OF (tag 0x31), not the [0] IMPLICIT SEQUENCE the wire form uses.
+	reencoded := append([]byte{0x31}, si.SignedAttrs.FullBytes[1:]...)
+
+	var attrs []struct {
+		Type   asn1.ObjectIdentifier
+		Values asn1.RawValue `asn1:"set"`
+	}
+	if _, err := asn1.UnmarshalWithParams(reencoded, &attrs, "set"); err != nil {
+		return nil, 0, fmt.Errorf("decode SignedAttrs: %w", err)
+	}
+
+	oidMessageDigest := asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	found := false
+	for _, attr := range attrs {
+		if !attr.Type.Equal(oidMessageDigest) {
+			continue
+		}
+		var want []byte
+		if _, err := asn1.Unmarshal(attr.Values.Bytes, &want); err == nil && bytes.Equal(want, digest) {
+			found = true
+		}
+	}
+	if !found {
+		return nil, 0, fmt.Errorf("SignedAttrs message-digest does not match TSTInfo")
+	}
+
+	return reencoded, hashAlgo, nil
+}
+
+func hashAlgoFromOID(oid asn1.ObjectIdentifier) (crypto.Hash, error) {
+	for hash, candidate := range hashOIDs {
+		if candidate.Equal(oid) {
+			return hash, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported digest algorithm OID %s", oid)
+}
+
+func verifySignature(cert *x509.Certificate, hashAlgo crypto.Hash, signedBytes, sig []byte) error {
+	h := hashAlgo.New()
+	h.Write(signedBytes)
+	digest := h.Sum(nil)
+
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(pub, hashAlgo, digest, sig)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, digest, sig) {
+			return fmt.Errorf("ECDSA signature invalid")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported signer public key type %T", pub)
+	}
+}
+
+func joinFreeText(text pkiFreeText) string {
+	if len(text) == 0 {
+		return "no status message"
+	}
+	out := text[0]
+	for _, s := range text[1:] {
+		out += "; " + s
+	}
+	return out
+}
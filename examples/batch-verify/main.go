@@ -169,7 +169,7 @@ type BatchResults struct {
 func batchVerifyAssets(ctx context.Context, client *c2c.Client, urls []string, policyID string) (*BatchResults, error) {
 	fmt.Printf("Batch verifying %d assets...\n", len(urls))
 
-	resultCh, err := client.BatchVerify(ctx, urls, c2c.BatchVerifyOptions{
+	stream, err := client.BatchVerify(ctx, urls, c2c.BatchVerifyOptions{
 		PolicyID:         &policyID,
 		Parallel:         c2c.Ptr(true),
 		TimeoutPerAsset:  c2c.Ptr(5000),
@@ -177,13 +177,18 @@ func batchVerifyAssets(ctx context.Context, client *c2c.Client, urls []string, p
 	if err != nil {
 		return nil, fmt.Errorf("failed to start batch verification: %w", err)
 	}
+	defer stream.Close()
 
 	results := &BatchResults{
 		Total:  len(urls),
 		Errors: []BatchResult{},
 	}
 
-	for result := range resultCh {
+	for {
+		result, err := stream.Next(ctx)
+		if err != nil {
+			break
+		}
 		verified := false
 		if result.Result != nil {
 			verified = result.Result.Verified
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/credlink/sdk-go/v2/c2c"
+	"github.com/credlink/sdk/go/credlink/events"
 )
 
 func main() {
@@ -21,6 +22,7 @@ func main() {
 		selector  = flag.String("selector", "img[src], video[src], audio[src]", "CSS selector")
 		pattern   = flag.String("pattern", "*.html", "File pattern for directory processing")
 		noBackup  = flag.Bool("no-backup", false, "Skip creating backup files")
+		outMode   = flag.String("event-output", "human", "Progress output format: human, ndjson, or json")
 	)
 	flag.Parse()
 
@@ -30,6 +32,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	mode, err := events.ParseOutputMode(*outMode)
+	if err != nil {
+		log.Fatal(err)
+	}
+	emitter := events.NewEmitter(mode)
+
 	apiKey := os.Getenv("C2_API_KEY")
 	if apiKey == "" {
 		log.Fatal("C2_API_KEY environment variable is required")
@@ -43,10 +51,10 @@ func main() {
 
 	if info.IsDir() {
 		// Process directory
-		err = injectLinksInDirectory(*input, *output, *manifest, *strategy, *selector, *pattern, !*noBackup)
+		err = injectLinksInDirectory(*input, *output, *manifest, *strategy, *selector, *pattern, !*noBackup, emitter)
 	} else {
 		// Process single file
-		err = injectLinksInFile(*input, *output, *manifest, *strategy, *selector)
+		err = injectLinksInFile(*input, *output, *manifest, *strategy, *selector, emitter)
 	}
 
 	if err != nil {
@@ -54,7 +62,7 @@ func main() {
 	}
 }
 
-func injectLinksInFile(inputPath, outputPath, manifestURL, strategy, selector string) error {
+func injectLinksInFile(inputPath, outputPath, manifestURL, strategy, selector string, emitter *events.Emitter) error {
 	fmt.Printf("Processing %s...\n", inputPath)
 
 	client := c2c.NewClientWithAPIKey(os.Getenv("C2_API_KEY"))
@@ -86,17 +94,14 @@ func injectLinksInFile(inputPath, outputPath, manifestURL, strategy, selector st
 	fmt.Printf("  Output saved to %s\n", outputPath)
 
 	// Show processed assets
-	if len(result.Data.AssetsProcessed) > 0 {
-		fmt.Println("  Processed assets:")
-		for _, asset := range result.Data.AssetsProcessed {
-			fmt.Printf("    - %s\n", asset)
-		}
+	for _, asset := range result.Data.AssetsProcessed {
+		emitter.Emit(events.LinkInjected(asset, ""))
 	}
 
 	return nil
 }
 
-func injectLinksInDirectory(inputDir, outputDir, manifestURL, strategy, selector, pattern string, backup bool) error {
+func injectLinksInDirectory(inputDir, outputDir, manifestURL, strategy, selector, pattern string, backup bool, emitter *events.Emitter) error {
 	// Create output directory
 	err := os.MkdirAll(outputDir, 0755)
 	if err != nil {
@@ -148,8 +153,9 @@ func injectLinksInDirectory(inputDir, outputDir, manifestURL, strategy, selector
 		}
 
 		// Inject links
-		err = injectLinksInFile(inputFile, outputFile, manifestURL, strategy, selector)
+		err = injectLinksInFile(inputFile, outputFile, manifestURL, strategy, selector, emitter)
 		if err != nil {
+			emitter.Emit(events.Error("INJECT_LINK_FAILED", err.Error()))
 			log.Printf("Error processing %s: %v", inputFile, err)
 		}
 	}
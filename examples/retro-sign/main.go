@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -9,34 +10,44 @@ import (
 	"strings"
 	"time"
 
-	"github.com/credlink/sdk-go/v2/c2c"
+	"github.com/credlink/sdk/go/credlink"
+	"github.com/credlink/sdk/go/credlink/events"
 )
 
 func main() {
 	var (
-		folder        = flag.String("folder", "", "Path to folder to sign")
-		profile       = flag.String("profile", "", "Signing profile ID")
-		tsa           = flag.Bool("tsa", false, "Include RFC-3161 timestamps")
-		noRecursive   = flag.Bool("no-recursive", false, "Do not process subdirectories")
-		patterns      = flag.String("patterns", "*.jpg,*.png,*.mp4,*.pdf", "Comma-separated file patterns")
-		idempotencyKey = flag.String("idempotency-key", "", "Idempotency key for request deduplication")
-		pollInterval  = flag.Int("poll-interval", 10, "Job status poll interval in seconds")
-		monitorOnly   = flag.String("monitor-only", "", "Monitor existing job (provide job ID)")
+		folder       = flag.String("folder", "", "Path to folder to sign")
+		profile      = flag.String("profile", "", "Signing profile ID")
+		tsa          = flag.Bool("tsa", false, "Include RFC-3161 timestamps")
+		noRecursive  = flag.Bool("no-recursive", false, "Do not process subdirectories")
+		patterns     = flag.String("patterns", "*.jpg,*.png,*.mp4,*.pdf", "Comma-separated file patterns")
+		pollInterval = flag.Int("poll-interval", 10, "Job status poll interval in seconds")
+		monitorOnly  = flag.String("monitor-only", "", "Monitor existing job (provide job ID)")
+		output       = flag.String("output", "human", "Progress output format: human, ndjson, or json")
 	)
 	flag.Parse()
 
+	mode, err := events.ParseOutputMode(*output)
+	if err != nil {
+		log.Fatal(err)
+	}
+	emitter := events.NewEmitter(mode)
+
 	apiKey := os.Getenv("C2_API_KEY")
 	if apiKey == "" {
 		log.Fatal("C2_API_KEY environment variable is required")
 	}
 
-	client := c2c.NewClientWithAPIKey(apiKey)
+	client := credlink.NewClientWithAPIKey(apiKey)
 	defer client.Close()
 
 	if *monitorOnly != "" {
 		// Monitor existing job
-		err := monitorJob(context.Background(), client, *monitorOnly, *pollInterval)
+		err := monitorJob(context.Background(), client, *monitorOnly, *pollInterval, emitter)
 		if err != nil {
+			if errors.Is(err, credlink.ErrJobCanceled) {
+				os.Exit(130)
+			}
 			log.Fatalf("Job monitoring failed: %v", err)
 		}
 		return
@@ -60,89 +71,116 @@ func main() {
 	// Parse file patterns
 	filePatterns := strings.Split(*patterns, ",")
 
-	// Start signing job
-	jobID, err := signFolder(context.Background(), client, *folder, *profile, *tsa, !*noRecursive, filePatterns, *idempotencyKey)
+	// Plan, dedupe against already-signed content, and submit the
+	// remaining files as one or more idempotent batches.
+	jobIDs, err := signFolder(context.Background(), client, *folder, *profile, *tsa, !*noRecursive, filePatterns)
 	if err != nil {
-		log.Fatalf("Failed to start signing job: %v", err)
+		log.Fatalf("Failed to start signing job(s): %v", err)
 	}
 
-	// Monitor the job
-	err = monitorJob(context.Background(), client, jobID, *pollInterval)
-	if err != nil {
-		log.Fatalf("Job monitoring failed: %v", err)
+	// Monitor each batch in turn; a SIGINT/SIGTERM cancels the in-flight
+	// batch server-side rather than abandoning it to run unattended. A
+	// rerun against the same folder/profile picks up from the plan file,
+	// so Ctrl-C here doesn't lose the batches that already completed.
+	for i, jobID := range jobIDs {
+		if len(jobIDs) > 1 {
+			fmt.Printf("\n--- Batch %d/%d ---\n", i+1, len(jobIDs))
+		}
+		err = monitorJob(context.Background(), client, jobID, *pollInterval, emitter)
+		if err != nil {
+			if errors.Is(err, credlink.ErrJobCanceled) {
+				os.Exit(130)
+			}
+			log.Fatalf("Job monitoring failed: %v", err)
+		}
 	}
 }
 
-func signFolder(ctx context.Context, client *c2c.Client, folderPath, profileID string, tsa, recursive bool, filePatterns []string, idempotencyKey string) (string, error) {
-	fmt.Printf("Signing folder: %s\n", folderPath)
+// signFolder plans the folder (hashing every matching file and checking
+// which ones already have a valid manifest under profileID), then submits
+// only the remaining files, grouped into idempotent sub-jobs by
+// FolderSignPlanner. It returns the job ID of each submitted sub-job.
+func signFolder(ctx context.Context, client *credlink.Client, folderPath, profileID string, tsa, recursive bool, filePatterns []string) ([]string, error) {
+	fmt.Printf("Planning folder: %s\n", folderPath)
 	fmt.Printf("Profile: %s\n", profileID)
 	fmt.Printf("TSA: %t\n", tsa)
 	fmt.Printf("Recursive: %t\n", recursive)
 	fmt.Printf("File patterns: %v\n", filePatterns)
 
-	result, err := client.SignFolder(ctx, folderPath, c2c.SignFolderOptions{
-		ProfileID:      profileID,
-		TSA:            &tsa,
-		Recursive:      &recursive,
-		FilePatterns:   filePatterns,
-		IdempotencyKey: &idempotencyKey,
+	planner := credlink.NewFolderSignPlanner(client, credlink.FolderSignPlannerOptions{
+		FolderPath:   folderPath,
+		ProfileID:    profileID,
+		Recursive:    recursive,
+		FilePatterns: filePatterns,
 	})
+
+	entries, err := planner.Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sign plan: %w", err)
+	}
+	fmt.Printf("📋 Plan built: %d files matched\n", len(entries))
+
+	entries, err = planner.CheckExisting(ctx, entries)
 	if err != nil {
-		return "", fmt.Errorf("failed to start signing job: %w", err)
+		return nil, fmt.Errorf("failed to check existing manifests: %w", err)
 	}
 
-	jobID := result.Data.JobID
-	fmt.Printf("✅ Signing job started: %s\n", jobID)
-	fmt.Printf("📊 Estimated duration: %d seconds\n", result.Data.EstimatedDuration)
-	fmt.Printf("📁 Files found: %d\n", result.Data.FilesFound)
-	fmt.Printf("🔗 Status URL: %s\n", result.Data.StatusURL)
+	batches := planner.RemainingBatches(entries)
+	remaining := 0
+	for _, batch := range batches {
+		remaining += len(batch)
+	}
+	fmt.Printf("✅ %d/%d files already signed under this profile; %d remaining across %d batch(es)\n",
+		len(entries)-remaining, len(entries), remaining, len(batches))
 
-	return jobID, nil
+	responses, err := planner.SubmitRemaining(ctx, entries, tsa)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit remaining batches: %w", err)
+	}
+
+	jobIDs := make([]string, 0, len(responses))
+	for _, resp := range responses {
+		jobID, _ := resp.Data["job_id"].(string)
+		if jobID == "" {
+			return jobIDs, fmt.Errorf("signing job response did not include a job_id")
+		}
+		fmt.Printf("✅ Signing job started: %s\n", jobID)
+		jobIDs = append(jobIDs, jobID)
+	}
+
+	return jobIDs, nil
 }
 
-func monitorJob(ctx context.Context, client *c2c.Client, jobID string, pollInterval int) error {
+func monitorJob(ctx context.Context, client *credlink.Client, jobID string, pollInterval int, emitter *events.Emitter) error {
 	fmt.Printf("\nMonitoring job %s...\n", jobID)
 
-	ticker := time.NewTicker(time.Duration(pollInterval) * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-ticker.C:
-			status, err := client.GetJobStatus(ctx, jobID)
-			if err != nil {
-				return fmt.Errorf("failed to get job status: %w", err)
-			}
-
-			fmt.Printf("Status: %s\n", status.Status)
-			if status.Message != nil {
-				fmt.Printf("Message: %s\n", *status.Message)
-			}
+	runner := credlink.NewJobRunner(client, credlink.JobRunnerOptions{
+		PollInterval: time.Duration(pollInterval) * time.Second,
+	})
 
-			if status.Progress != nil {
-				fmt.Printf("Progress: %d%%\n", *status.Progress)
-			}
+	status, err := runner.Run(ctx, jobID)
+	if err != nil {
+		return err
+	}
 
-			switch status.Status {
-			case "completed":
-				fmt.Println("\n✅ Folder signing completed successfully")
-				if status.Result != nil {
-					fmt.Printf("Result: %v\n", status.Result)
-				}
-				return nil
-			case "failed":
-				fmt.Println("\n❌ Folder signing failed")
-				if status.Error != nil {
-					fmt.Printf("Error: %s\n", *status.Error)
-					return fmt.Errorf("job failed: %s", *status.Error)
-				}
-				return fmt.Errorf("job failed")
-			case "cancelled":
-				fmt.Println("\n⏹️ Folder signing was cancelled")
-				return fmt.Errorf("job was cancelled")
-			}
+	switch status.Status {
+	case "completed":
+		emitter.Emit(events.JobComplete(jobID, "folder signing completed successfully"))
+		return nil
+	case "failed":
+		hint := ""
+		if status.Error != nil {
+			hint = fmt.Sprintf("%v", status.Error)
+		}
+		emitter.Emit(events.Error("SIGN_JOB_FAILED", hint))
+		if status.Error != nil {
+			return fmt.Errorf("job failed: %v", status.Error)
 		}
+		return fmt.Errorf("job failed")
+	case "cancelled":
+		emitter.Emit(events.Error("SIGN_JOB_CANCELLED", "job was cancelled"))
+		return fmt.Errorf("job was cancelled")
 	}
+
+	return nil
 }
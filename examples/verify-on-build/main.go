@@ -5,14 +5,16 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"strconv"
+	"os/signal"
+	"syscall"
 
-	"github.com/c2concierge/sdk-go/v2/c2c"
+	"github.com/credlink/sdk/go/credlink"
+	"github.com/credlink/sdk/go/credlink/events"
 )
 
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run verify_on_build.go <page_url> [policy_id]")
+		fmt.Println("Usage: go run verify_on_build.go <page_url> [policy_id] [output_mode]")
 		os.Exit(1)
 	}
 
@@ -21,6 +23,16 @@ func main() {
 	if len(os.Args) > 2 {
 		policyID = os.Args[2]
 	}
+	outputFlag := "human"
+	if len(os.Args) > 3 {
+		outputFlag = os.Args[3]
+	}
+
+	mode, err := events.ParseOutputMode(outputFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	emitter := events.NewEmitter(mode)
 
 	apiKey := os.Getenv("C2_API_KEY")
 	if apiKey == "" {
@@ -28,50 +40,72 @@ func main() {
 	}
 
 	// Initialize client
-	client := c2c.NewClientWithAPIKey(apiKey)
+	client := credlink.NewClientWithAPIKey(apiKey)
 	defer client.Close()
 
+	// A Ctrl-C (e.g. a build getting canceled) must stop the crawl loop
+	// instead of leaving the process hanging until the page finishes.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Verify page assets
-	err := verifyPageAssets(context.Background(), client, pageURL, policyID)
-	if err != nil {
+	if err := verifyPageAssets(ctx, client, pageURL, policyID, emitter); err != nil {
 		log.Fatalf("Verification failed: %v", err)
 	}
 }
 
-func verifyPageAssets(ctx context.Context, client *c2c.Client, pageURL, policyID string) error {
+func verifyPageAssets(ctx context.Context, client *credlink.Client, pageURL, policyID string, emitter *events.Emitter) error {
 	fmt.Printf("Verifying assets on %s...\n", pageURL)
 
-	resultCh, err := client.VerifyPage(ctx, pageURL, c2c.VerifyPageOptions{
+	followLinks := true
+	maxDepth := 2
+	stream, err := client.VerifyPage(ctx, pageURL, credlink.VerifyPageOptions{
 		PolicyID:    &policyID,
-		FollowLinks: c2c.Ptr(true),
-		MaxDepth:    c2c.Ptr(2),
+		FollowLinks: &followLinks,
+		MaxDepth:    &maxDepth,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to verify page: %w", err)
 	}
+	defer stream.Close()
 
 	verifiedCount := 0
 	totalCount := 0
 	failedAssets := []string{}
 
-	for asset := range resultCh {
+	for {
+		asset, err := stream.Next(ctx)
+		if err != nil {
+			break
+		}
 		totalCount++
+		assetURL := ""
+		if asset.URL != nil {
+			assetURL = *asset.URL
+		}
+		errHint := ""
+		if asset.Error != nil {
+			errHint = *asset.Error
+		}
+		manifestID := ""
+		if asset.ManifestURL != nil {
+			manifestID = *asset.ManifestURL
+		}
+		emitter.Emit(events.AssetVerified(assetURL, asset.Verified, manifestID, errHint))
 		if asset.Verified {
 			verifiedCount++
-			fmt.Printf("  ✅ %s\n", *asset.URL)
 		} else {
-			fmt.Printf("  ❌ %s: %s\n", *asset.URL, *asset.Error)
-			failedAssets = append(failedAssets, *asset.URL)
+			failedAssets = append(failedAssets, assetURL)
 		}
 	}
 
-	fmt.Printf("\nVerification complete: %d/%d assets verified\n", verifiedCount, totalCount)
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("verification canceled: %w", err)
+	}
+
+	emitter.Emit(events.JobComplete(pageURL, fmt.Sprintf("verification complete: %d/%d assets verified", verifiedCount, totalCount)))
 
 	if len(failedAssets) > 0 {
-		fmt.Println("\nFailed assets:")
-		for _, asset := range failedAssets {
-			fmt.Printf("  - %s\n", asset)
-		}
 		return fmt.Errorf("%d assets failed verification", len(failedAssets))
 	}
 
@@ -0,0 +1,172 @@
+package credlink
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// inTotoStatementType is the in-toto Statement v1 type URI.
+// https://github.com/in-toto/attestation/blob/main/spec/v1/statement.md
+const inTotoStatementType = "https://in-toto.io/Statement/v1"
+
+// dsseAttestationPayloadType is the DSSE payloadType for in-toto
+// attestations, per https://github.com/secure-systems-lab/dsse.
+const dsseAttestationPayloadType = "application/vnd.in-toto+json"
+
+// inTotoStatement is the subset of an in-toto Statement v1 CredLink
+// generates: a single subject (the asset being signed), a predicate type,
+// and the predicate body itself.
+type inTotoStatement struct {
+	Type          string               `json:"_type"`
+	Subject       []AttestationSubject `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     json.RawMessage      `json:"predicate"`
+}
+
+// resolveAttestationSpecs reads each spec's PredicatePath into
+// PredicateInline where the caller didn't already supply it, producing the
+// wire form sent to the server - a path on the caller's local filesystem is
+// meaningless once the spec travels over the wire.
+func resolveAttestationSpecs(specs []AttestationSpec) ([]attestationSpecWire, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	wire := make([]attestationSpecWire, 0, len(specs))
+	for _, spec := range specs {
+		predicate := spec.PredicateInline
+		if predicate == nil {
+			if spec.PredicatePath == "" {
+				return nil, NewValidationError(fmt.Sprintf("attestation %s: one of PredicatePath or PredicateInline is required", spec.PredicateType))
+			}
+			content, err := os.ReadFile(spec.PredicatePath)
+			if err != nil {
+				return nil, fmt.Errorf("attestation %s: read predicate %s: %w", spec.PredicateType, spec.PredicatePath, err)
+			}
+			predicate = json.RawMessage(content)
+		}
+		wire = append(wire, attestationSpecWire{PredicateType: spec.PredicateType, Predicate: predicate})
+	}
+	return wire, nil
+}
+
+// buildAttestationEnvelope builds the in-toto Statement covering one signed
+// asset and wraps it as an unsigned DSSE envelope payload - the caller still
+// has to fill in Signatures via the same signing path used for the asset's
+// manifest (TSA/keyless/profile).
+func buildAttestationEnvelope(assetName string, digest []byte, spec attestationSpecWire) (AttestationEnvelope, error) {
+	statement := inTotoStatement{
+		Type:          inTotoStatementType,
+		Subject:       []AttestationSubject{{Name: assetName, SHA256: fmt.Sprintf("%x", digest)}},
+		PredicateType: spec.PredicateType,
+		Predicate:     spec.Predicate,
+	}
+
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return AttestationEnvelope{}, fmt.Errorf("attestation %s: marshal in-toto statement: %w", spec.PredicateType, err)
+	}
+
+	return AttestationEnvelope{
+		PayloadType: dsseAttestationPayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+	}, nil
+}
+
+// attestAsset builds and uploads one DSSE attestation envelope per spec for
+// assetPath, the SignAsset counterpart to the manifest signature it already
+// computed. Keyless requests sign each envelope locally with the same
+// ephemeral key used for the manifest; profile/TSA requests upload the
+// envelope unsigned and rely on the server countersigning it with the
+// profile key it already used for the manifest.
+func (c *Client) attestAsset(ctx context.Context, assetPath string, digest []byte, material *keylessMaterial, specs []AttestationSpec, idempotencyKey string) error {
+	wire, err := resolveAttestationSpecs(specs)
+	if err != nil {
+		return err
+	}
+
+	assetName := filepath.Base(assetPath)
+	hashHex := fmt.Sprintf("%x", digest)
+
+	for _, spec := range wire {
+		envelope, err := buildAttestationEnvelope(assetName, digest, spec)
+		if err != nil {
+			return err
+		}
+		if material != nil {
+			payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+			if err != nil {
+				return fmt.Errorf("attestation %s: decode envelope payload: %w", spec.PredicateType, err)
+			}
+			sig, err := material.signPayload(payload)
+			if err != nil {
+				return fmt.Errorf("attestation %s: %w", spec.PredicateType, err)
+			}
+			envelope.Signatures = []AttestationSignature{{Sig: sig}}
+		}
+
+		if _, err := c.putAttestation(ctx, hashHex, envelope, idempotencyKey); err != nil {
+			return fmt.Errorf("attestation %s: %w", spec.PredicateType, err)
+		}
+	}
+
+	return nil
+}
+
+// putAttestation uploads a single DSSE attestation envelope alongside the
+// manifest at hash, the attestation counterpart to Client.PutManifest.
+func (c *Client) putAttestation(ctx context.Context, hash string, envelope AttestationEnvelope, idempotencyKey string) (*PutAttestationResponse, error) {
+	request := PutAttestationRequest{Envelope: envelope, IdempotencyKey: idempotencyKey}
+
+	reqOptions := NewRequestOptions().WithIdempotencyKey(idempotencyKey)
+
+	resp, err := c.transport.Request(ctx, "PUT", "/manifests/"+hash+"/attestations", request, reqOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var putResp PutAttestationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&putResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &putResp, nil
+}
+
+// VerifyAttestation fetches the in-toto/SLSA attestation envelopes attached
+// to the manifest at digest, verifies their DSSE signatures against the
+// same trust roots C2PA manifest verification uses, and returns the decoded
+// predicates. Pair with GetManifestOptions.IncludeAttestations when the
+// manifest content itself is also needed.
+func (c *Client) VerifyAttestation(ctx context.Context, digest string, opts VerifyAttestationOptions) (*VerifyAttestationResponse, error) {
+	span := c.telemetry.CreateSpan("verify.attestation", map[string]interface{}{
+		"digest":         digest[:16] + "...",
+		"predicate_type": opts.PredicateType,
+		"policy":         opts.Policy,
+	})
+	defer span.End()
+
+	if !c.isValidHash(digest) {
+		return nil, NewValidationError("Invalid hash format. Must be a 64-character hexadecimal string.")
+	}
+
+	request := VerifyAttestationRequest{PredicateType: opts.PredicateType, Policy: opts.Policy}
+
+	resp, err := c.transport.Request(ctx, "POST", "/manifests/"+digest+"/attestations/verify", request, NewRequestOptions())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var verifyResp VerifyAttestationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&verifyResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	span.SetAttribute("verified", verifyResp.Data.Verified)
+
+	return &verifyResp, nil
+}
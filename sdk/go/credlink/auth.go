@@ -0,0 +1,231 @@
+package credlink
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Auth Providers
+// ============================================================================
+
+// AuthProvider supplies the credential Transport sends on every request, in
+// place of a fixed Config.APIKey. Token is called once per outgoing request,
+// so implementations own their own caching/refresh strategy:
+// StaticAPIKeyProvider just returns the same string forever, while
+// RefreshableAuthProvider caches a credential and refreshes it ahead of
+// expiry via a LifetimeWatcher.
+type AuthProvider interface {
+	// Token returns the credential to send and when it expires. A zero
+	// expiresAt means the credential never expires.
+	Token(ctx context.Context) (token string, expiresAt time.Time, err error)
+}
+
+// StaticAPIKeyProvider is the default AuthProvider: it always returns the
+// same API key and never expires, matching the client's pre-AuthProvider
+// behavior.
+type StaticAPIKeyProvider struct {
+	apiKey string
+}
+
+// NewStaticAPIKeyProvider wraps apiKey as an AuthProvider.
+func NewStaticAPIKeyProvider(apiKey string) *StaticAPIKeyProvider {
+	return &StaticAPIKeyProvider{apiKey: apiKey}
+}
+
+// Token implements AuthProvider.
+func (p *StaticAPIKeyProvider) Token(ctx context.Context) (string, time.Time, error) {
+	return p.apiKey, time.Time{}, nil
+}
+
+// RenewBehavior controls how a RefreshableAuthProvider reacts when its
+// Refresh func returns an error.
+type RenewBehavior int
+
+const (
+	// ErrorOnFailure surfaces a failed refresh to the caller of Token.
+	ErrorOnFailure RenewBehavior = iota
+	// IgnoreErrors keeps serving the last known-good token (if any) when a
+	// refresh fails, rather than failing every request until the next
+	// LifetimeWatcher attempt succeeds.
+	IgnoreErrors
+)
+
+// RefreshableAuthProvider wraps a user-supplied Refresh func - e.g. one that
+// exchanges a refresh token or workload identity for a short-lived
+// credential - and caches the result until it's close to expiry. Pair it
+// with a LifetimeWatcher to keep the token renewed in the background instead
+// of only refreshing it lazily on the next Token call.
+type RefreshableAuthProvider struct {
+	// Refresh fetches a new token and its expiry.
+	Refresh func(ctx context.Context) (token string, expiresAt time.Time, err error)
+	// Behavior controls what Token and the LifetimeWatcher do when Refresh
+	// returns an error. Defaults to ErrorOnFailure.
+	Behavior RenewBehavior
+	// Mode labels what kind of credential this provider renews - e.g.
+	// "oidc" or "workload_identity" - so AuthError.NextSteps can tailor its
+	// guidance to the auth mode actually in use. Empty by default.
+	Mode string
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewRefreshableAuthProvider creates a RefreshableAuthProvider around
+// refresh. The first call to Token (or the LifetimeWatcher's first tick)
+// performs the initial refresh.
+func NewRefreshableAuthProvider(refresh func(ctx context.Context) (string, time.Time, error), behavior RenewBehavior) *RefreshableAuthProvider {
+	return &RefreshableAuthProvider{Refresh: refresh, Behavior: behavior}
+}
+
+// Token implements AuthProvider, refreshing first if no token has been
+// fetched yet or the cached one has expired.
+func (p *RefreshableAuthProvider) Token(ctx context.Context) (string, time.Time, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token == "" || (!p.expiresAt.IsZero() && time.Now().After(p.expiresAt)) {
+		if err := p.refreshLocked(ctx); err != nil {
+			return "", time.Time{}, err
+		}
+	}
+	return p.token, p.expiresAt, nil
+}
+
+// ForceRefresh refreshes the token regardless of expiresAt. Transport calls
+// this after a 401 to recover from a token the server considers invalid
+// before its advertised expiry; LifetimeWatcher calls it proactively ahead
+// of expiry.
+func (p *RefreshableAuthProvider) ForceRefresh(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.refreshLocked(ctx)
+}
+
+func (p *RefreshableAuthProvider) refreshLocked(ctx context.Context) error {
+	token, expiresAt, err := p.Refresh(ctx)
+	if err != nil {
+		if p.Behavior == IgnoreErrors && p.token != "" {
+			return nil
+		}
+		return err
+	}
+	p.token, p.expiresAt = token, expiresAt
+	return nil
+}
+
+// ============================================================================
+// Lifetime Watcher
+// ============================================================================
+
+const (
+	lifetimeWatcherMinBackoff = time.Second
+	lifetimeWatcherMaxBackoff = 5 * time.Minute
+	// lifetimeWatcherIdleSleep is how long the watcher waits before
+	// re-checking a provider whose Token reports a zero (never-expiring)
+	// expiry, instead of busy-looping on it.
+	lifetimeWatcherIdleSleep = 5 * time.Minute
+)
+
+// LifetimeWatcher runs in the background and proactively renews a
+// RefreshableAuthProvider's token before it expires, the way Vault's
+// client-side LifetimeWatcher keeps a lease alive. It's started by NewClient
+// when Config.AuthProvider is a *RefreshableAuthProvider, and stopped by
+// Client.Close.
+type LifetimeWatcher struct {
+	provider *RefreshableAuthProvider
+	grace    time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewLifetimeWatcher creates a watcher for provider that renews grace ahead
+// of the token's reported expiry.
+func NewLifetimeWatcher(provider *RefreshableAuthProvider, grace time.Duration) *LifetimeWatcher {
+	return &LifetimeWatcher{
+		provider: provider,
+		grace:    grace,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start launches the watcher's background goroutine.
+func (w *LifetimeWatcher) Start(ctx context.Context) {
+	go w.run(ctx)
+}
+
+func (w *LifetimeWatcher) run(ctx context.Context) {
+	defer close(w.done)
+
+	backoff := lifetimeWatcherMinBackoff
+	for {
+		_, expiresAt, err := w.provider.Token(ctx)
+		if err != nil {
+			if !w.sleep(ctx, backoff) {
+				return
+			}
+			backoff = nextLifetimeWatcherBackoff(backoff)
+			continue
+		}
+		backoff = lifetimeWatcherMinBackoff
+
+		if expiresAt.IsZero() {
+			if !w.sleep(ctx, lifetimeWatcherIdleSleep) {
+				return
+			}
+			continue
+		}
+
+		sleep := time.Until(expiresAt.Add(-w.grace))
+		if sleep < 0 {
+			sleep = 0
+		}
+		if !w.sleep(ctx, sleep) {
+			return
+		}
+
+		if err := w.provider.ForceRefresh(ctx); err != nil && w.provider.Behavior == ErrorOnFailure {
+			if !w.sleep(ctx, backoff) {
+				return
+			}
+			backoff = nextLifetimeWatcherBackoff(backoff)
+		}
+	}
+}
+
+// sleep waits for d, returning false if the watcher was stopped or ctx was
+// canceled in the meantime.
+func (w *LifetimeWatcher) sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-w.stop:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Stop ends the watcher's background goroutine and waits for it to exit. A
+// nil LifetimeWatcher is a no-op, so Client.Close doesn't need to guard the
+// call on whether a refreshable provider was ever configured.
+func (w *LifetimeWatcher) Stop() {
+	if w == nil {
+		return
+	}
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+}
+
+func nextLifetimeWatcherBackoff(current time.Duration) time.Duration {
+	next := current * 2
+	if next > lifetimeWatcherMaxBackoff {
+		next = lifetimeWatcherMaxBackoff
+	}
+	return next
+}
@@ -0,0 +1,322 @@
+package credlink
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Job Callback Server
+// ============================================================================
+
+// defaultCallbackGracePeriod is how long WaitForJob waits for a pushed
+// notification before falling back to polling GetJobStatus.
+const defaultCallbackGracePeriod = 15 * time.Second
+
+// CallbackEvent is delivered for each job status notification the API pushes
+// to a JobCallbackServer.
+type CallbackEvent struct {
+	JobID  string
+	Status *JobStatus
+	Err    error
+}
+
+// JobCallbackServerOptions configures a JobCallbackServer.
+type JobCallbackServerOptions struct {
+	// Network is "tcp" or "unix". Defaults to "tcp".
+	Network string
+	// Addr is the listen address: a TCP host:port (default
+	// "127.0.0.1:7422") or a Unix domain socket path (default
+	// "/run/credlink-cb.sock") when Network is "unix".
+	Addr string
+	// APIKey validates the HMAC-SHA256 signature the API attaches to each
+	// callback request. Defaults to the owning client's API key when
+	// created via Client.EnableJobCallbacks.
+	APIKey string
+	// TLSConfig optionally wraps the listener in TLS. Mainly useful for
+	// peered Unix sockets in sidecar deployments that still want transport
+	// security without exposing a TCP port.
+	TLSConfig *tls.Config
+}
+
+// JobCallbackServer listens for job-completion webhooks pushed by the API,
+// either over TCP or a Unix domain socket, so callers can avoid polling
+// GetJobStatus for long-running folder-signing or page-verification jobs.
+type JobCallbackServer struct {
+	opts     JobCallbackServerOptions
+	listener net.Listener
+	server   *http.Server
+
+	mu      sync.Mutex
+	waiters map[string][]chan CallbackEvent
+}
+
+// NewJobCallbackServer starts listening immediately and returns the server,
+// or an error if the listener could not be created.
+func NewJobCallbackServer(opts JobCallbackServerOptions) (*JobCallbackServer, error) {
+	if opts.Network == "" {
+		opts.Network = "tcp"
+	}
+	if opts.Addr == "" {
+		switch opts.Network {
+		case "unix":
+			opts.Addr = "/run/credlink-cb.sock"
+		default:
+			opts.Addr = "127.0.0.1:7422"
+		}
+	}
+
+	listener, err := net.Listen(opts.Network, opts.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s %s: %w", opts.Network, opts.Addr, err)
+	}
+	if opts.TLSConfig != nil {
+		listener = tls.NewListener(listener, opts.TLSConfig)
+	}
+
+	s := &JobCallbackServer{
+		opts:     opts,
+		listener: listener,
+		waiters:  make(map[string][]chan CallbackEvent),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", s.handleCallback)
+	s.server = &http.Server{Handler: mux}
+
+	go s.server.Serve(listener)
+
+	return s, nil
+}
+
+// CallbackURL returns the URL to register with the API (via
+// SignFolderOptions.CallbackURL / VerifyPageOptions.CallbackURL) so it
+// delivers notifications to this server.
+func (s *JobCallbackServer) CallbackURL() string {
+	if s.opts.Network == "unix" {
+		return "unix://" + s.opts.Addr
+	}
+	scheme := "http"
+	if s.opts.TLSConfig != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/callback", scheme, s.listener.Addr().String())
+}
+
+// Close stops the server and releases the listener (and, for Unix sockets,
+// the socket file).
+func (s *JobCallbackServer) Close() error {
+	return s.server.Close()
+}
+
+func (s *JobCallbackServer) handleCallback(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	if !s.verifySignature(r.Header.Get("X-CredLink-Signature"), body) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var payload struct {
+		JobID  string     `json:"job_id"`
+		Status *JobStatus `json:"status"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	s.dispatch(CallbackEvent{JobID: payload.JobID, Status: payload.Status})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verifySignature checks an "sha256=<hex hmac>" header computed over the raw
+// body with the configured API key as the HMAC key.
+func (s *JobCallbackServer) verifySignature(header string, body []byte) bool {
+	const prefix = "sha256="
+	if !strings.HasPrefix(header, prefix) {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.opts.APIKey))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(strings.TrimPrefix(header, prefix)))
+}
+
+func (s *JobCallbackServer) dispatch(event CallbackEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ch := range s.waiters[event.JobID] {
+		select {
+		case ch <- event:
+		default:
+			// Waiter's buffer is full (shouldn't happen with the buffer
+			// size below); drop rather than block the HTTP handler.
+		}
+	}
+}
+
+// subscribe registers a buffered channel to receive events for jobID. The
+// returned cancel func must be called once the caller stops listening.
+func (s *JobCallbackServer) subscribe(jobID string) (<-chan CallbackEvent, func()) {
+	ch := make(chan CallbackEvent, 4)
+
+	s.mu.Lock()
+	s.waiters[jobID] = append(s.waiters[jobID], ch)
+	s.mu.Unlock()
+
+	cancel := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		chans := s.waiters[jobID]
+		for i, c := range chans {
+			if c == ch {
+				s.waiters[jobID] = append(chans[:i], chans[i+1:]...)
+				break
+			}
+		}
+	}
+
+	return ch, cancel
+}
+
+// ============================================================================
+// Client wiring
+// ============================================================================
+
+// EnableJobCallbacks starts a JobCallbackServer bound to this client and
+// registers it so WaitForJob prefers pushed notifications over polling. The
+// returned server's CallbackURL should be passed as
+// SignFolderOptions.CallbackURL / VerifyPageOptions.CallbackURL when
+// starting a job.
+func (c *Client) EnableJobCallbacks(opts JobCallbackServerOptions) (*JobCallbackServer, error) {
+	if opts.APIKey == "" {
+		opts.APIKey = c.config.APIKey
+	}
+
+	server, err := NewJobCallbackServer(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c.callbackServer = server
+	return server, nil
+}
+
+// WaitForJob waits for jobID to reach a terminal status ("completed",
+// "failed", or "cancelled"). If EnableJobCallbacks has registered a
+// JobCallbackServer, it waits on pushed notifications and falls back to
+// polling GetJobStatus if none arrives within defaultCallbackGracePeriod -
+// the job may have been created with a CallbackURL the network can't
+// actually reach (NAT, firewalled sidecar, etc).
+func (c *Client) WaitForJob(ctx context.Context, jobID string) (*JobStatus, error) {
+	status, err := c.waitForJob(ctx, jobID)
+	if err == nil {
+		c.notifyJobComplete(jobID, status)
+	}
+	return status, err
+}
+
+func (c *Client) waitForJob(ctx context.Context, jobID string) (*JobStatus, error) {
+	if c.callbackServer == nil {
+		return c.pollJobStatus(ctx, jobID, 2*time.Second)
+	}
+
+	events, cancel := c.callbackServer.subscribe(jobID)
+	defer cancel()
+
+	grace := time.NewTimer(defaultCallbackGracePeriod)
+	defer grace.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case event := <-events:
+			if event.Err != nil {
+				return nil, event.Err
+			}
+			if event.Status == nil {
+				continue
+			}
+			switch event.Status.Status {
+			case "completed", "failed", "cancelled":
+				return event.Status, nil
+			}
+			// Non-terminal update: the callback path is alive, so reset the
+			// grace period instead of falling back to polling.
+			if !grace.Stop() {
+				<-grace.C
+			}
+			grace.Reset(defaultCallbackGracePeriod)
+
+		case <-grace.C:
+			return c.pollJobStatus(ctx, jobID, 2*time.Second)
+		}
+	}
+}
+
+// notifyJobComplete emits the "sign" notification event for a terminal
+// SignFolder/SignAsset job, the completion signal this SDK's async job
+// model actually provides (SignFolder itself only returns a job_id).
+func (c *Client) notifyJobComplete(jobID string, status *JobStatus) {
+	if c.notifier == nil || status == nil {
+		return
+	}
+
+	target := NotificationTarget{}
+	if digest, ok := status.Result["digest"].(string); ok {
+		target.Digest = digest
+	}
+	if mediaType, ok := status.Result["media_type"].(string); ok {
+		target.MediaType = mediaType
+	}
+
+	c.notifier.Notify(NotificationEvent{
+		Action:    NotificationActionSign,
+		Target:    target,
+		Result:    NotificationResult{Verified: status.Status == "completed"},
+		Timestamp: time.Now(),
+		RequestID: jobID,
+	})
+}
+
+func (c *Client) pollJobStatus(ctx context.Context, jobID string, interval time.Duration) (*JobStatus, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+			status, err := c.GetJobStatus(ctx, jobID)
+			if err != nil {
+				return nil, err
+			}
+			switch status.Status {
+			case "completed", "failed", "cancelled":
+				return status, nil
+			}
+		}
+	}
+}
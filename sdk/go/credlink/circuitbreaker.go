@@ -0,0 +1,472 @@
+package credlink
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Circuit Breaker Implementation
+// ============================================================================
+
+// Hystrix/resilience4j-style defaults: a 10-bucket, 1s-per-bucket rolling
+// window, tripping once at least volumeThreshold calls have landed in the
+// window and failureRatio of them failed.
+const (
+	defaultWindowBuckets    = 10
+	defaultWindowBucketSize = time.Second
+	defaultFailureRatio     = 0.5
+	defaultVolumeThreshold  = 20
+)
+
+// bucket tracks successes, failures, and calls the breaker itself rejected
+// (because it was open or half-open's probe slots were full) observed
+// within one slice of the rolling window.
+type bucket struct {
+	start     time.Time
+	successes int
+	failures  int
+	rejected  int
+}
+
+// slidingWindow is a ring of buckets spanning bucketCount*bucketSize of
+// wall-clock time. Unlike a monotonic failure counter - which a single
+// success never clears - only calls within the window count towards
+// tripping the breaker, so a dependency that failed heavily an hour ago but
+// has been healthy since doesn't stay permanently one failure away from
+// opening.
+type slidingWindow struct {
+	bucketSize time.Duration
+	buckets    []bucket
+}
+
+func newSlidingWindow(bucketCount int, bucketSize time.Duration) *slidingWindow {
+	return &slidingWindow{
+		bucketSize: bucketSize,
+		buckets:    make([]bucket, bucketCount),
+	}
+}
+
+// slotFor maps now to its bucket, resetting it in place if the ring has
+// rotated all the way back around to this slot since it was last written.
+func (w *slidingWindow) slotFor(now time.Time) *bucket {
+	slot := now.Truncate(w.bucketSize)
+	idx := int(slot.UnixNano()/int64(w.bucketSize)) % len(w.buckets)
+	b := &w.buckets[idx]
+	if !b.start.Equal(slot) {
+		*b = bucket{start: slot}
+	}
+	return b
+}
+
+func (w *slidingWindow) recordSuccess(now time.Time)  { w.slotFor(now).successes++ }
+func (w *slidingWindow) recordFailure(now time.Time)  { w.slotFor(now).failures++ }
+func (w *slidingWindow) recordRejected(now time.Time) { w.slotFor(now).rejected++ }
+
+// totals sums counts across buckets still inside the window as of now;
+// buckets the window has aged past (or that were never written) don't
+// count.
+func (w *slidingWindow) totals(now time.Time) (successes, failures, rejected int) {
+	span := time.Duration(len(w.buckets)) * w.bucketSize
+	for i := range w.buckets {
+		b := &w.buckets[i]
+		if b.start.IsZero() || now.Sub(b.start) >= span {
+			continue
+		}
+		successes += b.successes
+		failures += b.failures
+		rejected += b.rejected
+	}
+	return
+}
+
+// CircuitBreakerState represents a snapshot of a circuit breaker's state.
+type CircuitBreakerState struct {
+	State           string
+	LastFailureTime time.Time
+	NextAttemptTime time.Time
+}
+
+// CircuitBreakerSnapshot is a read-only view of one breaker's state and
+// rolling-window totals, shaped for a Prometheus scrape handler built on
+// top of Config.Telemetry.
+type CircuitBreakerSnapshot struct {
+	Endpoint        string
+	State           string
+	Successes       int
+	Failures        int
+	Rejected        int
+	LastFailureTime time.Time
+	NextAttemptTime time.Time
+}
+
+// CircuitBreaker prevents cascading failures by tripping open once a
+// rolling window of recent calls crosses failureRatio/volumeThreshold, and
+// probing recovery with a bounded number of half-open calls. All state is
+// guarded by mu, since Execute is called concurrently by every in-flight
+// request sharing the breaker.
+type CircuitBreaker struct {
+	name                string
+	failureRatio        float64
+	volumeThreshold     int
+	baseRecoveryTimeout time.Duration
+	maxRecoveryTimeout  time.Duration
+	halfOpenMaxCalls    int
+	requiredSuccesses   int
+
+	// StateChangeHook, if set, is invoked synchronously (outside mu) on
+	// every state transition, so telemetry can record it without the
+	// breaker itself depending on telemetry types.
+	StateChangeHook func(name, from, to string)
+
+	mu                sync.Mutex
+	state             CircuitBreakerState
+	window            *slidingWindow
+	recoveryTimeout   time.Duration
+	halfOpenSem       chan struct{}
+	halfOpenSuccesses int
+	// tripped is closed when the breaker transitions to "open", so any
+	// call already in flight - whose fn is running with a ctx derived from
+	// this channel - is canceled immediately instead of being left to run
+	// to completion against a dependency the breaker has just given up on.
+	// A fresh channel replaces it on every transition into "open".
+	tripped chan struct{}
+}
+
+// NewCircuitBreaker creates a circuit breaker with CredLink's default
+// tuning: a 10-bucket/1s rolling window, tripping at a 50% failure ratio
+// once at least 20 calls have landed in the window, re-opening for 60s the
+// first time, and requiring 3 consecutive half-open successes to close.
+func NewCircuitBreaker(name string) *CircuitBreaker {
+	return newCircuitBreaker(name, 60*time.Second, 60*time.Second)
+}
+
+// NewCircuitBreakerWithRetryConfig creates a circuit breaker whose re-open
+// cool-down grows exponentially - starting at retries.BaseMs, capped at
+// retries.MaxMs - each time a half-open probe fails, instead of always
+// reopening for the same fixed duration. Transport uses this so a
+// dependency that keeps failing its recovery probes backs off the same way
+// DoWithRetry backs off individual request retries.
+func NewCircuitBreakerWithRetryConfig(name string, retries *RetryConfig) *CircuitBreaker {
+	if retries == nil {
+		retries = DefaultRetryConfig()
+	}
+	return newCircuitBreaker(name, retries.BaseMs, retries.MaxMs)
+}
+
+func newCircuitBreaker(name string, baseRecoveryTimeout, maxRecoveryTimeout time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		name:                name,
+		failureRatio:        defaultFailureRatio,
+		volumeThreshold:     defaultVolumeThreshold,
+		baseRecoveryTimeout: baseRecoveryTimeout,
+		maxRecoveryTimeout:  maxRecoveryTimeout,
+		halfOpenMaxCalls:    3,
+		requiredSuccesses:   3,
+		state:               CircuitBreakerState{State: "closed"},
+		window:              newSlidingWindow(defaultWindowBuckets, defaultWindowBucketSize),
+		recoveryTimeout:     baseRecoveryTimeout,
+		tripped:             make(chan struct{}),
+	}
+}
+
+// Execute runs fn with circuit breaker protection. If the breaker is open
+// and its cool-down hasn't elapsed yet, fn is never called and Execute
+// returns a *CircuitOpenError immediately. In half-open, concurrent probes
+// are capped at halfOpenMaxCalls; once that cap is hit, further callers
+// also get a *CircuitOpenError rather than piling onto the probe. Every
+// rejection is recorded in the rolling window alongside successes/failures,
+// so Snapshot reflects load the breaker shed, not just calls it let through.
+//
+// If the breaker trips open while fn is still running - e.g. a concurrent
+// call's failure crosses the window's failureRatio/volumeThreshold - the
+// context passed to fn is canceled immediately, so callers observing
+// ctx.Done() (an in-flight HTTP request, in Transport's case) unblock right
+// away instead of waiting out their own timeout against a dependency the
+// breaker has already given up on.
+func (cb *CircuitBreaker) Execute(ctx context.Context, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	now := time.Now()
+
+	cb.mu.Lock()
+	var transitions []stateTransition
+	if cb.state.State == "open" {
+		if now.Before(cb.state.NextAttemptTime) {
+			nextAttempt := cb.state.NextAttemptTime
+			cb.window.recordRejected(now)
+			cb.mu.Unlock()
+			return nil, NewCircuitOpenError(cb.name, nextAttempt)
+		}
+		transitions = append(transitions, cb.setStateLocked("half-open", now))
+		cb.halfOpenSem = make(chan struct{}, cb.halfOpenMaxCalls)
+	}
+
+	var release func()
+	if cb.state.State == "half-open" {
+		select {
+		case cb.halfOpenSem <- struct{}{}:
+			sem := cb.halfOpenSem
+			release = func() { <-sem }
+		default:
+			nextAttempt := cb.state.NextAttemptTime
+			cb.window.recordRejected(now)
+			cb.mu.Unlock()
+			cb.notify(transitions)
+			return nil, NewCircuitOpenError(cb.name, nextAttempt)
+		}
+	}
+	tripped := cb.tripped
+	cb.mu.Unlock()
+	cb.notify(transitions)
+	if release != nil {
+		defer release()
+	}
+
+	callCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-tripped:
+			cancel()
+		case <-callCtx.Done():
+		}
+	}()
+
+	result, err := fn(callCtx)
+
+	cb.mu.Lock()
+	var transition stateTransition
+	if err != nil {
+		transition = cb.onFailureLocked(time.Now())
+	} else {
+		transition = cb.onSuccessLocked(time.Now())
+	}
+	cb.mu.Unlock()
+	cb.notify([]stateTransition{transition})
+
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (cb *CircuitBreaker) onSuccessLocked(now time.Time) stateTransition {
+	cb.window.recordSuccess(now)
+
+	if cb.state.State == "half-open" {
+		cb.halfOpenSuccesses++
+		if cb.halfOpenSuccesses >= cb.requiredSuccesses {
+			return cb.setStateLocked("closed", now)
+		}
+	}
+	return stateTransition{}
+}
+
+func (cb *CircuitBreaker) onFailureLocked(now time.Time) stateTransition {
+	cb.state.LastFailureTime = now
+	cb.window.recordFailure(now)
+
+	switch cb.state.State {
+	case "half-open":
+		// A single half-open failure re-opens the breaker with a longer
+		// cool-down rather than resetting to baseRecoveryTimeout, since a
+		// failed recovery probe suggests the dependency needs more time.
+		cb.recoveryTimeout *= 2
+		if cb.recoveryTimeout > cb.maxRecoveryTimeout {
+			cb.recoveryTimeout = cb.maxRecoveryTimeout
+		}
+		return cb.setStateLocked("open", now)
+	case "closed":
+		successes, failures, _ := cb.window.totals(now)
+		total := successes + failures
+		if total >= cb.volumeThreshold && float64(failures)/float64(total) >= cb.failureRatio {
+			return cb.setStateLocked("open", now)
+		}
+	}
+	return stateTransition{}
+}
+
+// stateTransition records a from/to state change so Execute can invoke
+// StateChangeHook after releasing mu, never while holding it.
+type stateTransition struct {
+	changed  bool
+	from, to string
+}
+
+// setStateLocked must be called with mu held.
+func (cb *CircuitBreaker) setStateLocked(newState string, now time.Time) stateTransition {
+	if newState == cb.state.State {
+		return stateTransition{}
+	}
+	previous := cb.state.State
+	cb.state.State = newState
+
+	switch newState {
+	case "open":
+		cb.state.NextAttemptTime = now.Add(cb.recoveryTimeout)
+		close(cb.tripped)
+		cb.tripped = make(chan struct{})
+	case "closed":
+		cb.recoveryTimeout = cb.baseRecoveryTimeout
+		cb.halfOpenSuccesses = 0
+	case "half-open":
+		cb.halfOpenSuccesses = 0
+	}
+
+	return stateTransition{changed: true, from: previous, to: newState}
+}
+
+// notify invokes StateChangeHook for each changed transition, called with
+// mu released.
+func (cb *CircuitBreaker) notify(transitions []stateTransition) {
+	if cb.StateChangeHook == nil {
+		return
+	}
+	for _, t := range transitions {
+		if t.changed {
+			cb.StateChangeHook(cb.name, t.from, t.to)
+		}
+	}
+}
+
+// GetState returns the current circuit breaker state.
+func (cb *CircuitBreaker) GetState() string {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.state.State
+}
+
+// Snapshot returns cb's current state and rolling-window totals under name.
+func (cb *CircuitBreaker) Snapshot(name string) CircuitBreakerSnapshot {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	successes, failures, rejected := cb.window.totals(time.Now())
+	return CircuitBreakerSnapshot{
+		Endpoint:        name,
+		State:           cb.state.State,
+		Successes:       successes,
+		Failures:        failures,
+		Rejected:        rejected,
+		LastFailureTime: cb.state.LastFailureTime,
+		NextAttemptTime: cb.state.NextAttemptTime,
+	}
+}
+
+// ============================================================================
+// Circuit Breaker Registry
+// ============================================================================
+
+// CircuitBreakerRegistry hands out one CircuitBreaker per (method, endpoint)
+// key, so a failing dependency (e.g. verify/asset under load) trips its own
+// breaker instead of one global breaker starving unrelated, healthy
+// endpoints.
+type CircuitBreakerRegistry struct {
+	retries *RetryConfig
+	// OnStateChange, if set, is assigned as every breaker's StateChangeHook
+	// at creation time, with name set to that breaker's endpoint key.
+	OnStateChange func(endpoint, from, to string)
+
+	mu       sync.Mutex
+	breakers map[string]*CircuitBreaker
+}
+
+// NewCircuitBreakerRegistry creates a registry whose breakers back off
+// retries-style (base/max from retries, or from DefaultRetryConfig if nil).
+func NewCircuitBreakerRegistry(retries *RetryConfig) *CircuitBreakerRegistry {
+	return &CircuitBreakerRegistry{
+		retries:  retries,
+		breakers: make(map[string]*CircuitBreaker),
+	}
+}
+
+// Get returns the breaker for method+path, creating it on first use.
+func (r *CircuitBreakerRegistry) Get(method, path string) *CircuitBreaker {
+	key := endpointKey(method, path)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cb, ok := r.breakers[key]; ok {
+		return cb
+	}
+
+	cb := NewCircuitBreakerWithRetryConfig(key, r.retries)
+	if r.OnStateChange != nil {
+		cb.StateChangeHook = r.OnStateChange
+	}
+	r.breakers[key] = cb
+	return cb
+}
+
+// Snapshot returns a CircuitBreakerSnapshot for every endpoint that has
+// handled at least one request so far, sorted by endpoint for stable
+// scrape output.
+func (r *CircuitBreakerRegistry) Snapshot() []CircuitBreakerSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshots := make([]CircuitBreakerSnapshot, 0, len(r.breakers))
+	for key, cb := range r.breakers {
+		snapshots = append(snapshots, cb.Snapshot(key))
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Endpoint < snapshots[j].Endpoint })
+	return snapshots
+}
+
+// endpointKey derives a stable breaker key from method and path by
+// collapsing path segments that look like dynamic identifiers (hashes, job
+// IDs, digests) down to a placeholder, so e.g. PUT /manifests/abc123 and PUT
+// /manifests/def456 share one breaker instead of each minting their own.
+func endpointKey(method, path string) string {
+	segments := splitPathSegments(path)
+	for i, seg := range segments {
+		if looksLikeDynamicSegment(seg) {
+			segments[i] = "{id}"
+		}
+	}
+	normalized := "/" + joinPathSegments(segments)
+	return method + " " + normalized
+}
+
+func splitPathSegments(path string) []string {
+	var segments []string
+	start := 0
+	trimmed := path
+	for len(trimmed) > 0 && trimmed[0] == '/' {
+		trimmed = trimmed[1:]
+	}
+	for i := 0; i < len(trimmed); i++ {
+		if trimmed[i] == '/' {
+			segments = append(segments, trimmed[start:i])
+			start = i + 1
+		}
+	}
+	segments = append(segments, trimmed[start:])
+	return segments
+}
+
+func joinPathSegments(segments []string) string {
+	result := ""
+	for i, seg := range segments {
+		if i > 0 {
+			result += "/"
+		}
+		result += seg
+	}
+	return result
+}
+
+// looksLikeDynamicSegment reports whether seg looks like an identifier
+// (contains a digit, as job IDs and hex digests do) rather than a fixed
+// keyword segment of an endpoint path.
+func looksLikeDynamicSegment(seg string) bool {
+	if seg == "" {
+		return false
+	}
+	for _, r := range seg {
+		if r >= '0' && r <= '9' {
+			return true
+		}
+	}
+	return len(seg) > 20
+}
@@ -2,10 +2,13 @@ package credlink
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
+	"os"
 	"time"
 )
 
@@ -18,6 +21,25 @@ type Client struct {
 	config    *Config
 	transport *Transport
 	telemetry *TelemetryManager
+
+	// callbackServer, when non-nil (set via EnableJobCallbacks), lets
+	// WaitForJob wait on pushed completion notifications instead of
+	// polling GetJobStatus.
+	callbackServer *JobCallbackServer
+
+	// notifier, when non-nil (config.Notifications is non-empty), fans
+	// verify/sign/inject/manifest.put decisions out to webhook endpoints.
+	notifier *Notifier
+
+	// profileManager, created on first EnrollProfile call, tracks ACME-
+	// issued signing profile certificates and auto-renews them once
+	// StartProfileRenewal is called.
+	profileManager *ProfileManager
+
+	// authWatcher, non-nil when config.AuthProvider is a
+	// *RefreshableAuthProvider, keeps its token renewed ahead of expiry in
+	// the background.
+	authWatcher *LifetimeWatcher
 }
 
 // NewClient creates a new CredLink client
@@ -25,14 +47,29 @@ func NewClient(config *Config) *Client {
 	if config == nil {
 		config = DefaultConfig()
 	}
-	
+
 	transport := NewTransport(config)
-	telemetry := NewTelemetryManager(config.Telemetry)
-	
+
+	var notifier *Notifier
+	if len(config.Notifications) > 0 {
+		notifier = NewNotifier(config.Notifications)
+	}
+
+	var authWatcher *LifetimeWatcher
+	if refreshable, ok := config.AuthProvider.(*RefreshableAuthProvider); ok {
+		authWatcher = NewLifetimeWatcher(refreshable, config.AuthRenewGrace)
+		authWatcher.Start(context.Background())
+	}
+
 	return &Client{
 		config:    config,
 		transport: transport,
-		telemetry: telemetry,
+		// Share the transport's TelemetryManager rather than creating a
+		// second one, so client-level spans (verify.asset, sign.folder,
+		// ...) batch through the same OTLP exporter as http.request spans.
+		telemetry:   transport.Telemetry(),
+		notifier:    notifier,
+		authWatcher: authWatcher,
 	}
 }
 
@@ -55,26 +92,40 @@ type VerifyAssetOptions struct {
 	CachedETag            *string
 	CachedCertThumbprints []string
 	EnableDelta           *bool
+	// Keyless, when set, asks the server to verify the asset's signature
+	// against a keyless (Fulcio/Rekor-style) identity instead of a
+	// pre-provisioned certificate.
+	Keyless *KeylessVerifyOptions
 }
 
 // VerifyAsset verifies a single asset by URL or direct content
 func (c *Client) VerifyAsset(ctx context.Context, urlOrBuffer string, options VerifyAssetOptions) (*VerifyAssetResponse, error) {
 	span := c.telemetry.CreateSpan("verify.asset", map[string]interface{}{
-		"policy_id":      options.PolicyID,
+		"policy_id":        options.PolicyID,
 		"has_content_type": options.ContentType != nil,
-		"enable_delta":   options.EnableDelta != nil && *options.EnableDelta,
+		"enable_delta":     options.EnableDelta != nil && *options.EnableDelta,
 	})
 	defer span.End()
 
 	// Determine if this is a URL or buffer content
 	isURL := c.isValidURL(urlOrBuffer)
-	
+
 	request := VerifyAssetRequest{
 		PolicyID:              options.PolicyID,
 		CachedCertThumbprints: options.CachedCertThumbprints,
 		EnableDelta:           options.EnableDelta != nil && *options.EnableDelta,
 	}
 
+	if options.Keyless != nil {
+		if options.Keyless.ExpectedIdentity != "" {
+			request.ExpectedIdentity = &options.Keyless.ExpectedIdentity
+		}
+		if options.Keyless.ExpectedIssuer != "" {
+			request.ExpectedIssuer = &options.Keyless.ExpectedIssuer
+		}
+		request.RequireTLog = options.Keyless.RequireTLog
+	}
+
 	if isURL {
 		request.AssetURL = &urlOrBuffer
 	} else {
@@ -105,6 +156,21 @@ func (c *Client) VerifyAsset(ctx context.Context, urlOrBuffer string, options Ve
 	span.SetAttribute("verified", verifyResp.Data.Verified)
 	span.SetAttribute("cached", verifyResp.Data.Cached)
 
+	target := NotificationTarget{}
+	if isURL {
+		target.URL = urlOrBuffer
+	}
+	if options.ContentType != nil {
+		target.MediaType = *options.ContentType
+	}
+	c.notifier.Notify(NotificationEvent{
+		Action:    NotificationActionVerify,
+		Target:    target,
+		Result:    NotificationResult{Verified: verifyResp.Data.Verified, PolicyID: options.PolicyID},
+		Timestamp: time.Now(),
+		RequestID: verifyResp.RequestID,
+	})
+
 	return &verifyResp, nil
 }
 
@@ -114,6 +180,10 @@ type VerifyPageOptions struct {
 	MaxDepth    *int
 	PolicyID    *string
 	Timeout     *time.Duration
+	// CallbackURL, when set, is registered with the API so job completion
+	// is pushed to a JobCallbackServer instead of requiring WaitForJob to
+	// poll GetJobStatus. See Client.EnableJobCallbacks.
+	CallbackURL *string
 }
 
 // VerifyPageResult represents a result from page verification
@@ -126,8 +196,10 @@ type VerifyPageResult struct {
 	NextToken   *string
 }
 
-// VerifyPage verifies all assets on a web page
-func (c *Client) VerifyPage(ctx context.Context, pageURL string, options VerifyPageOptions) (<-chan VerifyPageResult, error) {
+// VerifyPage verifies all assets on a web page. The returned ResultStream
+// lets a caller bound how long to wait for the *next* asset
+// (SetReadDeadline) independently of the overall request's ctx.
+func (c *Client) VerifyPage(ctx context.Context, pageURL string, options VerifyPageOptions) (*ResultStream[VerifyPageResult], error) {
 	span := c.telemetry.CreateSpan("verify.page", map[string]interface{}{
 		"url":          c.sanitizeURL(pageURL),
 		"follow_links": options.FollowLinks != nil && *options.FollowLinks,
@@ -157,6 +229,7 @@ func (c *Client) VerifyPage(ctx context.Context, pageURL string, options VerifyP
 		MaxDepth:    maxDepth,
 		PolicyID:    policyID,
 		Timeout:     c.durationToIntPtr(options.Timeout),
+		CallbackURL: options.CallbackURL,
 	}
 
 	reqOptions := NewRequestOptions()
@@ -164,36 +237,70 @@ func (c *Client) VerifyPage(ctx context.Context, pageURL string, options VerifyP
 		reqOptions = reqOptions.WithTimeout(*options.Timeout)
 	}
 
-	ch, err := c.transport.RequestStream(ctx, "POST", "/verify/page", request, reqOptions)
+	streamCtx, cancel := context.WithCancel(ctx)
+
+	ch, streamErrs, err := c.transport.RequestStream(streamCtx, "POST", "/verify/page", request, reqOptions)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
 	resultCh := make(chan VerifyPageResult, 10)
+	stream := newResultStream[VerifyPageResult](resultCh, func() error {
+		cancel()
+		return nil
+	})
 
 	go func() {
 		defer close(resultCh)
-		for item := range ch {
-			result := VerifyPageResult{
-				URL:       stringPtr(item["url"].(string)),
-				Verified:  item["verified"].(bool),
-				HasMore:   item["has_more"].(bool),
-				NextToken: stringPtr(item["next_token"].(string)),
-			}
+		for {
+			select {
+			case <-streamCtx.Done():
+				stream.setErr(streamCtx.Err())
+				return
+			case event, ok := <-ch:
+				if !ok {
+					return
+				}
+				item := event.Data
+				result := VerifyPageResult{
+					URL:       stringPtr(item["url"].(string)),
+					Verified:  item["verified"].(bool),
+					HasMore:   item["has_more"].(bool),
+					NextToken: stringPtr(item["next_token"].(string)),
+				}
 
-			if manifestURL, ok := item["manifest_url"].(string); ok && manifestURL != "" {
-				result.ManifestURL = &manifestURL
-			}
+				if manifestURL, ok := item["manifest_url"].(string); ok && manifestURL != "" {
+					result.ManifestURL = &manifestURL
+				}
 
-			if errMsg, ok := item["error"].(string); ok && errMsg != "" {
-				result.Error = &errMsg
-			}
+				if errMsg, ok := item["error"].(string); ok && errMsg != "" {
+					result.Error = &errMsg
+				}
 
-			resultCh <- result
+				target := NotificationTarget{}
+				if result.URL != nil {
+					target.URL = *result.URL
+				}
+				c.notifier.Notify(NotificationEvent{
+					Action:    NotificationActionVerify,
+					Target:    target,
+					Result:    NotificationResult{Verified: result.Verified, PolicyID: policyID},
+					Timestamp: time.Now(),
+				})
+
+				resultCh <- result
+			case streamErr, ok := <-streamErrs:
+				if !ok {
+					continue
+				}
+				errMsg := streamErr.Error()
+				resultCh <- VerifyPageResult{Error: &errMsg}
+			}
 		}
 	}()
 
-	return resultCh, nil
+	return stream, nil
 }
 
 // ============================================================================
@@ -202,22 +309,24 @@ func (c *Client) VerifyPage(ctx context.Context, pageURL string, options VerifyP
 
 // BatchVerifyOptions defines options for batch verification
 type BatchVerifyOptions struct {
-	PolicyID       *string
-	Parallel       *bool
+	PolicyID        *string
+	Parallel        *bool
 	TimeoutPerAsset *time.Duration
 }
 
 // BatchVerifyResult represents a result from batch verification
 type BatchVerifyResult struct {
-	Asset    AssetReference
-	Result   *VerificationResult
-	Error    map[string]interface{}
-	HasMore  bool
+	Asset     AssetReference
+	Result    *VerificationResult
+	Error     map[string]interface{}
+	HasMore   bool
 	NextToken *string
 }
 
-// BatchVerify verifies multiple assets
-func (c *Client) BatchVerify(ctx context.Context, assets []string, options BatchVerifyOptions) (<-chan BatchVerifyResult, error) {
+// BatchVerify verifies multiple assets. The returned ResultStream lets a
+// caller bound how long to wait for the *next* result independently of the
+// overall request's ctx.
+func (c *Client) BatchVerify(ctx context.Context, assets []string, options BatchVerifyOptions) (*ResultStream[BatchVerifyResult], error) {
 	span := c.telemetry.CreateSpan("batch.verify", map[string]interface{}{
 		"asset_count": len(assets),
 		"parallel":    options.Parallel != nil && *options.Parallel,
@@ -242,9 +351,9 @@ func (c *Client) BatchVerify(ctx context.Context, assets []string, options Batch
 	}
 
 	request := BatchVerifyRequest{
-		Assets:         normalizedAssets,
-		PolicyID:       policyID,
-		Parallel:       parallel,
+		Assets:          normalizedAssets,
+		PolicyID:        policyID,
+		Parallel:        parallel,
 		TimeoutPerAsset: c.durationToIntPtr(options.TimeoutPerAsset),
 	}
 
@@ -253,16 +362,41 @@ func (c *Client) BatchVerify(ctx context.Context, assets []string, options Batch
 		reqOptions = reqOptions.WithTimeout(*options.TimeoutPerAsset)
 	}
 
-	ch, err := c.transport.RequestStream(ctx, "POST", "/batch/verify", request, reqOptions)
+	streamCtx, cancel := context.WithCancel(ctx)
+
+	ch, streamErrs, err := c.transport.RequestStream(streamCtx, "POST", "/batch/verify", request, reqOptions)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
 	resultCh := make(chan BatchVerifyResult, 10)
+	stream := newResultStream[BatchVerifyResult](resultCh, func() error {
+		cancel()
+		return nil
+	})
 
 	go func() {
 		defer close(resultCh)
-		for item := range ch {
+		for {
+			var item map[string]interface{}
+			select {
+			case <-streamCtx.Done():
+				stream.setErr(streamCtx.Err())
+				return
+			case event, ok := <-ch:
+				if !ok {
+					return
+				}
+				item = event.Data
+			case streamErr, ok := <-streamErrs:
+				if !ok {
+					continue
+				}
+				resultCh <- BatchVerifyResult{Error: map[string]interface{}{"message": streamErr.Error()}}
+				continue
+			}
+
 			result := BatchVerifyResult{
 				HasMore:   item["has_more"].(bool),
 				NextToken: stringPtr(item["next_token"].(string)),
@@ -289,11 +423,20 @@ func (c *Client) BatchVerify(ctx context.Context, assets []string, options Batch
 				result.Error = errorData
 			}
 
+			if result.Result != nil {
+				c.notifier.Notify(NotificationEvent{
+					Action:    NotificationActionVerify,
+					Target:    NotificationTarget{URL: result.Asset.URL},
+					Result:    NotificationResult{Verified: result.Result.Verified, PolicyID: policyID},
+					Timestamp: time.Now(),
+				})
+			}
+
 			resultCh <- result
 		}
 	}()
 
-	return resultCh, nil
+	return stream, nil
 }
 
 // ============================================================================
@@ -340,6 +483,13 @@ func (c *Client) InjectLink(ctx context.Context, html string, options InjectLink
 
 	span.SetAttribute("links_injected", linkResp.Data["links_injected"])
 
+	c.notifier.Notify(NotificationEvent{
+		Action:    NotificationActionInject,
+		Target:    NotificationTarget{URL: options.ManifestURL},
+		Timestamp: time.Now(),
+		RequestID: linkResp.RequestID,
+	})
+
 	return &linkResp, nil
 }
 
@@ -354,6 +504,25 @@ type SignFolderOptions struct {
 	Recursive      *bool
 	FilePatterns   []string
 	IdempotencyKey *string
+	// CallbackURL, when set, is registered with the API so job completion
+	// is pushed to a JobCallbackServer instead of requiring WaitForJob to
+	// poll GetJobStatus. See Client.EnableJobCallbacks.
+	CallbackURL *string
+	// Files, when non-empty, signs exactly these paths (relative to
+	// folderPath) instead of having the server walk the folder and match
+	// FilePatterns itself. Used by FolderSignPlanner to submit only the
+	// files a prior plan run determined still need signing.
+	Files []string
+	// Keyless, when set, signs with an ephemeral key and ID-token-derived
+	// certificate instead of a pre-provisioned signing profile. The
+	// resulting certificate chain and a transparency log entry are
+	// attached to the signing request instead of relying on ProfileID.
+	Keyless *KeylessOptions
+	// Attestations, when non-empty, attaches one in-toto Statement per spec
+	// (SLSA provenance, an SPDX/CycloneDX SBOM, or a custom predicate) to
+	// each signed asset as a DSSE-enveloped attestation alongside its C2PA
+	// manifest. See Client.VerifyAttestation.
+	Attestations []AttestationSpec
 }
 
 // SignFolder retro-signs a folder with RFC-3161 timestamps
@@ -391,6 +560,35 @@ func (c *Client) SignFolder(ctx context.Context, folderPath string, options Sign
 		Recursive:      recursive,
 		FilePatterns:   options.FilePatterns,
 		IdempotencyKey: idempotencyKey,
+		CallbackURL:    options.CallbackURL,
+		Files:          options.Files,
+	}
+
+	if len(options.Attestations) > 0 {
+		wire, err := resolveAttestationSpecs(options.Attestations)
+		if err != nil {
+			return nil, err
+		}
+		request.Attestations = wire
+	}
+
+	// Folder signing processes files asynchronously on the server (the
+	// response only carries a job_id), so there's no single digest to sign
+	// locally here; the keyless material is forwarded and the server
+	// attaches it per file as the job runs. Compare SignAsset, which signs
+	// synchronously and can complete the local sign + tlog submission.
+	if options.Keyless != nil {
+		material, err := c.obtainKeylessCertificate(ctx, *options.Keyless)
+		if err != nil {
+			return nil, err
+		}
+		pubKey, err := material.publicKeyPEM()
+		if err != nil {
+			return nil, err
+		}
+		request.KeylessIDToken = material.idToken
+		request.KeylessCertificateChain = material.certChain
+		request.KeylessPublicKey = pubKey
 	}
 
 	reqOptions := NewRequestOptions().WithIdempotencyKey(idempotencyKey)
@@ -412,6 +610,107 @@ func (c *Client) SignFolder(ctx context.Context, folderPath string, options Sign
 	return &signResp, nil
 }
 
+// SignAsset signs a single asset synchronously, optionally via the keyless
+// flow (KeylessOptions), the single-file counterpart to SignFolder. Unlike
+// SignFolder, SignAsset computes one digest over the asset's contents up
+// front, so it can complete the full keyless flow - local signature plus
+// transparency log submission - in one call.
+func (c *Client) SignAsset(ctx context.Context, assetPath string, options SignFolderOptions) (*SignFolderResponse, error) {
+	span := c.telemetry.CreateSpan("sign.asset", map[string]interface{}{
+		"asset_path": assetPath,
+		"profile_id": options.ProfileID,
+		"tsa":        options.TSA,
+	})
+	defer span.End()
+
+	tsa := false
+	if options.TSA != nil {
+		tsa = *options.TSA
+	}
+
+	idempotencyKey := ""
+	if options.IdempotencyKey != nil {
+		idempotencyKey = *options.IdempotencyKey
+	}
+	if idempotencyKey == "" {
+		idempotencyKey = c.generateIdempotencyKey()
+	}
+
+	request := SignAssetRequest{
+		AssetPath:      assetPath,
+		ProfileID:      options.ProfileID,
+		TSA:            tsa,
+		IdempotencyKey: idempotencyKey,
+		CallbackURL:    options.CallbackURL,
+	}
+
+	var material *keylessMaterial
+	var digest []byte
+	if options.Keyless != nil || len(options.Attestations) > 0 {
+		content, err := os.ReadFile(assetPath)
+		if err != nil {
+			return nil, fmt.Errorf("sign.asset: read %s: %w", assetPath, err)
+		}
+		sum := sha256.Sum256(content)
+		digest = sum[:]
+	}
+
+	if options.Keyless != nil {
+		var err error
+		material, err = c.obtainKeylessCertificate(ctx, *options.Keyless)
+		if err != nil {
+			return nil, err
+		}
+		pubKey, err := material.publicKeyPEM()
+		if err != nil {
+			return nil, err
+		}
+		request.KeylessIDToken = material.idToken
+		request.KeylessCertificateChain = material.certChain
+		request.KeylessPublicKey = pubKey
+	}
+
+	reqOptions := NewRequestOptions().WithIdempotencyKey(idempotencyKey)
+
+	resp, err := c.transport.Request(ctx, "POST", "/sign/asset", request, reqOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var signResp SignFolderResponse
+	if err := json.NewDecoder(resp.Body).Decode(&signResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if material != nil {
+		signature, err := material.signDigest(digest)
+		if err != nil {
+			return nil, err
+		}
+		tlogData, err := c.submitTLogEntry(ctx, material, fmt.Sprintf("%x", digest), signature)
+		if err != nil {
+			return nil, err
+		}
+		if signResp.Data == nil {
+			signResp.Data = map[string]interface{}{}
+		}
+		for k, v := range tlogData {
+			signResp.Data[k] = v
+		}
+	}
+
+	if len(options.Attestations) > 0 {
+		if err := c.attestAsset(ctx, assetPath, digest, material, options.Attestations, idempotencyKey); err != nil {
+			return nil, err
+		}
+	}
+
+	span.SetAttribute("job_id", signResp.Data["job_id"])
+
+	return &signResp, nil
+}
+
 // ============================================================================
 // Manifest Operations
 // ============================================================================
@@ -420,9 +719,17 @@ func (c *Client) SignFolder(ctx context.Context, folderPath string, options Sign
 type GetManifestOptions struct {
 	CachedETag *string
 	Format     *string
+	// IncludeAttestations asks the server to embed any in-toto attestation
+	// envelopes attached to this manifest (see Client.VerifyAttestation) in
+	// the response, under Data["attestations"].
+	IncludeAttestations bool
 }
 
-// GetManifest gets a manifest by content hash
+// GetManifest gets a manifest by content hash. If Config.ManifestCache is
+// set, a cached copy's ETag is sent automatically via If-None-Match -
+// callers no longer need to thread GetManifestOptions.CachedETag through
+// themselves - and a 304 response is served from the cache instead of
+// erroring on an empty body.
 func (c *Client) GetManifest(ctx context.Context, hash string, options GetManifestOptions) (*ManifestResponse, error) {
 	span := c.telemetry.CreateSpan("manifest.get", map[string]interface{}{
 		"hash":   hash[:16] + "...",
@@ -434,22 +741,67 @@ func (c *Client) GetManifest(ctx context.Context, hash string, options GetManife
 		return nil, NewValidationError("Invalid hash format. Must be a 64-character hexadecimal string.")
 	}
 
+	cachedETag := options.CachedETag
+	var cached *ManifestCacheEntry
+	if c.config.ManifestCache != nil {
+		entry, err := c.config.ManifestCache.Get(ctx, hash)
+		if err != nil {
+			return nil, fmt.Errorf("read manifest cache: %w", err)
+		}
+		if entry != nil {
+			cached = entry
+			if cachedETag == nil {
+				cachedETag = &entry.ETag
+			}
+		}
+	}
+
 	reqOptions := NewRequestOptions()
-	if options.CachedETag != nil {
-		reqOptions = reqOptions.WithHeader("If-None-Match", *options.CachedETag)
+	if cachedETag != nil && *cachedETag != "" {
+		reqOptions = reqOptions.WithHeader("If-None-Match", *cachedETag)
 	}
 
-	resp, err := c.transport.Request(ctx, "GET", "/manifests/"+hash, nil, reqOptions)
+	path := "/manifests/" + hash
+	if options.IncludeAttestations {
+		path += "?include_attestations=true"
+	}
+
+	resp, err := c.transport.Request(ctx, "GET", path, nil, reqOptions)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		if cached == nil {
+			return nil, fmt.Errorf("manifest %s: server returned 304 but no cached copy is available", hash)
+		}
+		span.SetAttribute("cached", true)
+		return &ManifestResponse{
+			Success: true,
+			Data: map[string]interface{}{
+				"content": base64.StdEncoding.EncodeToString(cached.Content),
+				"cached":  true,
+			},
+			Timestamp: time.Now(),
+		}, nil
+	}
+
 	var manifestResp ManifestResponse
 	if err := json.NewDecoder(resp.Body).Decode(&manifestResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	if c.config.ManifestCache != nil {
+		if contentStr, ok := manifestResp.Data["content"].(string); ok {
+			if content, err := base64.StdEncoding.DecodeString(contentStr); err == nil {
+				if err := c.config.ManifestCache.Put(ctx, hash, content, resp.Header.Get("ETag")); err != nil {
+					return nil, fmt.Errorf("write manifest cache: %w", err)
+				}
+			}
+		}
+	}
+
 	span.SetAttribute("cached", manifestResp.Data["cached"])
 
 	return &manifestResp, nil
@@ -457,8 +809,8 @@ func (c *Client) GetManifest(ctx context.Context, hash string, options GetManife
 
 // PutManifestOptions defines options for storing a manifest
 type PutManifestOptions struct {
-	ContentType     *string
-	Metadata        map[string]interface{}
+	ContentType    *string
+	Metadata       map[string]interface{}
 	IdempotencyKey *string
 }
 
@@ -509,11 +861,41 @@ func (c *Client) PutManifest(ctx context.Context, hash string, content []byte, o
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	if c.config.ManifestCache != nil {
+		if err := c.config.ManifestCache.Put(ctx, hash, content, resp.Header.Get("ETag")); err != nil {
+			return nil, fmt.Errorf("write manifest cache: %w", err)
+		}
+	}
+
 	span.SetAttribute("stored", true)
 
+	c.notifier.Notify(NotificationEvent{
+		Action:    NotificationActionManifestPut,
+		Target:    NotificationTarget{MediaType: contentType, Digest: hash},
+		Timestamp: time.Now(),
+		RequestID: manifestResp.RequestID,
+	})
+
 	return &manifestResp, nil
 }
 
+// PurgeManifestCache clears Config.ManifestCache, if one is configured.
+func (c *Client) PurgeManifestCache(ctx context.Context) error {
+	if c.config.ManifestCache == nil {
+		return nil
+	}
+	return c.config.ManifestCache.Purge(ctx)
+}
+
+// CacheStats reports Config.ManifestCache's current size, or a zero value
+// if no cache is configured.
+func (c *Client) CacheStats(ctx context.Context) (CacheStats, error) {
+	if c.config.ManifestCache == nil {
+		return CacheStats{}, nil
+	}
+	return c.config.ManifestCache.Stats(ctx)
+}
+
 // ============================================================================
 // Job Management
 // ============================================================================
@@ -550,6 +932,13 @@ func (c *Client) GetCircuitBreakerState() string {
 	return c.transport.GetCircuitBreakerState()
 }
 
+// GetCircuitBreakerSnapshots returns a point-in-time snapshot of every
+// endpoint breaker's state and rolling-window totals, for exporting through
+// Config.Telemetry to a Prometheus scrape handler.
+func (c *Client) GetCircuitBreakerSnapshots() []CircuitBreakerSnapshot {
+	return c.transport.GetCircuitBreakerSnapshots()
+}
+
 // IsTelemetryEnabled returns whether telemetry is enabled
 func (c *Client) IsTelemetryEnabled() bool {
 	return c.telemetry.IsEnabled()
@@ -557,7 +946,18 @@ func (c *Client) IsTelemetryEnabled() bool {
 
 // Close closes the client and cleans up resources
 func (c *Client) Close() {
+	c.telemetry.Close()
 	c.transport.Close()
+	c.notifier.Close()
+	c.profileManager.Stop()
+	c.authWatcher.Stop()
+}
+
+// NotifierStats returns each configured notification endpoint's current
+// delivery counters, keyed by URL. Returns nil if Config.Notifications was
+// empty.
+func (c *Client) NotifierStats() map[string]EndpointStats {
+	return c.notifier.Stats()
 }
 
 // ============================================================================
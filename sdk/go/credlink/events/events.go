@@ -0,0 +1,163 @@
+// Package events defines the versioned NDJSON event schema shared by every
+// long-running CLI/SDK operation (verify, sign, inject-link), and an Emitter
+// that renders those events either as human-formatted lines or as one JSON
+// object per line, so CI pipelines can parse progress deterministically
+// instead of scraping emoji-decorated text.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Type identifies the kind of event recorded in the stream.
+type Type string
+
+const (
+	TypeJobStart      Type = "job_start"
+	TypeJobProgress   Type = "job_progress"
+	TypeJobComplete   Type = "job_complete"
+	TypeAssetVerified Type = "asset_verified"
+	TypeLinkInjected  Type = "link_injected"
+	TypeError         Type = "error"
+)
+
+// SchemaVersion is bumped whenever a field is added to or removed from
+// Event, so downstream consumers can detect incompatible changes.
+const SchemaVersion = 1
+
+// Event is one line of the event stream. Fields that don't apply to a given
+// EventType are left zero and omitted from the NDJSON/JSON encoding.
+type Event struct {
+	SchemaVersion int       `json:"schema_version"`
+	EventType     Type      `json:"event_type"`
+	Timestamp     time.Time `json:"ts"`
+	JobID         string    `json:"job_id,omitempty"`
+	AssetURL      string    `json:"asset_url,omitempty"`
+	Verified      *bool     `json:"verified,omitempty"`
+	ManifestID    string    `json:"manifest_id,omitempty"`
+	Survival      *float64  `json:"survival,omitempty"`
+	ErrorCode     string    `json:"error_code,omitempty"`
+	ErrorHint     string    `json:"error_hint,omitempty"`
+	ProgressPct   *float64  `json:"progress_pct,omitempty"`
+
+	// Message is the line printed in human mode. It is never part of the
+	// NDJSON/JSON encoding; Human derives a default from the other fields
+	// when Message is empty.
+	Message string `json:"-"`
+}
+
+// Human returns the line to print in OutputHuman mode.
+func (e Event) Human() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	switch e.EventType {
+	case TypeAssetVerified:
+		if e.Verified != nil && *e.Verified {
+			return fmt.Sprintf("  verified: %s", e.AssetURL)
+		}
+		return fmt.Sprintf("  failed: %s (%s)", e.AssetURL, e.ErrorHint)
+	case TypeJobProgress:
+		if e.ProgressPct != nil {
+			return fmt.Sprintf("[%s] %.0f%%", e.JobID, *e.ProgressPct)
+		}
+		return fmt.Sprintf("[%s] in progress", e.JobID)
+	case TypeJobComplete:
+		return fmt.Sprintf("[%s] complete", e.JobID)
+	case TypeLinkInjected:
+		return fmt.Sprintf("  injected link: %s", e.AssetURL)
+	case TypeError:
+		return fmt.Sprintf("error [%s]: %s", e.ErrorCode, e.ErrorHint)
+	default:
+		return string(e.EventType)
+	}
+}
+
+// OutputMode selects how an Emitter renders events.
+type OutputMode string
+
+const (
+	OutputHuman         OutputMode = "human"
+	OutputNDJSON        OutputMode = "ndjson"
+	OutputJSON          OutputMode = "json"
+	OutputGitHubActions OutputMode = "github-actions"
+)
+
+// ParseOutputMode validates a --output flag value.
+func ParseOutputMode(s string) (OutputMode, error) {
+	switch OutputMode(s) {
+	case OutputHuman, OutputNDJSON, OutputJSON, OutputGitHubActions:
+		return OutputMode(s), nil
+	default:
+		return "", fmt.Errorf("invalid output mode %q (want human, ndjson, json, or github-actions)", s)
+	}
+}
+
+// Emitter routes Event values to either human-formatted lines on stderr, or
+// NDJSON/JSON objects on stdout.
+type Emitter struct {
+	mode   OutputMode
+	ndjson io.Writer
+	human  io.Writer
+}
+
+// NewEmitter builds an Emitter for mode, writing NDJSON/JSON to stdout and
+// human lines to stderr.
+func NewEmitter(mode OutputMode) *Emitter {
+	return &Emitter{mode: mode, ndjson: os.Stdout, human: os.Stderr}
+}
+
+// Emit stamps ev with the current time and schema version (if unset) and
+// renders it according to the Emitter's mode.
+func (e *Emitter) Emit(ev Event) {
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now().UTC()
+	}
+	if ev.SchemaVersion == 0 {
+		ev.SchemaVersion = SchemaVersion
+	}
+
+	switch e.mode {
+	case OutputNDJSON, OutputJSON:
+		_ = json.NewEncoder(e.ndjson).Encode(ev)
+	case OutputGitHubActions:
+		fmt.Fprintln(e.human, ev.WorkflowCommand())
+	default:
+		fmt.Fprintln(e.human, ev.Human())
+	}
+}
+
+// JobProgress builds a TypeJobProgress event.
+func JobProgress(jobID string, pct float64, message string) Event {
+	return Event{EventType: TypeJobProgress, JobID: jobID, ProgressPct: &pct, Message: message}
+}
+
+// JobComplete builds a TypeJobComplete event.
+func JobComplete(jobID, message string) Event {
+	return Event{EventType: TypeJobComplete, JobID: jobID, Message: message}
+}
+
+// AssetVerified builds a TypeAssetVerified event.
+func AssetVerified(assetURL string, verified bool, manifestID, errorHint string) Event {
+	return Event{
+		EventType:  TypeAssetVerified,
+		AssetURL:   assetURL,
+		Verified:   &verified,
+		ManifestID: manifestID,
+		ErrorHint:  errorHint,
+	}
+}
+
+// LinkInjected builds a TypeLinkInjected event.
+func LinkInjected(assetURL, message string) Event {
+	return Event{EventType: TypeLinkInjected, AssetURL: assetURL, Message: message}
+}
+
+// Error builds a TypeError event.
+func Error(code, hint string) Event {
+	return Event{EventType: TypeError, ErrorCode: code, ErrorHint: hint}
+}
@@ -0,0 +1,130 @@
+package events
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// workflowEscapeData escapes a workflow-command value per GitHub Actions'
+// grammar: %, CR, and LF must be percent-encoded or the runner misparses
+// the command boundary.
+func workflowEscapeData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	s = strings.ReplaceAll(s, "\n", "%0A")
+	return s
+}
+
+// workflowEscapeProperty escapes a workflow-command property value (e.g.
+// file=, line=), which additionally requires , and : to be encoded since
+// those delimit the property list itself.
+func workflowEscapeProperty(s string) string {
+	s = workflowEscapeData(s)
+	s = strings.ReplaceAll(s, ",", "%2C")
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return s
+}
+
+// WorkflowError renders a `::error::` annotation. title and file may be
+// empty; when both are set they're attached as file=/title= properties so
+// GitHub renders the annotation inline against the named file.
+func WorkflowError(title, file, message string) string {
+	return workflowAnnotation("error", title, file, message)
+}
+
+// WorkflowWarning renders a `::warning::` annotation.
+func WorkflowWarning(title, file, message string) string {
+	return workflowAnnotation("warning", title, file, message)
+}
+
+// WorkflowNotice renders a `::notice::` annotation.
+func WorkflowNotice(title, file, message string) string {
+	return workflowAnnotation("notice", title, file, message)
+}
+
+func workflowAnnotation(level, title, file, message string) string {
+	var props []string
+	if file != "" {
+		props = append(props, "file="+workflowEscapeProperty(file))
+	}
+	if title != "" {
+		props = append(props, "title="+workflowEscapeProperty(title))
+	}
+	if len(props) == 0 {
+		return fmt.Sprintf("::%s::%s", level, workflowEscapeData(message))
+	}
+	return fmt.Sprintf("::%s %s::%s", level, strings.Join(props, ","), workflowEscapeData(message))
+}
+
+// WorkflowGroup renders the `::group::` command that starts a collapsible
+// log section; pair with WorkflowEndGroup.
+func WorkflowGroup(title string) string {
+	return "::group::" + workflowEscapeData(title)
+}
+
+// WorkflowEndGroup renders the `::endgroup::` command closing the section
+// started by WorkflowGroup.
+func WorkflowEndGroup() string {
+	return "::endgroup::"
+}
+
+// WorkflowMask renders an `::add-mask::` command so Actions redacts every
+// later occurrence of value in the log, e.g. a signing key or API key read
+// for the run.
+func WorkflowMask(value string) string {
+	return "::add-mask::" + workflowEscapeData(value)
+}
+
+// WorkflowCommand renders ev as the GitHub Actions workflow command an
+// OutputGitHubActions Emitter prints: a failed asset_verified/error event
+// becomes an ::error:: annotation (so it surfaces as an inline PR
+// annotation), everything else becomes a ::notice:: line.
+func (e Event) WorkflowCommand() string {
+	switch e.EventType {
+	case TypeAssetVerified:
+		if e.Verified != nil && !*e.Verified {
+			return WorkflowError("Asset verification failed", e.AssetURL, e.ErrorHint)
+		}
+		return WorkflowNotice("", "", fmt.Sprintf("verified: %s", e.AssetURL))
+	case TypeError:
+		return WorkflowError(e.ErrorCode, "", e.ErrorHint)
+	default:
+		return WorkflowNotice("", "", e.Human())
+	}
+}
+
+// AppendStepSummary appends markdown to the step the run is currently
+// executing in, i.e. $GITHUB_STEP_SUMMARY, if set. It's a no-op outside
+// GitHub Actions (or if the env var isn't set) so callers can invoke it
+// unconditionally.
+//
+// The write uses a random UUID boundary the same way GITHUB_ENV/
+// GITHUB_OUTPUT multi-line values do, even though the step-summary file
+// itself has no delimiter grammar to violate - it's cheap insurance against
+// markdown content that happens to contain a line some other tooling reads
+// as a command terminator.
+func AppendStepSummary(markdown string) error {
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+
+	boundary := uuid.New().String()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("open GITHUB_STEP_SUMMARY: %w", err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "\n<!-- %s -->\n%s\n<!-- /%s -->\n", boundary, markdown, boundary)
+	return err
+}
+
+// InGitHubActions reports whether the process is running as a GitHub
+// Actions step, per the runner's own GITHUB_ACTIONS=true convention.
+func InGitHubActions() bool {
+	return os.Getenv("GITHUB_ACTIONS") == "true"
+}
@@ -0,0 +1,219 @@
+package credlink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// ============================================================================
+// Job Cancellation
+// ============================================================================
+
+// CancelJob requests cancellation of a running job. The server is expected to
+// stop further work and transition the job to the "cancelled" status; callers
+// should keep polling GetJobStatus (or use JobRunner, which does this for you)
+// until that transition is observed.
+func (c *Client) CancelJob(ctx context.Context, jobID string) (*JobStatus, error) {
+	span := c.telemetry.CreateSpan("job.cancel", map[string]interface{}{
+		"job_id": jobID,
+	})
+	defer span.End()
+
+	resp, err := c.transport.Request(ctx, "POST", "/jobs/"+jobID+"/cancel", nil, NewRequestOptions())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var jobStatus JobStatus
+	if err := json.NewDecoder(resp.Body).Decode(&jobStatus); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	span.SetAttribute("job_status", jobStatus.Status)
+	return &jobStatus, nil
+}
+
+// ============================================================================
+// JobRunner: progress rendering + graceful cancellation for long-running jobs
+// ============================================================================
+
+// ErrJobCanceled is returned by JobRunner.Run when the job was interrupted via
+// SIGINT/SIGTERM and a server-side cancel was requested, whether or not the
+// server confirmed it within the grace period.
+var ErrJobCanceled = fmt.Errorf("job canceled by user")
+
+// JobRunnerOptions controls how JobRunner polls and renders progress.
+type JobRunnerOptions struct {
+	// Silent suppresses all progress output (equivalent to --silent).
+	Silent bool
+	// NoProgress disables the progress bar but still prints status lines
+	// (equivalent to --no-progress).
+	NoProgress bool
+	// PollInterval controls how often GetJobStatus is polled. Defaults to 2s.
+	PollInterval time.Duration
+	// Out is where progress output is written. Defaults to os.Stderr.
+	Out io.Writer
+}
+
+// JobRunner wraps GetJobStatus polling with a progress bar, ETA, and a
+// SIGINT/SIGTERM handler that cancels the job cleanly instead of leaving the
+// client-side poll loop and the server-side job both running forever.
+type JobRunner struct {
+	client *Client
+	opts   JobRunnerOptions
+}
+
+// NewJobRunner creates a JobRunner bound to a client.
+func NewJobRunner(client *Client, opts JobRunnerOptions) *JobRunner {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 2 * time.Second
+	}
+	if opts.Out == nil {
+		opts.Out = os.Stderr
+	}
+	return &JobRunner{client: client, opts: opts}
+}
+
+// Run polls jobID until it reaches a terminal status, rendering progress to
+// opts.Out. A SIGINT/SIGTERM during the run triggers CancelJob and returns
+// ErrJobCanceled once the cancellation is confirmed (or the grace period
+// elapses). Callers embedding this in a Cobra command should translate
+// ErrJobCanceled into a distinct non-zero exit code.
+func (r *JobRunner) Run(ctx context.Context, jobID string) (*JobStatus, error) {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(r.opts.PollInterval)
+	defer ticker.Stop()
+
+	started := time.Now()
+	var canceling int32
+
+	for {
+		select {
+		case <-ctx.Done():
+			if atomic.CompareAndSwapInt32(&canceling, 0, 1) {
+				r.printf("\nReceived interrupt, canceling job %s...\n", jobID)
+				cancelCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				status, err := r.client.CancelJob(cancelCtx, jobID)
+				if err != nil {
+					r.printf("Failed to cancel job cleanly: %v\n", err)
+					return status, ErrJobCanceled
+				}
+				// Keep polling until the server reports a terminal status or
+				// the 10s grace period runs out.
+				for status == nil || (status.Status != "cancelled" && status.Status != "completed" && status.Status != "failed") {
+					select {
+					case <-cancelCtx.Done():
+						r.printf("Job %s cancellation not confirmed within the grace period\n", jobID)
+						return status, ErrJobCanceled
+					case <-time.After(r.opts.PollInterval):
+					}
+					if s, err := r.client.GetJobStatus(cancelCtx, jobID); err == nil {
+						status = s
+					}
+				}
+				r.printf("Job %s canceled\n", jobID)
+				return status, ErrJobCanceled
+			}
+			return nil, ctx.Err()
+
+		case <-ticker.C:
+			status, err := r.client.GetJobStatus(ctx, jobID)
+			if err != nil {
+				return nil, err
+			}
+
+			r.renderProgress(status, started)
+
+			switch status.Status {
+			case "completed", "failed", "cancelled":
+				return status, nil
+			}
+		}
+	}
+}
+
+func (r *JobRunner) renderProgress(status *JobStatus, started time.Time) {
+	if r.opts.Silent {
+		return
+	}
+
+	if r.opts.NoProgress {
+		r.printf("Status: %s\n", status.Status)
+		return
+	}
+
+	pct := 0.0
+	if status.Progress != nil {
+		pct = *status.Progress * 100
+	}
+
+	bar := renderBar(pct, 30)
+	eta := estimateETA(status, started)
+
+	counters := formatCounters(status.Result)
+
+	fmt.Fprintf(r.opts.Out, "\r[%s] %5.1f%% %s%s", bar, pct, counters, eta)
+	if status.Status == "completed" || status.Status == "failed" || status.Status == "cancelled" {
+		fmt.Fprintln(r.opts.Out)
+	}
+}
+
+func (r *JobRunner) printf(format string, args ...interface{}) {
+	if r.opts.Silent {
+		return
+	}
+	fmt.Fprintf(r.opts.Out, format, args...)
+}
+
+func renderBar(pct float64, width int) string {
+	if pct < 0 {
+		pct = 0
+	}
+	if pct > 100 {
+		pct = 100
+	}
+	filled := int(float64(width) * pct / 100)
+	return strings.Repeat("=", filled) + strings.Repeat(" ", width-filled)
+}
+
+func estimateETA(status *JobStatus, started time.Time) string {
+	if status.EstimatedCompletion != nil {
+		remaining := time.Until(*status.EstimatedCompletion)
+		if remaining > 0 {
+			return fmt.Sprintf(" ETA %s", remaining.Round(time.Second))
+		}
+	}
+	return ""
+}
+
+// formatCounters pulls the well-known progress counters out of a job's
+// result payload (bytes_signed, assets_verified, files_found) so the bar can
+// show task-specific units without the SDK needing a typed result schema.
+func formatCounters(result map[string]interface{}) string {
+	if result == nil {
+		return ""
+	}
+
+	var parts []string
+	for _, key := range []string{"bytes_signed", "assets_verified", "files_found"} {
+		if v, ok := result[key]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%v", key, v))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, " ") + " "
+}
@@ -0,0 +1,165 @@
+package credlink
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/credlink/sdk/go/credlink/oidcflow"
+)
+
+// keylessMaterial is the ephemeral key, OIDC proof, and short-lived
+// certificate chain a keyless signing request attaches to SignFolder or
+// SignAsset.
+type keylessMaterial struct {
+	privateKey *ecdsa.PrivateKey
+	idToken    string
+	certChain  []string
+}
+
+// obtainKeylessCertificate runs the keyless signing flow described in
+// KeylessOptions: authenticate via OIDC to get an ID token, generate an
+// ephemeral P-256 key, embed the token's subject in a CSR, and exchange both
+// at the Fulcio-like /keyless/certificate endpoint for a short-lived
+// certificate chain.
+func (c *Client) obtainKeylessCertificate(ctx context.Context, opts KeylessOptions) (*keylessMaterial, error) {
+	getter := oidcflow.NewTokenGetter(oidcflow.Config{
+		Issuer:       opts.OIDCIssuer,
+		ClientID:     opts.OIDCClientID,
+		ClientSecret: opts.OIDCClientSecret,
+		RedirectURL:  opts.OIDCRedirectURL,
+	})
+
+	idToken, err := getter.GetIDToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("keyless: obtain OIDC ID token: %w", err)
+	}
+
+	identity := opts.Identity
+	if identity == "" {
+		identity, err = jwtSubject(idToken)
+		if err != nil {
+			return nil, fmt.Errorf("keyless: determine identity: %w", err)
+		}
+	}
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("keyless: generate ephemeral key: %w", err)
+	}
+
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:            pkix.Name{CommonName: identity},
+		SignatureAlgorithm: x509.ECDSAWithSHA256,
+	}, priv)
+	if err != nil {
+		return nil, fmt.Errorf("keyless: create CSR: %w", err)
+	}
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+	request := keylessCertificateRequest{CSR: string(csrPEM), IDToken: idToken}
+
+	resp, err := c.transport.Request(ctx, "POST", "/keyless/certificate", request, NewRequestOptions())
+	if err != nil {
+		return nil, fmt.Errorf("keyless: request certificate: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var certResp keylessCertificateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&certResp); err != nil {
+		return nil, fmt.Errorf("keyless: decode certificate response: %w", err)
+	}
+	if len(certResp.CertificateChain) == 0 {
+		return nil, fmt.Errorf("keyless: certificate endpoint returned an empty chain")
+	}
+
+	return &keylessMaterial{privateKey: priv, idToken: idToken, certChain: certResp.CertificateChain}, nil
+}
+
+// publicKeyPEM PEM-encodes the ephemeral key's public half, sent alongside
+// the certificate chain so the server can confirm the signature it receives
+// matches the certificate it issued.
+func (m *keylessMaterial) publicKeyPEM() (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(&m.privateKey.PublicKey)
+	if err != nil {
+		return "", fmt.Errorf("keyless: marshal public key: %w", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
+
+// signDigest signs a manifest digest with the ephemeral key, the "sign C2PA
+// manifests locally" step of the keyless flow - the private key never
+// leaves the process, so only its signature and the Fulcio-issued
+// certificate travel over the wire.
+func (m *keylessMaterial) signDigest(digest []byte) (string, error) {
+	sig, err := ecdsa.SignASN1(rand.Reader, m.privateKey, digest)
+	if err != nil {
+		return "", fmt.Errorf("keyless: sign digest: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// signPayload signs an arbitrary payload (e.g. a DSSE attestation envelope
+// payload) with the ephemeral key, first reducing it to a digest the same
+// way signDigest expects a manifest digest.
+func (m *keylessMaterial) signPayload(payload []byte) (string, error) {
+	sum := sha256.Sum256(payload)
+	return m.signDigest(sum[:])
+}
+
+// submitTLogEntry logs a keyless-signed bundle's certificate chain and
+// manifest digest to a Rekor-style transparency log, returning the server's
+// response Data (log index, inclusion proof) to merge into the caller's
+// SignFolderResponse (SignAsset returns the same type).
+func (c *Client) submitTLogEntry(ctx context.Context, m *keylessMaterial, manifestDigestHex, signature string) (map[string]interface{}, error) {
+	request := TLogEntryRequest{
+		CertificateChain: m.certChain,
+		ManifestDigest:   manifestDigestHex,
+		Signature:        signature,
+	}
+
+	resp, err := c.transport.Request(ctx, "POST", "/tlog/entries", request, NewRequestOptions())
+	if err != nil {
+		return nil, fmt.Errorf("keyless: submit transparency log entry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var tlogResp TLogEntryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tlogResp); err != nil {
+		return nil, fmt.Errorf("keyless: decode transparency log response: %w", err)
+	}
+	return tlogResp.Data, nil
+}
+
+// jwtSubject extracts the "sub" claim from an unverified JWT's payload; the
+// Fulcio-like endpoint re-verifies the token's signature server-side, so
+// this is only used to pick a human-readable default certificate subject.
+func jwtSubject(idToken string) (string, error) {
+	parts := strings.Split(idToken, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("malformed JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("decode JWT payload: %w", err)
+	}
+	var claims struct {
+		Subject string `json:"sub"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("decode JWT claims: %w", err)
+	}
+	if claims.Subject == "" {
+		return "", fmt.Errorf("JWT has no sub claim")
+	}
+	return claims.Subject, nil
+}
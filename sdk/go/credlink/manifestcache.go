@@ -0,0 +1,295 @@
+package credlink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ManifestCache lets Client.GetManifest/Client.PutManifest consult and
+// populate a local (or remote) store instead of always round-tripping to
+// the API. Implement this to back the cache with Redis, S3, or anything
+// else - FSManifestCache is the default, filesystem-backed implementation.
+type ManifestCache interface {
+	// Get returns the cached entry for hash, or (nil, nil) on a cache miss.
+	Get(ctx context.Context, hash string) (*ManifestCacheEntry, error)
+	// Put stores content under hash along with the ETag the server
+	// returned for it, overwriting any existing entry.
+	Put(ctx context.Context, hash string, content []byte, etag string) error
+	// Purge removes every cached entry.
+	Purge(ctx context.Context) error
+	// Stats reports the cache's current size.
+	Stats(ctx context.Context) (CacheStats, error)
+}
+
+// ManifestCacheEntry is a cached manifest's decompressed content plus the
+// ETag it was stored under, used to populate If-None-Match on the next
+// GetManifest call.
+type ManifestCacheEntry struct {
+	Content []byte
+	ETag    string
+}
+
+// CacheStats summarizes a ManifestCache's current contents.
+type CacheStats struct {
+	Entries int
+	Bytes   int64
+}
+
+// manifestCacheMeta is the sidecar metadata FSManifestCache writes next to
+// each cached manifest, for diagnostics rather than correctness - nothing
+// reads it back today.
+type manifestCacheMeta struct {
+	StoredAt    time.Time `json:"stored_at"`
+	RawBytes    int64     `json:"raw_bytes"`
+	StoredBytes int64     `json:"stored_bytes"`
+}
+
+// Manifest cache codecs. The first byte of manifest.c2pa names the codec
+// the rest of the file was written with, so a future codec can be added
+// without invalidating entries written by an older client.
+const (
+	manifestCacheCodecNone byte = 0
+	manifestCacheCodecGzip byte = 1
+)
+
+// FSManifestCache is the default ManifestCache: a sha256 fan-out directory
+// layout under Root (<root>/sha256/ab/cd/<hash>/manifest.c2pa, .etag,
+// .meta.json), gzip-compressed on disk since C2PA JUMBF manifests compress
+// well. MaxBytes, when positive, bounds total on-disk size via LRU
+// eviction keyed by each entry's last-read/last-written time.
+type FSManifestCache struct {
+	Root     string
+	MaxBytes int64
+}
+
+// NewFSManifestCache creates (if needed) root and returns a ready-to-use
+// FSManifestCache. maxBytes <= 0 disables eviction.
+func NewFSManifestCache(root string, maxBytes int64) (*FSManifestCache, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("create manifest cache root %s: %w", root, err)
+	}
+	return &FSManifestCache{Root: root, MaxBytes: maxBytes}, nil
+}
+
+func (f *FSManifestCache) entryDir(hash string) string {
+	return filepath.Join(f.Root, "sha256", hash[:2], hash[2:4], hash)
+}
+
+// Get implements ManifestCache.
+func (f *FSManifestCache) Get(ctx context.Context, hash string) (*ManifestCacheEntry, error) {
+	dir := f.entryDir(hash)
+	manifestPath := filepath.Join(dir, "manifest.c2pa")
+
+	raw, err := os.ReadFile(manifestPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cached manifest %s: %w", hash, err)
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("cached manifest %s is empty", hash)
+	}
+
+	content, err := decodeManifestCacheCodec(raw[0], raw[1:])
+	if err != nil {
+		return nil, fmt.Errorf("decode cached manifest %s: %w", hash, err)
+	}
+
+	etag, err := os.ReadFile(filepath.Join(dir, ".etag"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read cached etag for %s: %w", hash, err)
+	}
+
+	// Bump mtime so LRU eviction treats a read like a touch, not just writes.
+	now := time.Now()
+	_ = os.Chtimes(manifestPath, now, now)
+
+	return &ManifestCacheEntry{Content: content, ETag: string(etag)}, nil
+}
+
+// Put implements ManifestCache.
+func (f *FSManifestCache) Put(ctx context.Context, hash string, content []byte, etag string) error {
+	dir := f.entryDir(hash)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create manifest cache dir for %s: %w", hash, err)
+	}
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	if _, err := gz.Write(content); err != nil {
+		return fmt.Errorf("compress manifest %s: %w", hash, err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("compress manifest %s: %w", hash, err)
+	}
+
+	payload := make([]byte, 0, compressed.Len()+1)
+	payload = append(payload, manifestCacheCodecGzip)
+	payload = append(payload, compressed.Bytes()...)
+
+	if err := atomicWriteFile(filepath.Join(dir, "manifest.c2pa"), payload); err != nil {
+		return fmt.Errorf("write cached manifest %s: %w", hash, err)
+	}
+	if err := atomicWriteFile(filepath.Join(dir, ".etag"), []byte(etag)); err != nil {
+		return fmt.Errorf("write cached etag for %s: %w", hash, err)
+	}
+
+	meta, err := json.Marshal(manifestCacheMeta{
+		StoredAt:    time.Now(),
+		RawBytes:    int64(len(content)),
+		StoredBytes: int64(len(payload)),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal manifest cache metadata for %s: %w", hash, err)
+	}
+	if err := atomicWriteFile(filepath.Join(dir, ".meta.json"), meta); err != nil {
+		return fmt.Errorf("write cache metadata for %s: %w", hash, err)
+	}
+
+	if f.MaxBytes > 0 {
+		if err := f.evictLRU(); err != nil {
+			return fmt.Errorf("evict manifest cache entries: %w", err)
+		}
+	}
+	return nil
+}
+
+// Purge implements ManifestCache.
+func (f *FSManifestCache) Purge(ctx context.Context) error {
+	shaRoot := filepath.Join(f.Root, "sha256")
+	if err := os.RemoveAll(shaRoot); err != nil {
+		return fmt.Errorf("purge manifest cache: %w", err)
+	}
+	return nil
+}
+
+// Stats implements ManifestCache.
+func (f *FSManifestCache) Stats(ctx context.Context) (CacheStats, error) {
+	var stats CacheStats
+	err := filepath.WalkDir(f.Root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() || d.Name() != "manifest.c2pa" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		stats.Entries++
+		stats.Bytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return CacheStats{}, nil
+		}
+		return CacheStats{}, fmt.Errorf("compute manifest cache stats: %w", err)
+	}
+	return stats, nil
+}
+
+// evictLRU removes whole entries, oldest manifest.c2pa mtime first, until
+// total cached bytes are back under MaxBytes.
+func (f *FSManifestCache) evictLRU() error {
+	type cachedEntry struct {
+		dir   string
+		size  int64
+		mtime time.Time
+	}
+
+	var entries []cachedEntry
+	var total int64
+
+	err := filepath.WalkDir(f.Root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() || d.Name() != "manifest.c2pa" {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, cachedEntry{dir: filepath.Dir(path), size: info.Size(), mtime: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if total <= f.MaxBytes {
+		return nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].mtime.Before(entries[j].mtime) })
+
+	for _, e := range entries {
+		if total <= f.MaxBytes {
+			break
+		}
+		if err := os.RemoveAll(e.dir); err != nil {
+			return err
+		}
+		total -= e.size
+	}
+	return nil
+}
+
+// decodeManifestCacheCodec decompresses data according to the codec byte
+// read from the front of a cached manifest.c2pa file.
+func decodeManifestCacheCodec(codec byte, data []byte) ([]byte, error) {
+	switch codec {
+	case manifestCacheCodecNone:
+		return data, nil
+	case manifestCacheCodecGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("open gzip reader: %w", err)
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	default:
+		return nil, fmt.Errorf("unknown manifest cache codec %d", codec)
+	}
+}
+
+// atomicWriteFile writes data to a temp file in dir's directory, then
+// renames it into place, so a crash or concurrent reader never observes a
+// partially-written cache file.
+func atomicWriteFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("close temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("rename temp file into place: %w", err)
+	}
+	return nil
+}
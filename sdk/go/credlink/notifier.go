@@ -0,0 +1,282 @@
+package credlink
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Notification actions, modeled on Docker Distribution's registry/notifications
+// package: each corresponds to one of the endpoint methods below that can
+// produce a provenance decision worth forwarding to a SIEM/audit pipeline.
+const (
+	NotificationActionVerify               = "verify"
+	NotificationActionSign                 = "sign"
+	NotificationActionInject               = "inject"
+	NotificationActionManifestPut          = "manifest.put"
+	NotificationActionProfileRenewed       = "profile.renewed"
+	NotificationActionProfileRenewalFailed = "profile.renewal_failed"
+)
+
+// NotificationTarget identifies what a NotificationEvent is about.
+type NotificationTarget struct {
+	MediaType string `json:"media_type,omitempty"`
+	Digest    string `json:"digest,omitempty"`
+	URL       string `json:"url,omitempty"`
+	// ProfileID identifies the signing profile a profile.renewed /
+	// profile.renewal_failed event is about.
+	ProfileID string `json:"profile_id,omitempty"`
+}
+
+// NotificationResult carries the provenance decision a NotificationEvent
+// reports.
+type NotificationResult struct {
+	Verified bool   `json:"verified"`
+	PolicyID string `json:"policy_id,omitempty"`
+}
+
+// NotificationEvent is the JSON body POSTed to each configured notification
+// endpoint.
+type NotificationEvent struct {
+	Action    string             `json:"action"`
+	Target    NotificationTarget `json:"target"`
+	Result    NotificationResult `json:"result,omitempty"`
+	Actor     string             `json:"actor,omitempty"`
+	Timestamp time.Time          `json:"timestamp"`
+	RequestID string             `json:"request_id,omitempty"`
+}
+
+// EndpointIgnoreConfig filters events out of an endpoint's queue before
+// delivery is even attempted.
+type EndpointIgnoreConfig struct {
+	MediaTypes []string `json:"media_types,omitempty"`
+	Actions    []string `json:"actions,omitempty"`
+}
+
+// EndpointConfig configures one notification endpoint, modeled on Docker
+// Distribution's notifications.Endpoint: a URL to POST NotificationEvents
+// to, with its own timeout, retry backoff, and unhealthy-after threshold.
+type EndpointConfig struct {
+	URL     string        `json:"url"`
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// Threshold is the number of consecutive delivery failures after which
+	// the endpoint is marked unhealthy and further events are dropped
+	// rather than retried.
+	Threshold int                  `json:"threshold,omitempty"`
+	Backoff   time.Duration        `json:"backoff,omitempty"`
+	Headers   map[string]string    `json:"headers,omitempty"`
+	Ignore    EndpointIgnoreConfig `json:"ignore,omitempty"`
+	// IncludeReferences is reserved for callers that want full manifest
+	// references attached to each event instead of just target identifiers.
+	IncludeReferences bool `json:"include_references,omitempty"`
+}
+
+// EndpointStats reports one endpoint's delivery counters.
+type EndpointStats struct {
+	Pending   int
+	Delivered int64
+	Failed    int64
+	Healthy   bool
+}
+
+// Notifier fans VerifyAsset/BatchVerify/VerifyPage/SignFolder events out to
+// every configured EndpointConfig, queuing and retrying deliveries
+// independently per endpoint so a slow or unhealthy endpoint never blocks
+// the call that triggered the event.
+type Notifier struct {
+	endpoints []*notifyEndpoint
+}
+
+// NewNotifier builds a Notifier from configs and starts one delivery
+// goroutine per endpoint.
+func NewNotifier(configs []EndpointConfig) *Notifier {
+	n := &Notifier{endpoints: make([]*notifyEndpoint, 0, len(configs))}
+	for _, cfg := range configs {
+		n.endpoints = append(n.endpoints, newNotifyEndpoint(cfg))
+	}
+	return n
+}
+
+// Notify enqueues ev on every endpoint whose Ignore filters don't match it.
+// A nil Notifier is a no-op, so callers don't need to guard every call site
+// on whether notifications are configured.
+func (n *Notifier) Notify(ev NotificationEvent) {
+	if n == nil {
+		return
+	}
+	for _, ep := range n.endpoints {
+		ep.enqueue(ev)
+	}
+}
+
+// Stats returns each endpoint's current counters, keyed by URL. A nil
+// Notifier returns nil.
+func (n *Notifier) Stats() map[string]EndpointStats {
+	if n == nil {
+		return nil
+	}
+	stats := make(map[string]EndpointStats, len(n.endpoints))
+	for _, ep := range n.endpoints {
+		stats[ep.config.URL] = ep.stats()
+	}
+	return stats
+}
+
+// Close stops accepting new events and waits for in-flight deliveries to
+// finish. A nil Notifier is a no-op.
+func (n *Notifier) Close() {
+	if n == nil {
+		return
+	}
+	for _, ep := range n.endpoints {
+		close(ep.queue)
+	}
+	for _, ep := range n.endpoints {
+		ep.wg.Wait()
+	}
+}
+
+// notifyEndpoint is one EndpointConfig's delivery queue and counters.
+type notifyEndpoint struct {
+	config EndpointConfig
+	client *http.Client
+	queue  chan NotificationEvent
+	wg     sync.WaitGroup
+
+	mu                  sync.Mutex
+	delivered           int64
+	failed              int64
+	consecutiveFailures int
+	healthy             bool
+}
+
+func newNotifyEndpoint(cfg EndpointConfig) *notifyEndpoint {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 5
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = time.Second
+	}
+
+	ep := &notifyEndpoint{
+		config:  cfg,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		queue:   make(chan NotificationEvent, 256),
+		healthy: true,
+	}
+
+	ep.wg.Add(1)
+	go ep.run()
+
+	return ep
+}
+
+func (ep *notifyEndpoint) enqueue(ev NotificationEvent) {
+	if ep.ignored(ev) {
+		return
+	}
+	select {
+	case ep.queue <- ev:
+	default:
+		// Queue is full; count it as a failed delivery rather than block
+		// the caller that triggered the event.
+		ep.mu.Lock()
+		ep.failed++
+		ep.mu.Unlock()
+	}
+}
+
+func (ep *notifyEndpoint) ignored(ev NotificationEvent) bool {
+	for _, action := range ep.config.Ignore.Actions {
+		if action == ev.Action {
+			return true
+		}
+	}
+	for _, mediaType := range ep.config.Ignore.MediaTypes {
+		if mediaType == ev.Target.MediaType {
+			return true
+		}
+	}
+	return false
+}
+
+func (ep *notifyEndpoint) run() {
+	defer ep.wg.Done()
+	for ev := range ep.queue {
+		ep.deliver(ev)
+	}
+}
+
+// deliver retries with exponential backoff until it succeeds or
+// Threshold consecutive failures have accumulated, at which point the
+// endpoint is marked unhealthy and the event is dropped.
+func (ep *notifyEndpoint) deliver(ev NotificationEvent) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		ep.mu.Lock()
+		ep.failed++
+		ep.mu.Unlock()
+		return
+	}
+
+	for attempt := 1; ; attempt++ {
+		if ep.attemptDelivery(body) {
+			ep.mu.Lock()
+			ep.delivered++
+			ep.consecutiveFailures = 0
+			ep.healthy = true
+			ep.mu.Unlock()
+			return
+		}
+
+		ep.mu.Lock()
+		ep.consecutiveFailures++
+		exhausted := ep.consecutiveFailures >= ep.config.Threshold
+		if exhausted {
+			ep.healthy = false
+			ep.failed++
+		}
+		ep.mu.Unlock()
+
+		if exhausted {
+			return
+		}
+
+		time.Sleep(ep.config.Backoff * time.Duration(uint(1)<<uint(attempt-1)))
+	}
+}
+
+func (ep *notifyEndpoint) attemptDelivery(body []byte) bool {
+	req, err := http.NewRequest(http.MethodPost, ep.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range ep.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := ep.client.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+func (ep *notifyEndpoint) stats() EndpointStats {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	return EndpointStats{
+		Pending:   len(ep.queue),
+		Delivered: ep.delivered,
+		Failed:    ep.failed,
+		Healthy:   ep.healthy,
+	}
+}
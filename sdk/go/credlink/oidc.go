@@ -0,0 +1,347 @@
+package credlink
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// OIDC / Workload Identity Authentication
+// ============================================================================
+
+// OIDCConfig configures OIDC or workload-identity authentication as an
+// alternative to a static APIKey, for CI jobs and cluster workloads that
+// shouldn't hold a long-lived credential. Set either WorkloadIdentityTokenFile
+// (file-based, non-interactive) or Issuer/ClientID/RedirectURL (interactive
+// auth-code + PKCE) - not both.
+type OIDCConfig struct {
+	// Issuer is the OIDC issuer's base URL, used to discover its
+	// authorization and token endpoints via Issuer +
+	// "/.well-known/openid-configuration".
+	Issuer string `json:"issuer,omitempty"`
+	// ClientID and ClientSecret identify this client to Issuer. ClientSecret
+	// is optional for a public (PKCE-only) client.
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	// RedirectURL is the loopback callback URL NewOIDCAuthCodeProvider
+	// listens on, e.g. "http://localhost:8484/callback".
+	RedirectURL string `json:"redirect_url,omitempty"`
+	// WorkloadIdentityTokenFile, set instead of the fields above, names a
+	// file containing a JWT that's rotated out-of-band - e.g. a
+	// Kubernetes-projected service account token, or a GCP/AWS workload
+	// identity federation token - so Validate builds a file-based provider
+	// rather than running the interactive flow.
+	WorkloadIdentityTokenFile string `json:"workload_identity_token_file,omitempty"`
+}
+
+// newOIDCAuthProvider builds the AuthProvider cfg describes: a file-based
+// provider around WorkloadIdentityTokenFile if set, or the interactive
+// auth-code + PKCE flow otherwise.
+func newOIDCAuthProvider(cfg *OIDCConfig) (AuthProvider, error) {
+	if cfg.WorkloadIdentityTokenFile != "" {
+		return NewWorkloadIdentityAuthProvider(cfg.WorkloadIdentityTokenFile), nil
+	}
+	if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return nil, fmt.Errorf("oidc: Issuer, ClientID, and RedirectURL are required for the interactive auth-code flow")
+	}
+	return NewOIDCAuthCodeProvider(cfg), nil
+}
+
+// ============================================================================
+// Workload Identity (file-based)
+// ============================================================================
+
+// NewWorkloadIdentityAuthProvider returns a RefreshableAuthProvider that
+// reads the JWT at tokenFile, re-reading it once the previously read token
+// nears its own exp claim rather than on every call. The file itself is
+// rewritten out-of-band - by the kubelet, or a GCP/AWS credential helper -
+// well ahead of expiry, so there's no need to watch it more closely than
+// that.
+func NewWorkloadIdentityAuthProvider(tokenFile string) *RefreshableAuthProvider {
+	provider := NewRefreshableAuthProvider(func(ctx context.Context) (string, time.Time, error) {
+		return readWorkloadIdentityToken(tokenFile)
+	}, ErrorOnFailure)
+	provider.Mode = "workload_identity"
+	return provider
+}
+
+func readWorkloadIdentityToken(path string) (string, time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("read workload identity token file %q: %w", path, err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	expiresAt, err := jwtExpiry(token)
+	if err != nil {
+		// A token we can't parse the exp claim from is still usable as a
+		// credential - verification is the issuer's job, not ours - so
+		// treat it as never-expiring for caching purposes rather than
+		// failing outright.
+		return token, time.Time{}, nil
+	}
+	return token, expiresAt, nil
+}
+
+// jwtExpiry extracts the exp claim from an unverified JWT. It's only used
+// to decide when RefreshableAuthProvider should re-read a rotated token
+// file, never to authenticate the token itself.
+func jwtExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("jwt: expected 3 dot-separated parts, got %d", len(parts))
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("jwt: decode payload: %w", err)
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("jwt: decode claims: %w", err)
+	}
+	if claims.Exp == 0 {
+		return time.Time{}, fmt.Errorf("jwt: no exp claim")
+	}
+	return time.Unix(claims.Exp, 0), nil
+}
+
+// ============================================================================
+// Interactive Auth-Code + PKCE Flow
+// ============================================================================
+
+// oidcDiscoveryDocument is the subset of a provider's
+// /.well-known/openid-configuration this flow needs.
+type oidcDiscoveryDocument struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+}
+
+func discoverOIDC(ctx context.Context, issuer string) (*oidcDiscoveryDocument, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: discover %s: %w", issuer, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc: discovery at %s returned HTTP %d", issuer, resp.StatusCode)
+	}
+
+	var doc oidcDiscoveryDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("oidc: decode discovery document: %w", err)
+	}
+	return &doc, nil
+}
+
+// OIDCAuthCodeSource performs the OIDC authorization-code + PKCE flow for
+// interactive use (e.g. a developer running the CredLink CLI), then
+// exchanges the resulting refresh token for a new access token as it
+// expires. Use NewOIDCAuthCodeProvider to wrap one in a RefreshableAuthProvider
+// rather than constructing it directly.
+type OIDCAuthCodeSource struct {
+	Config *OIDCConfig
+	// OnAuthorizationURL is called with the URL the user should open in a
+	// browser to complete sign-in. Defaults to printing it to stderr.
+	OnAuthorizationURL func(authURL string)
+
+	refreshToken string
+}
+
+// NewOIDCAuthCodeProvider wraps an OIDCAuthCodeSource for cfg in a
+// RefreshableAuthProvider, tagged so AuthError.NextSteps can tell an OIDC
+// failure apart from a stale API key.
+func NewOIDCAuthCodeProvider(cfg *OIDCConfig) *RefreshableAuthProvider {
+	source := &OIDCAuthCodeSource{Config: cfg}
+	provider := NewRefreshableAuthProvider(source.refresh, ErrorOnFailure)
+	provider.Mode = "oidc"
+	return provider
+}
+
+// refresh runs the full PKCE login on first call, then the refresh_token
+// grant on every call after - both produce the (token, expiresAt) pair
+// RefreshableAuthProvider expects.
+func (s *OIDCAuthCodeSource) refresh(ctx context.Context) (string, time.Time, error) {
+	doc, err := discoverOIDC(ctx, s.Config.Issuer)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if s.refreshToken == "" {
+		return s.login(ctx, doc)
+	}
+
+	token, expiresAt, refreshToken, err := s.exchangeToken(ctx, doc.TokenEndpoint, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {s.refreshToken},
+		"client_id":     {s.Config.ClientID},
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if refreshToken != "" {
+		s.refreshToken = refreshToken
+	}
+	return token, expiresAt, nil
+}
+
+// login runs the interactive authorization-code + PKCE flow: it builds the
+// authorization URL, hands it to OnAuthorizationURL, listens on
+// Config.RedirectURL for the callback, and exchanges the resulting code for
+// a token.
+func (s *OIDCAuthCodeSource) login(ctx context.Context, doc *oidcDiscoveryDocument) (string, time.Time, error) {
+	verifier, challenge, err := newPKCEPair()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	state, err := randomOIDCString(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	redirect, err := url.Parse(s.Config.RedirectURL)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("oidc: parse redirect URL: %w", err)
+	}
+
+	authURL, err := url.Parse(doc.AuthorizationEndpoint)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("oidc: parse authorization endpoint: %w", err)
+	}
+	q := authURL.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", s.Config.ClientID)
+	q.Set("redirect_uri", s.Config.RedirectURL)
+	q.Set("scope", "openid offline_access")
+	q.Set("state", state)
+	q.Set("code_challenge", challenge)
+	q.Set("code_challenge_method", "S256")
+	authURL.RawQuery = q.Encode()
+
+	codeCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(redirect.Path, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != state {
+			errCh <- fmt.Errorf("oidc: callback state mismatch")
+			http.Error(w, "state mismatch", http.StatusBadRequest)
+			return
+		}
+		if errMsg := r.URL.Query().Get("error"); errMsg != "" {
+			errCh <- fmt.Errorf("oidc: authorization denied: %s", errMsg)
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, "Sign-in complete, you may close this tab.")
+		codeCh <- r.URL.Query().Get("code")
+	})
+
+	server := &http.Server{Addr: redirect.Host, Handler: mux}
+	go server.ListenAndServe()
+	defer server.Close()
+
+	onURL := s.OnAuthorizationURL
+	if onURL == nil {
+		onURL = func(u string) {
+			fmt.Fprintf(os.Stderr, "Open this URL to sign in: %s\n", u)
+		}
+	}
+	onURL(authURL.String())
+
+	var code string
+	select {
+	case <-ctx.Done():
+		return "", time.Time{}, ctx.Err()
+	case err := <-errCh:
+		return "", time.Time{}, err
+	case code = <-codeCh:
+	}
+
+	token, expiresAt, refreshToken, err := s.exchangeToken(ctx, doc.TokenEndpoint, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {s.Config.RedirectURL},
+		"client_id":     {s.Config.ClientID},
+		"code_verifier": {verifier},
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	s.refreshToken = refreshToken
+	return token, expiresAt, nil
+}
+
+// exchangeToken posts form to tokenEndpoint and returns the access token,
+// its computed expiry, and any refresh token in the response.
+func (s *OIDCAuthCodeSource) exchangeToken(ctx context.Context, tokenEndpoint string, form url.Values) (token string, expiresAt time.Time, refreshToken string, err error) {
+	if s.Config.ClientSecret != "" {
+		form.Set("client_secret", s.Config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", time.Time{}, "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, "", fmt.Errorf("oidc: exchange token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, "", fmt.Errorf("oidc: token endpoint returned HTTP %d", resp.StatusCode)
+	}
+
+	var result struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", time.Time{}, "", fmt.Errorf("oidc: decode token response: %w", err)
+	}
+	if result.AccessToken == "" {
+		return "", time.Time{}, "", fmt.Errorf("oidc: token response had no access_token")
+	}
+
+	if result.ExpiresIn > 0 {
+		expiresAt = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	}
+	return result.AccessToken, expiresAt, result.RefreshToken, nil
+}
+
+func randomOIDCString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// newPKCEPair returns a random code_verifier and its S256 code_challenge,
+// per RFC 7636.
+func newPKCEPair() (verifier, challenge string, err error) {
+	verifier, err = randomOIDCString(32)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
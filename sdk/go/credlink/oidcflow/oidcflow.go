@@ -0,0 +1,379 @@
+// Package oidcflow abstracts how a caller obtains an OIDC ID token for
+// keyless signing, the way cosign's oauthflow.TokenGetter lets a CI job
+// supply a token non-interactively while a developer's laptop falls back to
+// a browser or device-code prompt.
+package oidcflow
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// TokenGetter obtains an OIDC ID token suitable for exchange at a
+// Fulcio-like certificate endpoint.
+type TokenGetter interface {
+	GetIDToken(ctx context.Context) (string, error)
+}
+
+// Config names the OIDC issuer and client used to obtain a token.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL, when set, selects the browser-based authorization-code
+	// flow over the device-code flow.
+	RedirectURL string
+}
+
+// NewTokenGetter picks a TokenGetter for cfg: a GitHub Actions job's OIDC
+// token when running in that environment (GITHUB_OIDC_TOKEN or the
+// ACTIONS_ID_TOKEN_REQUEST_* variables), the browser authorization-code flow
+// when cfg.RedirectURL is set, and the device-code flow otherwise - the
+// right default for a headless CI runner without a callback listener.
+func NewTokenGetter(cfg Config) TokenGetter {
+	if os.Getenv("GITHUB_OIDC_TOKEN") != "" || os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL") != "" {
+		return &GitHubActionsTokenGetter{Audience: cfg.ClientID}
+	}
+	if cfg.RedirectURL != "" {
+		return &BrowserTokenGetter{Config: cfg}
+	}
+	return &DeviceCodeTokenGetter{Config: cfg}
+}
+
+// discoveryDocument is the subset of a provider's
+// /.well-known/openid-configuration this package needs.
+type discoveryDocument struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	DeviceAuthEndpoint    string `json:"device_authorization_endpoint"`
+}
+
+func discover(ctx context.Context, issuer string) (*discoveryDocument, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oidcflow: discover %s: %w", issuer, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidcflow: discovery at %s returned HTTP %d", issuer, resp.StatusCode)
+	}
+	var doc discoveryDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("oidcflow: decode discovery document: %w", err)
+	}
+	return &doc, nil
+}
+
+// GitHubActionsTokenGetter fetches the job's OIDC ID token from the
+// Actions runtime, so a CI workflow never needs interactive auth. It honors
+// GITHUB_OIDC_TOKEN as a pre-fetched static token, then falls back to
+// requesting one from ACTIONS_ID_TOKEN_REQUEST_URL.
+type GitHubActionsTokenGetter struct {
+	Audience string
+}
+
+func (g *GitHubActionsTokenGetter) GetIDToken(ctx context.Context) (string, error) {
+	if token := os.Getenv("GITHUB_OIDC_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	requestURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	requestToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+	if requestURL == "" || requestToken == "" {
+		return "", fmt.Errorf("oidcflow: not running in a GitHub Actions job with id-token permission")
+	}
+
+	if g.Audience != "" {
+		u, err := url.Parse(requestURL)
+		if err != nil {
+			return "", fmt.Errorf("oidcflow: parse ACTIONS_ID_TOKEN_REQUEST_URL: %w", err)
+		}
+		q := u.Query()
+		q.Set("audience", g.Audience)
+		u.RawQuery = q.Encode()
+		requestURL = u.String()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+requestToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("oidcflow: request Actions OIDC token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("oidcflow: Actions OIDC token request returned HTTP %d: %s", resp.StatusCode, body)
+	}
+
+	var payload struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("oidcflow: decode Actions OIDC token response: %w", err)
+	}
+	return payload.Value, nil
+}
+
+// DeviceCodeTokenGetter implements the RFC 8628 device authorization grant,
+// the right flow for a headless terminal: it prints a verification URL and
+// user code, then polls the token endpoint until the user completes auth in
+// any browser.
+type DeviceCodeTokenGetter struct {
+	Config
+	// Prompt receives the verification URL and user code to display.
+	// Defaults to printing to stderr.
+	Prompt func(verificationURI, userCode string)
+}
+
+func (d *DeviceCodeTokenGetter) GetIDToken(ctx context.Context) (string, error) {
+	doc, err := discover(ctx, d.Issuer)
+	if err != nil {
+		return "", err
+	}
+	if doc.DeviceAuthEndpoint == "" {
+		return "", fmt.Errorf("oidcflow: issuer %s does not advertise a device_authorization_endpoint", d.Issuer)
+	}
+
+	form := url.Values{
+		"client_id": {d.ClientID},
+		"scope":     {"openid email"},
+	}
+	resp, err := http.PostForm(doc.DeviceAuthEndpoint, form)
+	if err != nil {
+		return "", fmt.Errorf("oidcflow: device authorization request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var auth struct {
+		DeviceCode      string `json:"device_code"`
+		UserCode        string `json:"user_code"`
+		VerificationURI string `json:"verification_uri"`
+		ExpiresIn       int    `json:"expires_in"`
+		Interval        int    `json:"interval"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&auth); err != nil {
+		return "", fmt.Errorf("oidcflow: decode device authorization response: %w", err)
+	}
+
+	prompt := d.Prompt
+	if prompt == nil {
+		prompt = func(uri, code string) {
+			fmt.Fprintf(os.Stderr, "To sign in, visit %s and enter code %s\n", uri, code)
+		}
+	}
+	prompt(auth.VerificationURI, auth.UserCode)
+
+	interval := time.Duration(auth.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(auth.ExpiresIn) * time.Second)
+
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+
+		tokenForm := url.Values{
+			"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+			"device_code": {auth.DeviceCode},
+			"client_id":   {d.ClientID},
+		}
+		if d.ClientSecret != "" {
+			tokenForm.Set("client_secret", d.ClientSecret)
+		}
+
+		tokenResp, err := http.PostForm(doc.TokenEndpoint, tokenForm)
+		if err != nil {
+			return "", fmt.Errorf("oidcflow: device token poll: %w", err)
+		}
+		var result struct {
+			IDToken string `json:"id_token"`
+			Error   string `json:"error"`
+		}
+		decodeErr := json.NewDecoder(tokenResp.Body).Decode(&result)
+		tokenResp.Body.Close()
+		if decodeErr != nil {
+			return "", fmt.Errorf("oidcflow: decode device token response: %w", decodeErr)
+		}
+
+		switch result.Error {
+		case "":
+			if result.IDToken == "" {
+				return "", fmt.Errorf("oidcflow: token response had no id_token")
+			}
+			return result.IDToken, nil
+		case "authorization_pending", "slow_down":
+			continue
+		default:
+			return "", fmt.Errorf("oidcflow: device authorization failed: %s", result.Error)
+		}
+	}
+
+	return "", fmt.Errorf("oidcflow: device authorization expired before the user completed sign-in")
+}
+
+// BrowserTokenGetter implements the authorization-code flow with PKCE,
+// opening the user's default browser and listening on RedirectURL for the
+// callback - the right flow for an interactive developer machine.
+type BrowserTokenGetter struct {
+	Config
+	// Open launches url in a browser. Defaults to the OS's URL opener:
+	// "open" on macOS, "rundll32" on Windows, "xdg-open" elsewhere.
+	Open func(url string) error
+}
+
+func (b *BrowserTokenGetter) GetIDToken(ctx context.Context) (string, error) {
+	doc, err := discover(ctx, b.Issuer)
+	if err != nil {
+		return "", err
+	}
+
+	verifier, challenge, err := newPKCEPair()
+	if err != nil {
+		return "", err
+	}
+	state, err := randomString(16)
+	if err != nil {
+		return "", err
+	}
+
+	redirect, err := url.Parse(b.RedirectURL)
+	if err != nil {
+		return "", fmt.Errorf("oidcflow: parse redirect URL: %w", err)
+	}
+
+	authURL, err := url.Parse(doc.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("oidcflow: parse authorization endpoint: %w", err)
+	}
+	q := authURL.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", b.ClientID)
+	q.Set("redirect_uri", b.RedirectURL)
+	q.Set("scope", "openid email")
+	q.Set("state", state)
+	q.Set("code_challenge", challenge)
+	q.Set("code_challenge_method", "S256")
+	authURL.RawQuery = q.Encode()
+
+	codeCh := make(chan string, 1)
+	errCh := make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(redirect.Path, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != state {
+			errCh <- fmt.Errorf("oidcflow: callback state mismatch")
+			http.Error(w, "state mismatch", http.StatusBadRequest)
+			return
+		}
+		if errMsg := r.URL.Query().Get("error"); errMsg != "" {
+			errCh <- fmt.Errorf("oidcflow: authorization denied: %s", errMsg)
+			http.Error(w, errMsg, http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, "Sign-in complete, you may close this tab.")
+		codeCh <- r.URL.Query().Get("code")
+	})
+
+	server := &http.Server{Addr: redirect.Host, Handler: mux}
+	go server.ListenAndServe()
+	defer server.Close()
+
+	open := b.Open
+	if open == nil {
+		open = openBrowser
+	}
+	if err := open(authURL.String()); err != nil {
+		fmt.Fprintf(os.Stderr, "Open this URL to sign in: %s\n", authURL.String())
+	}
+
+	var code string
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case err := <-errCh:
+		return "", err
+	case code = <-codeCh:
+	}
+
+	tokenForm := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {b.RedirectURL},
+		"client_id":     {b.ClientID},
+		"code_verifier": {verifier},
+	}
+	if b.ClientSecret != "" {
+		tokenForm.Set("client_secret", b.ClientSecret)
+	}
+
+	tokenResp, err := http.PostForm(doc.TokenEndpoint, tokenForm)
+	if err != nil {
+		return "", fmt.Errorf("oidcflow: exchange authorization code: %w", err)
+	}
+	defer tokenResp.Body.Close()
+
+	var result struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.NewDecoder(tokenResp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("oidcflow: decode token response: %w", err)
+	}
+	if result.IDToken == "" {
+		return "", fmt.Errorf("oidcflow: token response had no id_token")
+	}
+	return result.IDToken, nil
+}
+
+func openBrowser(target string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", target).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", target).Start()
+	default:
+		return exec.Command("xdg-open", target).Start()
+	}
+}
+
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// newPKCEPair returns a random code_verifier and its S256 code_challenge,
+// per RFC 7636.
+func newPKCEPair() (verifier, challenge string, err error) {
+	verifier, err = randomString(32)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return verifier, base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
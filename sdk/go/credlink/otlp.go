@@ -0,0 +1,366 @@
+package credlink
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	crand "crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// ============================================================================
+// OTLP/HTTP Exporter
+// ============================================================================
+
+const (
+	defaultOTLPEndpoint      = "http://localhost:4318"
+	otlpTracesPath           = "/v1/traces"
+	otlpMetricsPath          = "/v1/metrics"
+	defaultOTLPBatchSize     = 50
+	defaultOTLPBatchInterval = 5 * time.Second
+	otlpExportTimeout        = 10 * time.Second
+	otlpMaxExportAttempts    = 3
+)
+
+// otlpExporter batches spans and metrics and flushes them to an OTLP/HTTP
+// collector as protobuf-encoded ExportTraceServiceRequest/
+// ExportMetricsServiceRequest messages, instead of making one HTTP call per
+// span. A batch is flushed when it reaches batchSize or batchInterval
+// elapses, whichever comes first.
+type otlpExporter struct {
+	endpoint    string
+	compression string
+	httpClient  *http.Client
+
+	batchSize     int
+	batchInterval time.Duration
+
+	mu             sync.Mutex
+	pendingSpans   []*tracepb.Span
+	pendingMetrics []*metricspb.Metric
+
+	flushCh   chan struct{}
+	stopCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// newOTLPExporter builds an exporter from cfg. The collector endpoint is
+// read from cfg.OTel["endpoint"], falling back to the
+// OTEL_EXPORTER_OTLP_ENDPOINT environment variable and then
+// defaultOTLPEndpoint. cfg.OTel["compression"] == "gzip" enables gzip
+// request bodies.
+func newOTLPExporter(cfg *TelemetryConfig, baseClient *http.Client) *otlpExporter {
+	endpoint := defaultOTLPEndpoint
+	compression := ""
+	batchSize := defaultOTLPBatchSize
+	batchInterval := defaultOTLPBatchInterval
+
+	if cfg != nil && cfg.OTel != nil {
+		if v := cfg.OTel["endpoint"]; v != "" {
+			endpoint = v
+		}
+		compression = cfg.OTel["compression"]
+		if v := cfg.OTel["batch_size"]; v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				batchSize = n
+			}
+		}
+		if v := cfg.OTel["batch_interval_ms"]; v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 {
+				batchInterval = time.Duration(n) * time.Millisecond
+			}
+		}
+	}
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); v != "" && (cfg == nil || cfg.OTel["endpoint"] == "") {
+		endpoint = v
+	}
+
+	// Clone the SDK's own http.Transport (rather than reusing baseClient
+	// directly) so any TLS/proxy overrides configured on it apply to OTLP
+	// exports too, without sharing connection pool state with API calls.
+	client := &http.Client{Timeout: otlpExportTimeout}
+	if baseClient != nil {
+		if rt, ok := baseClient.Transport.(*http.Transport); ok && rt != nil {
+			client.Transport = rt.Clone()
+		}
+	}
+
+	e := &otlpExporter{
+		endpoint:      strings.TrimSuffix(endpoint, "/"),
+		compression:   compression,
+		httpClient:    client,
+		batchSize:     batchSize,
+		batchInterval: batchInterval,
+		flushCh:       make(chan struct{}, 1),
+		stopCh:        make(chan struct{}),
+	}
+	go e.batchLoop()
+	return e
+}
+
+func (e *otlpExporter) batchLoop() {
+	ticker := time.NewTicker(e.batchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			e.flush()
+		case <-e.flushCh:
+			e.flush()
+		case <-e.stopCh:
+			e.flush()
+			return
+		}
+	}
+}
+
+func (e *otlpExporter) enqueueSpan(span *tracepb.Span) {
+	e.mu.Lock()
+	e.pendingSpans = append(e.pendingSpans, span)
+	full := len(e.pendingSpans) >= e.batchSize
+	e.mu.Unlock()
+	if full {
+		e.requestFlush()
+	}
+}
+
+func (e *otlpExporter) enqueueMetric(metric *metricspb.Metric) {
+	e.mu.Lock()
+	e.pendingMetrics = append(e.pendingMetrics, metric)
+	full := len(e.pendingMetrics) >= e.batchSize
+	e.mu.Unlock()
+	if full {
+		e.requestFlush()
+	}
+}
+
+func (e *otlpExporter) requestFlush() {
+	select {
+	case e.flushCh <- struct{}{}:
+	default:
+	}
+}
+
+func (e *otlpExporter) flush() {
+	e.mu.Lock()
+	spans := e.pendingSpans
+	metrics := e.pendingMetrics
+	e.pendingSpans = nil
+	e.pendingMetrics = nil
+	e.mu.Unlock()
+
+	if len(spans) > 0 {
+		if err := e.exportTraces(spans); err != nil {
+			fmt.Fprintf(os.Stderr, "otlp: failed to export %d span(s): %v\n", len(spans), err)
+		}
+	}
+	if len(metrics) > 0 {
+		if err := e.exportMetrics(metrics); err != nil {
+			fmt.Fprintf(os.Stderr, "otlp: failed to export %d metric(s): %v\n", len(metrics), err)
+		}
+	}
+}
+
+// close signals the background batch loop to flush any remaining batch and
+// stop; it returns without waiting for that final export to finish.
+func (e *otlpExporter) close() {
+	e.closeOnce.Do(func() { close(e.stopCh) })
+}
+
+func (e *otlpExporter) exportTraces(spans []*tracepb.Span) error {
+	req := &coltracepb.ExportTraceServiceRequest{
+		ResourceSpans: []*tracepb.ResourceSpans{
+			{
+				Resource:   e.resource(),
+				ScopeSpans: []*tracepb.ScopeSpans{{Spans: spans}},
+			},
+		},
+	}
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshal trace export request: %w", err)
+	}
+	return e.send(context.Background(), e.endpoint+otlpTracesPath, body)
+}
+
+func (e *otlpExporter) exportMetrics(metrics []*metricspb.Metric) error {
+	req := &colmetricspb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{
+			{
+				Resource:     e.resource(),
+				ScopeMetrics: []*metricspb.ScopeMetrics{{Metrics: metrics}},
+			},
+		},
+	}
+	body, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshal metrics export request: %w", err)
+	}
+	return e.send(context.Background(), e.endpoint+otlpMetricsPath, body)
+}
+
+func (e *otlpExporter) resource() *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Attributes: []*commonpb.KeyValue{
+			stringAttr("service.name", "credlink-sdk-go"),
+			stringAttr("service.version", Version),
+		},
+	}
+}
+
+// send POSTs an OTLP protobuf body, gzipping it first when compression is
+// configured, retrying on 429/503 while honoring Retry-After (seconds or an
+// HTTP-date), and aborting as soon as ctx is canceled.
+func (e *otlpExporter) send(ctx context.Context, url string, body []byte) error {
+	payload := body
+	encoding := ""
+	if e.compression == "gzip" {
+		compressed, err := gzipBytes(body)
+		if err != nil {
+			return fmt.Errorf("gzip otlp payload: %w", err)
+		}
+		payload = compressed
+		encoding = "gzip"
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < otlpMaxExportAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("build otlp request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-protobuf")
+		if encoding != "" {
+			req.Header.Set("Content-Encoding", encoding)
+		}
+
+		resp, err := e.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			retryAfter := parseOTLPRetryAfter(resp.Header.Get("Retry-After"))
+			resp.Body.Close()
+			lastErr = fmt.Errorf("otlp export got HTTP %d", resp.StatusCode)
+			if attempt == otlpMaxExportAttempts-1 {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(retryAfter):
+			}
+			continue
+		}
+
+		if resp.StatusCode >= 300 {
+			respBody, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return fmt.Errorf("otlp export failed: HTTP %d: %s", resp.StatusCode, string(respBody))
+		}
+
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return nil
+	}
+
+	return lastErr
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// parseOTLPRetryAfter accepts either a delay in seconds or an HTTP-date, per
+// RFC 9110 §10.2.3, defaulting to one second when the header is missing or
+// unparseable.
+func parseOTLPRetryAfter(header string) time.Duration {
+	if header == "" {
+		return time.Second
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return time.Second
+}
+
+func newTraceID() []byte {
+	b := make([]byte, 16)
+	_, _ = crand.Read(b)
+	return b
+}
+
+func newSpanID() []byte {
+	b := make([]byte, 8)
+	_, _ = crand.Read(b)
+	return b
+}
+
+func stringAttr(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+func toAnyValue(v interface{}) *commonpb.AnyValue {
+	switch val := v.(type) {
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: val}}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: val}}
+	case int:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: int64(val)}}
+	case int64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: val}}
+	case float64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: val}}
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: fmt.Sprintf("%v", val)}}
+	}
+}
+
+func toAttributes(m map[string]interface{}) []*commonpb.KeyValue {
+	if len(m) == 0 {
+		return nil
+	}
+	attrs := make([]*commonpb.KeyValue, 0, len(m))
+	for k, v := range m {
+		attrs = append(attrs, &commonpb.KeyValue{Key: k, Value: toAnyValue(v)})
+	}
+	return attrs
+}
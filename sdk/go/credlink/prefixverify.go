@@ -0,0 +1,435 @@
+package credlink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// ============================================================================
+// Prefix Verification (s3:// / r2://)
+// ============================================================================
+
+// PrefixObjectResult is one object's verification outcome; also the shape
+// streamed as an NDJSON record when PrefixVerifierOptions.Stream is set.
+type PrefixObjectResult struct {
+	URL        string   `json:"url"`
+	Verified   bool     `json:"verified"`
+	Error      *string  `json:"error,omitempty"`
+	ManifestID *string  `json:"manifest_id,omitempty"`
+	Survival   *float64 `json:"survival,omitempty"`
+}
+
+// PrefixVerifySummary aggregates the outcome of a prefix verification run.
+type PrefixVerifySummary struct {
+	Total    int
+	Verified int
+	Failed   int
+}
+
+// Metrics mirrors the CLI's VerifyAggregate.Metrics so --threshold
+// expressions evaluate a prefix run the same way they evaluate a
+// single/page run.
+func (s PrefixVerifySummary) Metrics() map[string]float64 {
+	ratio := 0.0
+	if s.Total > 0 {
+		ratio = float64(s.Verified) / float64(s.Total)
+	}
+	return map[string]float64{
+		"survival":       ratio,
+		"verified_ratio": ratio,
+		"failed":         float64(s.Failed),
+	}
+}
+
+// PrefixVerifierOptions configures a PrefixVerifier run.
+type PrefixVerifierOptions struct {
+	// Delimiter separates listing "directories"; forwarded to ListObjectsV2.
+	// Defaults to "/".
+	Delimiter string
+	// Concurrency is the number of objects verified in parallel. Defaults to 8.
+	Concurrency int
+	// PerObjectTimeout bounds each VerifyAsset call. Defaults to 30s.
+	PerObjectTimeout time.Duration
+	// Profile selects a named AWS/R2 credentials profile; if empty,
+	// credentials come from the standard AWS_ACCESS_KEY_ID /
+	// AWS_SECRET_ACCESS_KEY / R2 env vars via the default credential chain.
+	Profile string
+	// PolicyID is forwarded to each VerifyAsset call.
+	PolicyID string
+	// Stream, when true, writes one JSON PrefixObjectResult per object to
+	// Out as it completes.
+	Stream bool
+	// Out is where streamed NDJSON records are written. Defaults to os.Stdout.
+	Out io.Writer
+	// ResumeFile is the checkpoint path used to persist/resume listing
+	// progress. Defaults to ".credlink-resume.json".
+	ResumeFile string
+	// IdempotencyKey identifies this run in the resume file: a stored
+	// checkpoint is resumed only when its key and prefix both match this
+	// run's; otherwise the run starts fresh and overwrites it.
+	IdempotencyKey string
+}
+
+// PrefixVerifier lists objects under an s3:// or r2:// prefix and verifies
+// each one concurrently, checkpointing listing progress so an interrupted
+// run can resume instead of re-listing and re-verifying everything already
+// covered.
+type PrefixVerifier struct {
+	client *Client
+	opts   PrefixVerifierOptions
+}
+
+// NewPrefixVerifier creates a PrefixVerifier bound to client.
+func NewPrefixVerifier(client *Client, opts PrefixVerifierOptions) *PrefixVerifier {
+	if opts.Delimiter == "" {
+		opts.Delimiter = "/"
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 8
+	}
+	if opts.PerObjectTimeout <= 0 {
+		opts.PerObjectTimeout = 30 * time.Second
+	}
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+	if opts.ResumeFile == "" {
+		opts.ResumeFile = ".credlink-resume.json"
+	}
+	return &PrefixVerifier{client: client, opts: opts}
+}
+
+// prefixResumeState is the on-disk checkpoint format. A resume file holds
+// one checkpoint, tagged with its IdempotencyKey and prefix; a run with a
+// different key or prefix replaces it rather than sharing the file.
+type prefixResumeState struct {
+	IdempotencyKey    string `json:"idempotency_key"`
+	Prefix            string `json:"prefix"`
+	ContinuationToken string `json:"continuation_token,omitempty"`
+	Verified          int    `json:"verified"`
+	Failed            int    `json:"failed"`
+}
+
+// Run lists and verifies every object under target (an s3:// or r2:// URL),
+// resuming from a prior checkpoint for the same IdempotencyKey if one is
+// found in ResumeFile.
+func (v *PrefixVerifier) Run(ctx context.Context, target string) (*PrefixVerifySummary, error) {
+	bucket, prefix, endpoint, err := parsePrefixTarget(target)
+	if err != nil {
+		return nil, err
+	}
+
+	s3Client, err := v.newS3Client(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := v.loadResumeState(target)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &PrefixVerifySummary{Verified: state.Verified, Failed: state.Failed}
+	summary.Total = summary.Verified + summary.Failed
+
+	jobs := make(chan string)
+	results := make(chan PrefixObjectResult)
+
+	// tracker gates each page's continuation token behind the completion of
+	// every object that page gated, so saveResumeState never persists a
+	// token past objects that haven't actually been verified yet.
+	tracker := newTokenTracker(state.ContinuationToken)
+
+	var wg sync.WaitGroup
+	for i := 0; i < v.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v.worker(ctx, jobs, results)
+		}()
+	}
+
+	var listErr error
+	go func() {
+		defer close(jobs)
+		listErr = v.listObjects(ctx, s3Client, bucket, prefix, state, tracker, jobs)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var summaryMu sync.Mutex
+	for result := range results {
+		summaryMu.Lock()
+		summary.Total++
+		if result.Verified {
+			summary.Verified++
+		} else {
+			summary.Failed++
+		}
+		state.Verified = summary.Verified
+		state.Failed = summary.Failed
+		summaryMu.Unlock()
+
+		if v.opts.Stream {
+			v.writeStreamRecord(result)
+		}
+
+		state.ContinuationToken = tracker.completeOne()
+		_ = v.saveResumeState(target, state)
+	}
+
+	if listErr != nil {
+		return summary, listErr
+	}
+	if err := ctx.Err(); err != nil {
+		return summary, err
+	}
+
+	// A clean completion clears the checkpoint; otherwise a later run
+	// against the same prefix would skip objects a fresh listing re-finds.
+	_ = os.Remove(v.opts.ResumeFile)
+
+	return summary, nil
+}
+
+func (v *PrefixVerifier) worker(ctx context.Context, jobs <-chan string, results chan<- PrefixObjectResult) {
+	for objectURL := range jobs {
+		result := v.verifyOne(ctx, objectURL)
+		select {
+		case results <- result:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (v *PrefixVerifier) verifyOne(ctx context.Context, objectURL string) PrefixObjectResult {
+	callCtx, cancel := context.WithTimeout(ctx, v.opts.PerObjectTimeout)
+	defer cancel()
+
+	resp, err := v.client.VerifyAsset(callCtx, objectURL, VerifyAssetOptions{
+		PolicyID: v.opts.PolicyID,
+	})
+	if err != nil {
+		errMsg := err.Error()
+		return PrefixObjectResult{URL: objectURL, Verified: false, Error: &errMsg}
+	}
+
+	result := PrefixObjectResult{
+		URL:      objectURL,
+		Verified: resp.Data.Verified,
+	}
+	if resp.Data.ManifestURL != nil {
+		result.ManifestID = resp.Data.ManifestURL
+	}
+	return result
+}
+
+func (v *PrefixVerifier) writeStreamRecord(result PrefixObjectResult) {
+	encoder := json.NewEncoder(v.opts.Out)
+	_ = encoder.Encode(result)
+}
+
+// listObjects paginates ListObjectsV2 starting from tracker's committed
+// token (if any), pushing one "s3://bucket/key" or "r2://bucket/key" URL per
+// object onto jobs. Each page's token is registered with tracker rather than
+// written to state directly - it only becomes the persisted checkpoint once
+// every object that page produced has a recorded verification result.
+func (v *PrefixVerifier) listObjects(ctx context.Context, client *s3.Client, bucket, prefix string, state *prefixResumeState, tracker *tokenTracker, jobs chan<- string) error {
+	var token *string
+	if t := tracker.snapshot(); t != "" {
+		token = aws.String(t)
+	}
+
+	scheme := "s3"
+	if strings.Contains(state.Prefix, "r2://") {
+		scheme = "r2"
+	}
+
+	for {
+		page, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:            aws.String(bucket),
+			Prefix:            aws.String(prefix),
+			Delimiter:         aws.String(v.opts.Delimiter),
+			ContinuationToken: token,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list %s://%s/%s: %w", scheme, bucket, prefix, err)
+		}
+
+		for _, obj := range page.Contents {
+			select {
+			case jobs <- fmt.Sprintf("%s://%s/%s", scheme, bucket, aws.ToString(obj.Key)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if page.IsTruncated == nil || !*page.IsTruncated || page.NextContinuationToken == nil {
+			tracker.addPage("", len(page.Contents))
+			return nil
+		}
+
+		token = page.NextContinuationToken
+		tracker.addPage(aws.ToString(token), len(page.Contents))
+	}
+}
+
+// tokenTracker defers committing a page's continuation token until every
+// object that page dispatched has a recorded result, so a crash between
+// listing a page and finishing its verification can't make a resume skip
+// objects that were listed but never actually checked. Pages are completed
+// in FIFO order since earlier pages are always registered first.
+type tokenTracker struct {
+	mu      sync.Mutex
+	pending []pendingPage
+	current string
+}
+
+// pendingPage is one page's still-outstanding object count gating its token.
+type pendingPage struct {
+	token     string
+	remaining int
+}
+
+func newTokenTracker(initial string) *tokenTracker {
+	return &tokenTracker{current: initial}
+}
+
+// addPage registers a page's token, gated by count outstanding objects. A
+// page with no objects has nothing gating it, so its token commits right away.
+func (t *tokenTracker) addPage(token string, count int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if count <= 0 {
+		t.current = token
+		return
+	}
+	t.pending = append(t.pending, pendingPage{token: token, remaining: count})
+}
+
+// completeOne records one more object result and returns the token now safe
+// to persist: the committed token advances past a page only once its count
+// has been fully consumed.
+func (t *tokenTracker) completeOne() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.pending) > 0 {
+		t.pending[0].remaining--
+		if t.pending[0].remaining <= 0 {
+			t.current = t.pending[0].token
+			t.pending = t.pending[1:]
+		}
+	}
+	return t.current
+}
+
+// snapshot returns the token currently safe to resume from.
+func (t *tokenTracker) snapshot() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.current
+}
+
+func (v *PrefixVerifier) newS3Client(ctx context.Context, endpoint string) (*s3.Client, error) {
+	loadOpts := []func(*config.LoadOptions) error{}
+	if v.opts.Profile != "" {
+		loadOpts = append(loadOpts, config.WithSharedConfigProfile(v.opts.Profile))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS/R2 credentials: %w", err)
+	}
+
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		}
+	}), nil
+}
+
+// parsePrefixTarget splits an s3://bucket/prefix or r2://account/bucket/prefix
+// URL into a bucket, prefix, and (for R2) the account-scoped S3-compatible
+// endpoint to use instead of AWS's.
+func parsePrefixTarget(target string) (bucket, prefix, endpoint string, err error) {
+	switch {
+	case strings.HasPrefix(target, "s3://"):
+		rest := strings.TrimPrefix(target, "s3://")
+		parts := strings.SplitN(rest, "/", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return "", "", "", fmt.Errorf("invalid S3 path format, expected: s3://bucket/prefix")
+		}
+		return parts[0], parts[1], "", nil
+
+	case strings.HasPrefix(target, "r2://"):
+		rest := strings.TrimPrefix(target, "r2://")
+		parts := strings.SplitN(rest, "/", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return "", "", "", fmt.Errorf("invalid R2 path format, expected: r2://account/bucket/prefix")
+		}
+		account := parts[0]
+		endpoint = fmt.Sprintf("https://%s.r2.cloudflarestorage.com", account)
+		return parts[1], parts[2], endpoint, nil
+
+	default:
+		return "", "", "", fmt.Errorf("unsupported prefix scheme (expected s3:// or r2://): %s", target)
+	}
+}
+
+// loadResumeState reads the checkpoint file if it matches this run's
+// IdempotencyKey, or returns a fresh state otherwise.
+func (v *PrefixVerifier) loadResumeState(target string) (*prefixResumeState, error) {
+	fresh := &prefixResumeState{IdempotencyKey: v.opts.IdempotencyKey, Prefix: target}
+
+	data, err := os.ReadFile(v.opts.ResumeFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fresh, nil
+		}
+		return nil, fmt.Errorf("failed to read resume file: %w", err)
+	}
+
+	var state prefixResumeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse resume file: %w", err)
+	}
+
+	if state.IdempotencyKey != v.opts.IdempotencyKey || state.Prefix != target {
+		return fresh, nil
+	}
+
+	return &state, nil
+}
+
+// saveResumeState persists the checkpoint via a temp-file-plus-rename so a
+// crash mid-write can't corrupt progress an interrupted run would rely on.
+func (v *PrefixVerifier) saveResumeState(target string, state *prefixResumeState) error {
+	state.Prefix = target
+	state.IdempotencyKey = v.opts.IdempotencyKey
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := v.opts.ResumeFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, v.opts.ResumeFile)
+}
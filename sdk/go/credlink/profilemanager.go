@@ -0,0 +1,452 @@
+package credlink
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// defaultRenewBefore is how long before a certificate's NotAfter
+// StartProfileRenewal renews it when EnrollProfileOptions.RenewBefore is
+// unset.
+const defaultRenewBefore = 30 * 24 * time.Hour
+
+// profileRenewalCheckInterval is how often StartProfileRenewal's background
+// goroutine checks managed profiles against their RenewBefore threshold.
+const profileRenewalCheckInterval = time.Hour
+
+// profileRecord is the persisted state of one ACME-managed signing profile:
+// its enrollment options, the key and certificate chain last issued for it,
+// and renewal bookkeeping. It's written through the same pluggable store
+// used for the manifest cache (Config.ManifestCache); nothing reads it back
+// from there, so a new process starts with no managed profiles.
+type profileRecord struct {
+	ProfileID        string            `json:"profile_id"`
+	ACMEDirectoryURL string            `json:"acme_directory_url"`
+	Contact          []string          `json:"contact,omitempty"`
+	KeyType          string            `json:"key_type"`
+	Subject          string            `json:"subject"`
+	Challenge        ACMEChallengeType `json:"challenge"`
+	RenewBefore      time.Duration     `json:"renew_before"`
+	PrivateKeyPEM    string            `json:"private_key_pem"`
+	CertificateChain []string          `json:"certificate_chain"`
+	NotAfter         time.Time         `json:"not_after"`
+	Issuer           string            `json:"issuer"`
+	LastRenewal      time.Time         `json:"last_renewal"`
+	NextAttempt      time.Time         `json:"next_attempt"`
+}
+
+// managedProfile guards one profileRecord against concurrent reads
+// (Client.ProfileStatus) and writes (the renewal goroutine), so a renewal
+// in progress never hands a reader a half-updated cert chain / NotAfter
+// pair.
+type managedProfile struct {
+	mu     sync.RWMutex
+	record profileRecord
+}
+
+func (p *managedProfile) snapshot() profileRecord {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.record
+}
+
+func (p *managedProfile) set(record profileRecord) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.record = record
+}
+
+// ProfileManager provisions and auto-renews the X.509 certificates backing
+// SignFolderOptions.ProfileID through an ACME-compatible CA (e.g. a step-ca
+// deployment), the same order/challenge/finalize flow smallstep's ACME
+// handler implements. Create one via Client.EnrollProfile; don't construct
+// directly.
+type ProfileManager struct {
+	client *Client
+
+	mu       sync.Mutex
+	profiles map[string]*managedProfile
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newProfileManager(client *Client) *ProfileManager {
+	return &ProfileManager{client: client, profiles: make(map[string]*managedProfile)}
+}
+
+// Stop ends the background renewal goroutine started by
+// Client.StartProfileRenewal, if running. A nil ProfileManager is a no-op,
+// so Client.Close doesn't need to guard the call on whether any profile was
+// ever enrolled.
+func (pm *ProfileManager) Stop() {
+	if pm == nil {
+		return
+	}
+	pm.mu.Lock()
+	stopCh := pm.stopCh
+	pm.stopCh = nil
+	pm.mu.Unlock()
+
+	if stopCh != nil {
+		close(stopCh)
+	}
+	pm.wg.Wait()
+}
+
+// EnrollProfile provisions a new signing profile's certificate from
+// opts.ACMEDirectoryURL: it generates a key pair, opens an ACME order for
+// opts.Subject, satisfies the requested challenge, finalizes the order with
+// a CSR, and persists the generated private key and issued chain through
+// Config.ManifestCache, when one is configured. No separate ACME account key
+// is created or stored. It returns the new ProfileID to pass as
+// SignFolderOptions.ProfileID.
+func (c *Client) EnrollProfile(ctx context.Context, opts EnrollProfileOptions) (string, error) {
+	span := c.telemetry.CreateSpan("profile.enroll", map[string]interface{}{
+		"acme_directory_url": opts.ACMEDirectoryURL,
+		"subject":            opts.Subject,
+		"challenge":          opts.Challenge,
+	})
+	defer span.End()
+
+	if opts.RenewBefore <= 0 {
+		opts.RenewBefore = defaultRenewBefore
+	}
+	if opts.KeyType == "" {
+		opts.KeyType = "ecdsa-p256"
+	}
+
+	priv, privPEM, err := generateProfileKey(opts.KeyType)
+	if err != nil {
+		return "", err
+	}
+
+	chain, notAfter, issuer, err := c.runACMEEnrollment(ctx, opts, priv)
+	if err != nil {
+		return "", fmt.Errorf("profile.enroll: %w", err)
+	}
+
+	profileID := "profile_" + c.generateIdempotencyKey()
+	record := profileRecord{
+		ProfileID:        profileID,
+		ACMEDirectoryURL: opts.ACMEDirectoryURL,
+		Contact:          opts.Contact,
+		KeyType:          opts.KeyType,
+		Subject:          opts.Subject,
+		Challenge:        opts.Challenge,
+		RenewBefore:      opts.RenewBefore,
+		PrivateKeyPEM:    privPEM,
+		CertificateChain: chain,
+		NotAfter:         notAfter,
+		Issuer:           issuer,
+		LastRenewal:      time.Now(),
+		NextAttempt:      notAfter.Add(-opts.RenewBefore),
+	}
+
+	if err := c.saveProfileRecord(ctx, record); err != nil {
+		return "", fmt.Errorf("profile.enroll: %w", err)
+	}
+
+	if c.profileManager == nil {
+		c.profileManager = newProfileManager(c)
+	}
+	c.profileManager.mu.Lock()
+	c.profileManager.profiles[profileID] = &managedProfile{record: record}
+	c.profileManager.mu.Unlock()
+
+	span.SetAttribute("profile_id", profileID)
+	span.SetAttribute("not_after", notAfter)
+
+	return profileID, nil
+}
+
+// StartProfileRenewal starts a background goroutine that checks every
+// profile enrolled via EnrollProfile against its RenewBefore threshold and
+// reruns the ACME order when time.Until(NotAfter) < RenewBefore, swapping
+// the cert atomically so in-flight SignFolder/SignAsset calls always read
+// either the old or the new chain, never a partial one. Calling it again
+// while the goroutine runs is a no-op; called before any profile has been
+// enrolled, it starts the goroutine, which picks up profiles enrolled later.
+func (c *Client) StartProfileRenewal() error {
+	if c.profileManager == nil {
+		c.profileManager = newProfileManager(c)
+	}
+	pm := c.profileManager
+
+	pm.mu.Lock()
+	if pm.stopCh != nil {
+		pm.mu.Unlock()
+		return nil
+	}
+	stopCh := make(chan struct{})
+	pm.stopCh = stopCh
+	pm.mu.Unlock()
+
+	pm.wg.Add(1)
+	go func() {
+		defer pm.wg.Done()
+		ticker := time.NewTicker(profileRenewalCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+				pm.checkAndRenewAll()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// ProfileStatus reports the certificate EnrollProfile last issued (or
+// renewed) for profileID.
+func (c *Client) ProfileStatus(profileID string) (ProfileStatus, error) {
+	if c.profileManager == nil {
+		return ProfileStatus{}, fmt.Errorf("profile.status: unknown profile %s", profileID)
+	}
+	c.profileManager.mu.Lock()
+	mp, ok := c.profileManager.profiles[profileID]
+	c.profileManager.mu.Unlock()
+	if !ok {
+		return ProfileStatus{}, fmt.Errorf("profile.status: unknown profile %s", profileID)
+	}
+
+	record := mp.snapshot()
+	return ProfileStatus{
+		NotAfter:    record.NotAfter,
+		Issuer:      record.Issuer,
+		LastRenewal: record.LastRenewal,
+		NextAttempt: record.NextAttempt,
+	}, nil
+}
+
+// checkAndRenewAll renews every managed profile whose certificate is within
+// its RenewBefore window, emitting a profile.renewed or
+// profile.renewal_failed notification for each attempt.
+func (pm *ProfileManager) checkAndRenewAll() {
+	pm.mu.Lock()
+	profiles := make([]*managedProfile, 0, len(pm.profiles))
+	for _, mp := range pm.profiles {
+		profiles = append(profiles, mp)
+	}
+	pm.mu.Unlock()
+
+	for _, mp := range profiles {
+		record := mp.snapshot()
+		if time.Until(record.NotAfter) >= record.RenewBefore {
+			continue
+		}
+		pm.renew(mp, record)
+	}
+}
+
+// renew reruns the ACME order for an already-enrolled profile, reusing its
+// stored key, and atomically swaps in the freshly issued chain.
+func (pm *ProfileManager) renew(mp *managedProfile, record profileRecord) {
+	ctx := context.Background()
+
+	priv, err := parseProfileKey(record.PrivateKeyPEM)
+	if err != nil {
+		pm.reportRenewalFailure(record, err)
+		return
+	}
+
+	opts := EnrollProfileOptions{
+		ACMEDirectoryURL: record.ACMEDirectoryURL,
+		Contact:          record.Contact,
+		KeyType:          record.KeyType,
+		Subject:          record.Subject,
+		Challenge:        record.Challenge,
+		RenewBefore:      record.RenewBefore,
+	}
+
+	chain, notAfter, issuer, err := pm.client.runACMEEnrollment(ctx, opts, priv)
+	if err != nil {
+		record.NextAttempt = time.Now().Add(profileRenewalCheckInterval)
+		mp.set(record)
+		pm.reportRenewalFailure(record, err)
+		return
+	}
+
+	record.CertificateChain = chain
+	record.NotAfter = notAfter
+	record.Issuer = issuer
+	record.LastRenewal = time.Now()
+	record.NextAttempt = notAfter.Add(-record.RenewBefore)
+	mp.set(record)
+
+	if err := pm.client.saveProfileRecord(ctx, record); err != nil {
+		pm.reportRenewalFailure(record, err)
+		return
+	}
+
+	pm.client.notifier.Notify(NotificationEvent{
+		Action:    NotificationActionProfileRenewed,
+		Target:    NotificationTarget{ProfileID: record.ProfileID},
+		Timestamp: time.Now(),
+	})
+}
+
+func (pm *ProfileManager) reportRenewalFailure(record profileRecord, err error) {
+	pm.client.notifier.Notify(NotificationEvent{
+		Action:    NotificationActionProfileRenewalFailed,
+		Target:    NotificationTarget{ProfileID: record.ProfileID},
+		Timestamp: time.Now(),
+	})
+}
+
+// runACMEEnrollment drives the ACME order/challenge/finalize flow against
+// opts.ACMEDirectoryURL for priv, used by both EnrollProfile and each
+// renewal.
+func (c *Client) runACMEEnrollment(ctx context.Context, opts EnrollProfileOptions, priv *ecdsa.PrivateKey) ([]string, time.Time, string, error) {
+	orderReq := acmeOrderRequest{Contact: opts.Contact, Subject: opts.Subject}
+
+	resp, err := c.transport.Request(ctx, "POST", acmePath(opts.ACMEDirectoryURL, "/orders"), orderReq, NewRequestOptions())
+	if err != nil {
+		return nil, time.Time{}, "", fmt.Errorf("open ACME order: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var order acmeOrderResponse
+	if err := json.NewDecoder(resp.Body).Decode(&order); err != nil {
+		return nil, time.Time{}, "", fmt.Errorf("decode ACME order: %w", err)
+	}
+
+	challenge, err := pickChallenge(order.Challenges, opts.Challenge)
+	if err != nil {
+		return nil, time.Time{}, "", err
+	}
+
+	challengeResp, err := c.transport.Request(ctx, "POST", challenge.URL,
+		acmeChallengeResponseRequest{KeyAuthorization: challenge.Token}, NewRequestOptions())
+	if err != nil {
+		return nil, time.Time{}, "", fmt.Errorf("respond to %s challenge: %w", challenge.Type, err)
+	}
+	defer challengeResp.Body.Close()
+
+	var challengeStatus acmeChallengeResponse
+	if err := json.NewDecoder(challengeResp.Body).Decode(&challengeStatus); err != nil {
+		return nil, time.Time{}, "", fmt.Errorf("decode %s challenge response: %w", challenge.Type, err)
+	}
+	if challengeStatus.Status != "valid" {
+		return nil, time.Time{}, "", fmt.Errorf("%s challenge not satisfied: status %q", challenge.Type, challengeStatus.Status)
+	}
+
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:            pkix.Name{CommonName: opts.Subject},
+		SignatureAlgorithm: x509.ECDSAWithSHA256,
+	}, priv)
+	if err != nil {
+		return nil, time.Time{}, "", fmt.Errorf("create CSR: %w", err)
+	}
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+	finalizeResp, err := c.transport.Request(ctx, "POST", acmePath(opts.ACMEDirectoryURL, "/orders/"+order.OrderID+"/finalize"),
+		acmeFinalizeRequest{CSR: string(csrPEM)}, NewRequestOptions())
+	if err != nil {
+		return nil, time.Time{}, "", fmt.Errorf("finalize ACME order: %w", err)
+	}
+	defer finalizeResp.Body.Close()
+
+	var finalize acmeFinalizeResponse
+	if err := json.NewDecoder(finalizeResp.Body).Decode(&finalize); err != nil {
+		return nil, time.Time{}, "", fmt.Errorf("decode finalized ACME order: %w", err)
+	}
+	if len(finalize.CertificateChain) == 0 {
+		return nil, time.Time{}, "", fmt.Errorf("finalized ACME order returned an empty certificate chain")
+	}
+
+	return finalize.CertificateChain, finalize.NotAfter, finalize.Issuer, nil
+}
+
+// pickChallenge returns the offered challenge matching want, defaulting to
+// ChallengeHTTP01 when want is empty.
+func pickChallenge(offered []acmeChallenge, want ACMEChallengeType) (acmeChallenge, error) {
+	if want == "" {
+		want = ChallengeHTTP01
+	}
+	for _, challenge := range offered {
+		if challenge.Type == want {
+			return challenge, nil
+		}
+	}
+	return acmeChallenge{}, fmt.Errorf("ACME order did not offer a %s challenge", want)
+}
+
+// acmePath joins an ACME directory URL's path with a relative endpoint, so
+// acmeOrderResponse.Challenges[i].URL (already absolute, per RFC 8555) and
+// the directory-relative /orders, /orders/{id}/finalize endpoints can share
+// c.transport.Request's (method, path) signature.
+func acmePath(directoryURL, endpoint string) string {
+	return directoryURL + endpoint
+}
+
+// generateProfileKey creates a fresh key pair of keyType, PEM-encoding the
+// private half for persistence through Config.ManifestCache. Only
+// "ecdsa-p256" (the default) is supported today.
+func generateProfileKey(keyType string) (*ecdsa.PrivateKey, string, error) {
+	if keyType != "" && keyType != "ecdsa-p256" {
+		return nil, "", fmt.Errorf("unsupported profile key type %q", keyType)
+	}
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, "", fmt.Errorf("generate profile key: %w", err)
+	}
+	der, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshal profile key: %w", err)
+	}
+	return priv, string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})), nil
+}
+
+// parseProfileKey decodes a PEM-encoded EC private key persisted by
+// generateProfileKey, used to rebuild the signing key for a renewal.
+func parseProfileKey(keyPEM string) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(keyPEM))
+	if block == nil {
+		return nil, fmt.Errorf("parse profile key: not PEM-encoded")
+	}
+	priv, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse profile key: %w", err)
+	}
+	return priv, nil
+}
+
+// profileRecordKey derives the pseudo content-hash profileRecords are keyed
+// under in Config.ManifestCache - the store is built for sha256 content
+// hashes, so profile state is addressed the same way rather than adding a
+// second storage interface.
+func profileRecordKey(profileID string) string {
+	sum := sha256.Sum256([]byte("acme-profile:" + profileID))
+	return fmt.Sprintf("%x", sum)
+}
+
+// saveProfileRecord persists record through Config.ManifestCache, the same
+// pluggable store GetManifest/PutManifest use. It's a no-op if no cache is
+// configured, so EnrollProfile still works for callers who only care about
+// the in-memory ProfileManager state within this process's lifetime.
+func (c *Client) saveProfileRecord(ctx context.Context, record profileRecord) error {
+	if c.config.ManifestCache == nil {
+		return nil
+	}
+	content, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal profile record: %w", err)
+	}
+	if err := c.config.ManifestCache.Put(ctx, profileRecordKey(record.ProfileID), content, ""); err != nil {
+		return fmt.Errorf("persist profile record: %w", err)
+	}
+	return nil
+}
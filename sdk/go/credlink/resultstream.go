@@ -0,0 +1,170 @@
+package credlink
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrDeadlineExceeded is returned by ResultStream.Next when SetReadDeadline
+// fires before the next item arrives. Unlike a canceled ctx, it doesn't
+// tear down the underlying stream - the next Next call can still succeed.
+var ErrDeadlineExceeded = errors.New("credlink: read deadline exceeded")
+
+// ResultStream wraps a streaming API's result channel with a per-item read
+// deadline, the way a caller would want to bound "wait up to 200ms for the
+// next page result, then move on" without aborting the whole crawl. Modeled
+// on gVisor/netstack's deadlineTimer: a stoppable *time.Timer plus a
+// replaceable cancel channel protected by a mutex, so SetReadDeadline is
+// safe to call while Next is blocked - the old cancel channel is closed so
+// the blocked waiter wakes immediately.
+type ResultStream[T any] struct {
+	items   <-chan T
+	closeFn func() error
+
+	mu           sync.Mutex
+	readTimer    *time.Timer
+	readCancelCh chan struct{}
+	readDeadline time.Time
+	err          error
+	closed       bool
+}
+
+// newResultStream wraps items; closeFn is called at most once by Close to
+// release whatever produces items (typically canceling the request context).
+func newResultStream[T any](items <-chan T, closeFn func() error) *ResultStream[T] {
+	return &ResultStream[T]{
+		items:        items,
+		closeFn:      closeFn,
+		readCancelCh: make(chan struct{}),
+	}
+}
+
+// SetReadDeadline bounds how long the next Next call may block waiting for
+// an item. A zero Time disables the deadline.
+func (s *ResultStream[T]) SetReadDeadline(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Extending a deadline that hasn't fired yet (or removing it outright)
+	// must not wake a Next call already blocked on the current cancel
+	// channel - only re-arm the timer in place. Swapping the channel is
+	// only correct when the new deadline is sooner than (or the old one
+	// already passed/was never armed).
+	if !s.readDeadline.IsZero() && s.readDeadline.After(time.Now()) && (t.IsZero() || t.After(s.readDeadline)) {
+		if s.readTimer != nil {
+			s.readTimer.Stop()
+		}
+		s.readDeadline = t
+		if t.IsZero() {
+			s.readTimer = nil
+			return
+		}
+		cancelCh := s.readCancelCh
+		s.readTimer = time.AfterFunc(time.Until(t), func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.readCancelCh == cancelCh {
+				close(cancelCh)
+			}
+		})
+		return
+	}
+
+	if s.readTimer != nil {
+		s.readTimer.Stop()
+	}
+	// Swap in a fresh cancel channel and close the old one so a Next call
+	// already blocked on it wakes up immediately instead of waiting for
+	// the stale deadline. If the old deadline already fired, its channel is
+	// closed and is left as it is.
+	select {
+	case <-s.readCancelCh:
+	default:
+		close(s.readCancelCh)
+	}
+	s.readCancelCh = make(chan struct{})
+	s.readDeadline = t
+
+	if t.IsZero() {
+		s.readTimer = nil
+		return
+	}
+
+	cancelCh := s.readCancelCh
+	d := time.Until(t)
+	if d <= 0 {
+		close(cancelCh)
+		return
+	}
+	s.readTimer = time.AfterFunc(d, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.readCancelCh == cancelCh {
+			close(cancelCh)
+		}
+	})
+}
+
+// Next blocks until an item arrives, ctx is done, the current read
+// deadline fires (ErrDeadlineExceeded), or the stream ends.
+func (s *ResultStream[T]) Next(ctx context.Context) (T, error) {
+	var zero T
+
+	s.mu.Lock()
+	cancelCh := s.readCancelCh
+	s.mu.Unlock()
+
+	select {
+	case item, ok := <-s.items:
+		if !ok {
+			s.mu.Lock()
+			err := s.err
+			s.mu.Unlock()
+			if err == nil {
+				err = io.EOF
+			}
+			return zero, err
+		}
+		return item, nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case <-cancelCh:
+		return zero, ErrDeadlineExceeded
+	}
+}
+
+// Err returns the error that ended the stream, if Next has already
+// returned io.EOF (or another terminal error) once.
+func (s *ResultStream[T]) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// setErr records the error the producing goroutine ends the stream with,
+// returned by Next once items is drained and closed.
+func (s *ResultStream[T]) setErr(err error) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+// Close releases the stream's underlying request. Safe to call more than
+// once; only the first call has effect.
+func (s *ResultStream[T]) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	if s.closeFn != nil {
+		return s.closeFn()
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package credlink
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestResultStreamExtendDeadlineDoesNotWakeBlockedNext guards against
+// SetReadDeadline unconditionally closing the in-flight cancel channel: a
+// blocked Next call must not see ErrDeadlineExceeded just because the
+// deadline was replaced with a later one.
+func TestResultStreamExtendDeadlineDoesNotWakeBlockedNext(t *testing.T) {
+	items := make(chan int)
+	s := newResultStream[int](items, nil)
+
+	s.SetReadDeadline(time.Now().Add(time.Hour))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Next(context.Background())
+		done <- err
+	}()
+
+	// Give Next a moment to actually start blocking before extending.
+	time.Sleep(20 * time.Millisecond)
+	s.SetReadDeadline(time.Now().Add(2 * time.Hour))
+
+	select {
+	case err := <-done:
+		t.Fatalf("Next returned early after deadline extension: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	items <- 42
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,207 @@
+package credlink
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Retry Budget
+// ============================================================================
+
+// RetryBudget caps the rate of retry attempts - not first attempts - across
+// an entire client, as a simple token bucket refilled at maxPerSecond.
+// Without one, a broad outage makes every in-flight call retry at once,
+// multiplying load on an already-struggling dependency; the budget bounds
+// that multiplier regardless of how many calls are retrying concurrently.
+type RetryBudget struct {
+	maxPerSecond float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRetryBudget creates a budget allowing up to maxPerSecond retry
+// attempts per second, refilling continuously and capped at maxPerSecond
+// tokens of burst.
+func NewRetryBudget(maxPerSecond float64) *RetryBudget {
+	return &RetryBudget{maxPerSecond: maxPerSecond, tokens: maxPerSecond}
+}
+
+// Allow reports whether a retry attempt may proceed right now, consuming a
+// token if so. A nil budget always allows the retry, so RetryConfig.Budget
+// can be left unset with no behavior change.
+func (b *RetryBudget) Allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.maxPerSecond
+		if b.tokens > b.maxPerSecond {
+			b.tokens = b.maxPerSecond
+		}
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// ============================================================================
+// Retry Executor
+// ============================================================================
+
+// IsRetryable reports whether err is worth retrying, based on CredLink's
+// typed errors rather than raw HTTP status codes: NetworkError, ServerError,
+// and RateLimitError are retryable; AuthError, ConflictError, and
+// ValidationError never are, since retrying them against the same request
+// would just fail the same way again. Unrecognized error types are treated
+// as non-retryable.
+func IsRetryable(err error) bool {
+	switch err.(type) {
+	case *NetworkError, *ServerError, *RateLimitError:
+		return true
+	default:
+		return false
+	}
+}
+
+// DoWithRetry runs fn, retrying up to cfg.MaxAttempts additional times
+// while IsRetryable(err) holds. fn is passed the attempt number (0 for the
+// first try) so callers can tag spans or headers accordingly.
+//
+// Backoff between attempts honors a RateLimitError's (or ServerError's)
+// RetryAfter directly when set (bounded by cfg.MaxMs). Otherwise, with
+// cfg.Jitter set, it follows decorrelated jitter - sleep = min(MaxMs,
+// random(BaseMs, prev*3)) - the schedule AWS recommends for clients
+// retrying against a shared service, since it spreads out retries from
+// many clients better than exponential backoff alone; without cfg.Jitter
+// it doubles BaseMs on each attempt, capped at MaxMs. cfg.Budget, if set, can veto a retry once the client-wide retry
+// rate is exhausted, returning the last error immediately instead of
+// waiting out a delay the budget won't allow.
+//
+// The returned error's Attempts() reflects the total number of attempts
+// made, including the first.
+func DoWithRetry(ctx context.Context, cfg *RetryConfig, fn func(ctx context.Context, attempt int) (interface{}, error)) (interface{}, error) {
+	if cfg == nil {
+		cfg = DefaultRetryConfig()
+	}
+
+	delay := cfg.BaseMs
+	var lastErr error
+	attemptsMade := 0
+
+	for attempt := 0; attempt <= cfg.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			if cfg.Budget != nil && !cfg.Budget.Allow() {
+				break
+			}
+			delay = retryDelay(cfg, lastErr, delay, attempt)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		result, err := fn(ctx, attempt)
+		attemptsMade++
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+
+		if rateLimitErr, ok := lastErr.(*RateLimitError); ok {
+			count := attemptsMade
+			rateLimitErr.AttemptCount = &count
+		}
+
+		if !IsRetryable(lastErr) || attempt == cfg.MaxAttempts {
+			break
+		}
+	}
+
+	return nil, withAttempts(lastErr, attemptsMade)
+}
+
+// retryDelay computes the wait before the next attempt, given the delay
+// used before the previous one. A RateLimitError's RetryAfter (or a
+// ServerError's) overrides the computed schedule when present. Otherwise,
+// cfg.Jitter selects between decorrelated-jitter backoff (recommended for
+// clients retrying against a shared service, since it spreads out retries
+// from many clients better than synchronized exponential backoff) and a
+// deterministic exponential schedule.
+func retryDelay(cfg *RetryConfig, lastErr error, prev time.Duration, attempt int) time.Duration {
+	if rateLimitErr, ok := lastErr.(*RateLimitError); ok && rateLimitErr.RetryAfter != nil {
+		d := time.Duration(*rateLimitErr.RetryAfter) * time.Second
+		if d > cfg.MaxMs {
+			d = cfg.MaxMs
+		}
+		return d
+	}
+
+	if retryAfter, ok := retryAfterDelay(lastErr); ok {
+		if retryAfter > cfg.MaxMs {
+			retryAfter = cfg.MaxMs
+		}
+		return retryAfter
+	}
+
+	if !cfg.Jitter {
+		d := time.Duration(float64(cfg.BaseMs) * math.Pow(2, float64(attempt-1)))
+		if d > cfg.MaxMs {
+			d = cfg.MaxMs
+		}
+		return d
+	}
+
+	lo := cfg.BaseMs
+	hi := prev * 3
+	if hi < lo {
+		hi = lo
+	}
+	d := lo + time.Duration(rand.Float64()*float64(hi-lo))
+	if d > cfg.MaxMs {
+		d = cfg.MaxMs
+	}
+	return d
+}
+
+// retryAfterDelay extracts a server-specified retry delay from err, if any.
+func retryAfterDelay(err error) (time.Duration, bool) {
+	if e, ok := err.(*ServerError); ok && e.RetryAfter != nil {
+		return time.Duration(*e.RetryAfter) * time.Second, true
+	}
+	return 0, false
+}
+
+// attemptsSetter is implemented by *BaseError (and thus every CredLink
+// error type via embedding). It's unexported since only DoWithRetry needs
+// to record the final attempt count - callers read it back via the
+// exported Error.Attempts().
+type attemptsSetter interface {
+	setAttempts(n int)
+}
+
+// withAttempts records n on err's Attempts() count, if err supports it.
+func withAttempts(err error, n int) error {
+	if err == nil {
+		return nil
+	}
+	if as, ok := err.(attemptsSetter); ok {
+		as.setAttempts(n)
+	}
+	return err
+}
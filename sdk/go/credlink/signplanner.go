@@ -0,0 +1,347 @@
+package credlink
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ============================================================================
+// Resumable Folder-Sign Planning
+// ============================================================================
+
+// PlanEntry is one file's entry in a sign plan: its path relative to the
+// folder root, its content hash, and (once known) the manifest ID of an
+// existing valid signature for that exact content under the active profile.
+type PlanEntry struct {
+	RelPath    string  `json:"relpath"`
+	SHA256     string  `json:"sha256"`
+	ManifestID *string `json:"manifest_id,omitempty"`
+}
+
+// FolderSignPlannerOptions configures a FolderSignPlanner.
+type FolderSignPlannerOptions struct {
+	FolderPath   string
+	ProfileID    string
+	Recursive    bool
+	FilePatterns []string
+	// PlanFile is the NDJSON checkpoint path. Defaults to
+	// "<FolderPath>/.credlink-plan.ndjson".
+	PlanFile string
+	// LookupBatchSize bounds how many content hashes are checked against
+	// the API per CheckExisting call. Defaults to 100.
+	LookupBatchSize int
+	// SubmitBatchSize bounds how many files are grouped into one
+	// SignFolder sub-job. Defaults to 500.
+	SubmitBatchSize int
+}
+
+// FolderSignPlanner walks a folder, hashes each matching file, and figures
+// out which ones already have a valid manifest under the target profile so
+// a retro-sign job never re-signs content it's already covered, and so an
+// interrupted run can resume from the plan file instead of starting over.
+type FolderSignPlanner struct {
+	client *Client
+	opts   FolderSignPlannerOptions
+}
+
+// NewFolderSignPlanner creates a FolderSignPlanner bound to client.
+func NewFolderSignPlanner(client *Client, opts FolderSignPlannerOptions) *FolderSignPlanner {
+	if opts.PlanFile == "" {
+		opts.PlanFile = filepath.Join(opts.FolderPath, ".credlink-plan.ndjson")
+	}
+	if opts.LookupBatchSize <= 0 {
+		opts.LookupBatchSize = 100
+	}
+	if opts.SubmitBatchSize <= 0 {
+		opts.SubmitBatchSize = 500
+	}
+	return &FolderSignPlanner{client: client, opts: opts}
+}
+
+// PlanID deterministically identifies this folder+profile combination so
+// repeated runs derive the same sub-job IdempotencyKeys.
+func (p *FolderSignPlanner) PlanID() string {
+	sum := sha256.Sum256([]byte(p.opts.FolderPath + "|" + p.opts.ProfileID))
+	return hex.EncodeToString(sum[:])
+}
+
+// Build walks the folder (respecting FilePatterns/Recursive), hashes every
+// matching file, carries over any already-known manifest IDs from a prior
+// plan file for files whose content hasn't changed, and writes the
+// refreshed plan to disk.
+func (p *FolderSignPlanner) Build() ([]PlanEntry, error) {
+	existing, err := p.loadPlan()
+	if err != nil {
+		return nil, err
+	}
+	existingByPath := make(map[string]PlanEntry, len(existing))
+	for _, e := range existing {
+		existingByPath[e.RelPath] = e
+	}
+
+	var entries []PlanEntry
+	walkFn := func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if !p.opts.Recursive && path != p.opts.FolderPath {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !p.matchesPatterns(filepath.Base(path)) {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(p.opts.FolderPath, path)
+		if err != nil {
+			return err
+		}
+
+		sum, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+
+		entry := PlanEntry{RelPath: relPath, SHA256: sum}
+		if prior, ok := existingByPath[relPath]; ok && prior.SHA256 == sum {
+			entry.ManifestID = prior.ManifestID
+		}
+		entries = append(entries, entry)
+		return nil
+	}
+
+	if err := filepath.WalkDir(p.opts.FolderPath, walkFn); err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", p.opts.FolderPath, err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].RelPath < entries[j].RelPath })
+
+	if err := p.savePlan(entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (p *FolderSignPlanner) matchesPatterns(name string) bool {
+	if len(p.opts.FilePatterns) == 0 {
+		return true
+	}
+	for _, pattern := range p.opts.FilePatterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// CheckExisting queries the API in LookupBatchSize batches for every entry
+// still missing a ManifestID, filling it in (and persisting the updated
+// plan) wherever a valid manifest already exists for that content hash
+// under ProfileID.
+func (p *FolderSignPlanner) CheckExisting(ctx context.Context, entries []PlanEntry) ([]PlanEntry, error) {
+	var pending []int
+	for i, e := range entries {
+		if e.ManifestID == nil {
+			pending = append(pending, i)
+		}
+	}
+
+	for start := 0; start < len(pending); start += p.opts.LookupBatchSize {
+		end := start + p.opts.LookupBatchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		batch := pending[start:end]
+
+		hashes := make([]string, len(batch))
+		for i, idx := range batch {
+			hashes[i] = entries[idx].SHA256
+		}
+
+		found, err := p.lookupManifests(ctx, hashes)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, idx := range batch {
+			if manifestID, ok := found[hashes[i]]; ok {
+				entries[idx].ManifestID = &manifestID
+			}
+		}
+	}
+
+	if err := p.savePlan(entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// lookupManifests asks the API which of the given content hashes already
+// have a valid manifest under ProfileID, returning a hash -> manifest ID map
+// for the ones that do.
+func (p *FolderSignPlanner) lookupManifests(ctx context.Context, hashes []string) (map[string]string, error) {
+	request := map[string]interface{}{
+		"profile_id": p.opts.ProfileID,
+		"hashes":     hashes,
+	}
+
+	resp, err := p.client.transport.Request(ctx, "POST", "/manifests/lookup", request, NewRequestOptions())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data struct {
+			Manifests map[string]string `json:"manifests"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest lookup response: %w", err)
+	}
+
+	return result.Data.Manifests, nil
+}
+
+// RemainingBatches groups entries without a ManifestID into SubmitBatchSize
+// chunks, ready to be submitted as idempotent SignFolder sub-jobs.
+func (p *FolderSignPlanner) RemainingBatches(entries []PlanEntry) [][]PlanEntry {
+	var remaining []PlanEntry
+	for _, e := range entries {
+		if e.ManifestID == nil {
+			remaining = append(remaining, e)
+		}
+	}
+
+	var batches [][]PlanEntry
+	for start := 0; start < len(remaining); start += p.opts.SubmitBatchSize {
+		end := start + p.opts.SubmitBatchSize
+		if end > len(remaining) {
+			end = len(remaining)
+		}
+		batches = append(batches, remaining[start:end])
+	}
+	return batches
+}
+
+// BatchIdempotencyKey derives a deterministic idempotency key for batch
+// batchIndex of this plan, so resubmitting the same plan (e.g. after a
+// crash) never double-signs a batch the server already accepted.
+func (p *FolderSignPlanner) BatchIdempotencyKey(batchIndex int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%d", p.PlanID(), batchIndex)))
+	return hex.EncodeToString(sum[:])
+}
+
+// SubmitRemaining submits every batch from RemainingBatches as its own
+// idempotent SignFolder sub-job (using Files rather than FilePatterns, so
+// only the listed relative paths are signed) and returns their responses in
+// batch order. If a batch fails, already-submitted batches' responses are
+// still returned alongside the error.
+func (p *FolderSignPlanner) SubmitRemaining(ctx context.Context, entries []PlanEntry, tsa bool) ([]*SignFolderResponse, error) {
+	batches := p.RemainingBatches(entries)
+	responses := make([]*SignFolderResponse, 0, len(batches))
+
+	recursive := false
+	for i, batch := range batches {
+		files := make([]string, len(batch))
+		for j, e := range batch {
+			files[j] = e.RelPath
+		}
+
+		idempotencyKey := p.BatchIdempotencyKey(i)
+		resp, err := p.client.SignFolder(ctx, p.opts.FolderPath, SignFolderOptions{
+			ProfileID:      p.opts.ProfileID,
+			TSA:            &tsa,
+			Recursive:      &recursive,
+			Files:          files,
+			IdempotencyKey: &idempotencyKey,
+		})
+		if err != nil {
+			return responses, fmt.Errorf("batch %d/%d failed: %w", i+1, len(batches), err)
+		}
+		responses = append(responses, resp)
+	}
+
+	return responses, nil
+}
+
+func (p *FolderSignPlanner) loadPlan() ([]PlanEntry, error) {
+	f, err := os.Open(p.opts.PlanFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open plan file: %w", err)
+	}
+	defer f.Close()
+
+	var entries []PlanEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry PlanEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse plan file line: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read plan file: %w", err)
+	}
+
+	return entries, nil
+}
+
+// savePlan writes via a temp-file-plus-rename so a crash mid-write can't
+// corrupt the checkpoint a resumed run would rely on.
+func (p *FolderSignPlanner) savePlan(entries []PlanEntry) error {
+	tmp := p.opts.PlanFile + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create plan file: %w", err)
+	}
+
+	encoder := json.NewEncoder(f)
+	for _, e := range entries {
+		if err := encoder.Encode(e); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to write plan entry: %w", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, p.opts.PlanFile)
+}
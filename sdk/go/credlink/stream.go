@@ -0,0 +1,306 @@
+package credlink
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Streaming
+// ============================================================================
+
+// defaultMaxFrameBytes bounds a single streamed frame (NDJSON line, SSE
+// event, or length-prefixed payload) so a runaway or malicious server can't
+// stall RequestStream by growing one frame without end.
+const defaultMaxFrameBytes = 8 * 1024 * 1024 // 8 MiB
+
+// streamFraming identifies how a streamed response body is framed.
+type streamFraming int
+
+const (
+	framingNDJSON streamFraming = iota
+	framingSSE
+	framingLengthPrefix
+)
+
+// StreamEvent is one decoded frame from a streaming request. ID, Event, and
+// Retry are only populated for SSE framing (event: / id: / retry: fields);
+// NDJSON and length-prefixed frames only ever set Data.
+type StreamEvent struct {
+	// Data is the frame's decoded JSON payload.
+	Data map[string]interface{}
+	// ID is the SSE event's id: field, used as Last-Event-ID on reconnect.
+	ID string
+	// Event is the SSE event's event: field.
+	Event string
+	// Retry is the SSE event's retry: field, if the server sent one.
+	Retry time.Duration
+}
+
+// FrameTooLargeError is returned on the error channel when a single frame
+// exceeds MaxFrameBytes, instead of RequestStream silently stalling while it
+// buffers an unbounded frame.
+type FrameTooLargeError struct {
+	FrameBytes int64
+	MaxBytes   int64
+}
+
+func (e *FrameTooLargeError) Error() string {
+	return fmt.Sprintf("credlink: stream frame of %d bytes exceeds MaxFrameBytes (%d)", e.FrameBytes, e.MaxBytes)
+}
+
+// detectFraming chooses a framing strategy from the response's Content-Type.
+func detectFraming(contentType string) streamFraming {
+	ct, params := parseContentType(contentType)
+	switch {
+	case strings.HasPrefix(ct, "text/event-stream"):
+		return framingSSE
+	case ct == "application/vnd.credlink.stream+json" && params["framing"] == "length-prefix":
+		return framingLengthPrefix
+	default:
+		return framingNDJSON
+	}
+}
+
+// parseContentType splits "type/subtype; k=v; k2=v2" into the bare media
+// type and a lowercased parameter map, without pulling in mime.ParseMediaType
+// for a job this small.
+func parseContentType(contentType string) (string, map[string]string) {
+	parts := strings.Split(contentType, ";")
+	mediaType := strings.TrimSpace(parts[0])
+	params := make(map[string]string)
+	for _, part := range parts[1:] {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) == 2 {
+			params[strings.TrimSpace(kv[0])] = strings.Trim(strings.TrimSpace(kv[1]), `"`)
+		}
+	}
+	return mediaType, params
+}
+
+// RequestStream makes a streaming HTTP request and decodes the response body
+// frame-by-frame as NDJSON, SSE (text/event-stream), or length-prefixed JSON
+// (application/vnd.credlink.stream+json; framing=length-prefix), depending
+// on the response's Content-Type. Decode errors are surfaced on the returned
+// error channel rather than dropped; both channels close once the stream
+// ends, MaxFrameBytes is exceeded, or ctx is done.
+func (t *Transport) RequestStream(ctx context.Context, method, path string, body interface{}, options *RequestOptions) (<-chan StreamEvent, <-chan error, error) {
+	if options.LastEventID != "" {
+		options = options.WithHeader("Last-Event-ID", options.LastEventID)
+	}
+
+	resp, err := t.Request(ctx, method, path, body, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	maxFrameBytes := options.MaxFrameBytes
+	if maxFrameBytes <= 0 {
+		maxFrameBytes = defaultMaxFrameBytes
+	}
+
+	ch := make(chan StreamEvent, 10)
+	errCh := make(chan error, 1)
+
+	// The body's Read is not context-aware, so closing it from a watcher
+	// goroutine is what actually unblocks a read that's waiting past
+	// ctx.Done() instead of leaking until EOF.
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			resp.Body.Close()
+		case <-done:
+		}
+	}()
+
+	go func() {
+		defer close(done)
+		defer close(ch)
+		defer close(errCh)
+		defer resp.Body.Close()
+
+		framing := detectFraming(resp.Header.Get("Content-Type"))
+		switch framing {
+		case framingSSE:
+			t.streamSSE(ctx, resp, maxFrameBytes, ch, errCh, method, path, body, options)
+		case framingLengthPrefix:
+			streamLengthPrefixed(resp.Body, maxFrameBytes, ch, errCh)
+		default:
+			streamNDJSON(resp.Body, maxFrameBytes, ch, errCh)
+		}
+	}()
+
+	return ch, errCh, nil
+}
+
+// streamNDJSON decodes one JSON object per line. A malformed line surfaces
+// its error on errCh and parsing continues with the next line, rather than
+// silently dropping the frame.
+func streamNDJSON(body io.Reader, maxFrameBytes int64, ch chan<- StreamEvent, errCh chan<- error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), int(maxFrameBytes))
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var item map[string]interface{}
+		if err := json.Unmarshal(line, &item); err != nil {
+			errCh <- fmt.Errorf("credlink: decode NDJSON frame: %w", err)
+			continue
+		}
+		ch <- StreamEvent{Data: item}
+	}
+
+	if err := scanner.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			errCh <- &FrameTooLargeError{MaxBytes: maxFrameBytes}
+			return
+		}
+		errCh <- fmt.Errorf("credlink: read NDJSON stream: %w", err)
+	}
+}
+
+// streamLengthPrefixed decodes frames of [4-byte big-endian length][JSON
+// payload]. A length over maxFrameBytes ends the stream with a typed
+// FrameTooLargeError instead of attempting to buffer it.
+func streamLengthPrefixed(body io.Reader, maxFrameBytes int64, ch chan<- StreamEvent, errCh chan<- error) {
+	var lenBuf [4]byte
+	for {
+		if _, err := io.ReadFull(body, lenBuf[:]); err != nil {
+			if err != io.EOF {
+				errCh <- fmt.Errorf("credlink: read frame length: %w", err)
+			}
+			return
+		}
+
+		frameLen := int64(binary.BigEndian.Uint32(lenBuf[:]))
+		if frameLen > maxFrameBytes {
+			errCh <- &FrameTooLargeError{FrameBytes: frameLen, MaxBytes: maxFrameBytes}
+			return
+		}
+
+		payload := make([]byte, frameLen)
+		if _, err := io.ReadFull(body, payload); err != nil {
+			errCh <- fmt.Errorf("credlink: read frame payload: %w", err)
+			return
+		}
+
+		var item map[string]interface{}
+		if err := json.Unmarshal(payload, &item); err != nil {
+			errCh <- fmt.Errorf("credlink: decode length-prefixed frame: %w", err)
+			continue
+		}
+		ch <- StreamEvent{Data: item}
+	}
+}
+
+// streamSSE parses text/event-stream per the WHATWG spec's data:/event:/id:
+// fields, dispatching an event on each blank line. If the connection drops
+// mid-stream, it reconnects using the last-seen id as the Last-Event-ID
+// header for resumption, first waiting out the retry: interval if the
+// server sent one on that connection. A reconnected stream that drops again
+// is reconnected the same way.
+func (t *Transport) streamSSE(ctx context.Context, resp *http.Response, maxFrameBytes int64, ch chan<- StreamEvent, errCh chan<- error, method, path string, body interface{}, options *RequestOptions) {
+	var (
+		dataLines []string
+		eventName string
+		lastID    string
+		retry     time.Duration
+	)
+
+	dispatch := func() {
+		if len(dataLines) == 0 && eventName == "" {
+			return
+		}
+		var data map[string]interface{}
+		if len(dataLines) > 0 {
+			raw := strings.Join(dataLines, "\n")
+			if err := json.Unmarshal([]byte(raw), &data); err != nil {
+				errCh <- fmt.Errorf("credlink: decode SSE data: %w", err)
+			}
+		}
+		ch <- StreamEvent{Data: data, ID: lastID, Event: eventName, Retry: retry}
+		dataLines = nil
+		eventName = ""
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), int(maxFrameBytes))
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			dispatch()
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "data":
+			dataLines = append(dataLines, value)
+		case "event":
+			eventName = value
+		case "id":
+			lastID = value
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil {
+				retry = time.Duration(ms) * time.Millisecond
+			}
+		case "":
+			// Comment line, ignored per spec.
+		}
+	}
+	dispatch()
+
+	err := scanner.Err()
+	if err == bufio.ErrTooLong {
+		errCh <- &FrameTooLargeError{MaxBytes: maxFrameBytes}
+		return
+	}
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+
+	// Transient disconnect: reconnect once with Last-Event-ID so the server
+	// can resume from where this client left off.
+	reconnectOpts := *options
+	reconnectOpts.LastEventID = lastID
+	reconnectOpts.Headers = cloneHeaders(options.Headers)
+	reconnectOpts.Headers["Last-Event-ID"] = lastID
+
+	if retry > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+	}
+
+	reconnectResp, reqErr := t.Request(ctx, method, path, body, &reconnectOpts)
+	if reqErr != nil {
+		errCh <- fmt.Errorf("credlink: SSE reconnect: %w", reqErr)
+		return
+	}
+	defer reconnectResp.Body.Close()
+	t.streamSSE(ctx, reconnectResp, maxFrameBytes, ch, errCh, method, path, body, &reconnectOpts)
+}
+
+func cloneHeaders(h map[string]string) map[string]string {
+	out := make(map[string]string, len(h)+1)
+	for k, v := range h {
+		out[k] = v
+	}
+	return out
+}
@@ -6,13 +6,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"math"
-	"math/rand"
 	"net/http"
 	"strconv"
 	"time"
 
-	"github.com/google/uuid"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
 )
 
 // ============================================================================
@@ -21,10 +20,17 @@ import (
 
 // Transport handles HTTP communication with retry and circuit breaker
 type Transport struct {
-	config           *Config
-	httpClient       *http.Client
-	circuitBreaker   *CircuitBreaker
-	telemetryEnabled bool
+	config     *Config
+	httpClient *http.Client
+	// circuitBreakers hands out one breaker per (method, endpoint), so a
+	// failing endpoint trips its own breaker instead of starving requests
+	// to unrelated, healthy endpoints.
+	circuitBreakers *CircuitBreakerRegistry
+	telemetry       *TelemetryManager
+	// authProvider supplies the X-API-Key value for every request. Defaults
+	// to a StaticAPIKeyProvider wrapping config.APIKey when config.AuthProvider
+	// is nil.
+	authProvider AuthProvider
 }
 
 // NewTransport creates a new HTTP transport
@@ -33,29 +39,83 @@ func NewTransport(config *Config) *Transport {
 		panic(err)
 	}
 
-	transport := &Transport{
-		config: config,
-		httpClient: &http.Client{
-			Timeout: config.TimeoutMs,
-		},
-		circuitBreaker: NewCircuitBreaker("http-transport"),
+	httpClient := &http.Client{
+		Timeout: config.TimeoutMs,
+	}
+
+	authProvider := config.AuthProvider
+	if authProvider == nil {
+		authProvider = NewStaticAPIKeyProvider(config.APIKey)
 	}
 
-	if config.Telemetry != nil {
-		transport.telemetryEnabled = config.Telemetry.Enabled
+	transport := &Transport{
+		config:          config,
+		httpClient:      httpClient,
+		circuitBreakers: NewCircuitBreakerRegistry(config.Retries),
+		telemetry:       NewTelemetryManager(config.Telemetry, httpClient),
+		authProvider:    authProvider,
+	}
+	transport.circuitBreakers.OnStateChange = func(endpoint, from, to string) {
+		transport.telemetry.RecordMetric("circuit_breaker.state_change", 1, map[string]interface{}{
+			"circuit_breaker": endpoint,
+			"from":            from,
+			"to":              to,
+		})
 	}
 
 	return transport
 }
 
-// Request makes an HTTP request with retry and circuit breaker
+// Telemetry returns the transport's TelemetryManager, shared with Client so
+// both layers export spans/metrics to the same OTLP batch queue.
+func (t *Transport) Telemetry() *TelemetryManager {
+	return t.telemetry
+}
+
+// Request makes an HTTP request with retry and circuit breaker, forcing an
+// auth token refresh and retrying once if the first attempt fails with an
+// AuthError (401 or 403) - IsRetryable deliberately excludes AuthError from
+// DoWithRetry's normal retry loop, since retrying with the same stale token
+// would just fail again.
 func (t *Transport) Request(ctx context.Context, method, path string, body interface{}, options *RequestOptions) (*http.Response, error) {
+	resp, err := t.requestAttempt(ctx, method, path, body, options)
+	if err == nil {
+		return resp, nil
+	}
+
+	if _, ok := err.(*AuthError); ok {
+		if refresher, ok := t.authProvider.(interface {
+			ForceRefresh(context.Context) error
+		}); ok {
+			if refreshErr := refresher.ForceRefresh(ctx); refreshErr == nil {
+				return t.requestAttempt(ctx, method, path, body, options)
+			}
+		}
+	}
+
+	return nil, err
+}
+
+// requestAttempt makes a single HTTP request with retry and circuit
+// breaker. requestID and idempotencyKey are generated once here, before
+// DoWithRetry's loop, so every attempt for a given call - including
+// circuit-breaker half-open probes - reuses the same X-Request-ID and
+// Idempotency-Key headers.
+func (t *Transport) requestAttempt(ctx context.Context, method, path string, body interface{}, options *RequestOptions) (*http.Response, error) {
 	requestID := t.generateRequestID()
 	idempotencyKey := options.IdempotencyKey
 	if idempotencyKey == "" {
 		idempotencyKey = t.generateIdempotencyKey()
 	}
 
+	span := t.telemetry.CreateSpan("http.request", map[string]interface{}{
+		"http.method":              method,
+		"http.url":                 t.config.BaseURL + path,
+		"credlink.request_id":      requestID,
+		"credlink.idempotency_key": idempotencyKey,
+	})
+	defer span.End()
+
 	var bodyReader io.Reader
 	if body != nil {
 		bodyBytes, err := json.Marshal(body)
@@ -70,12 +130,17 @@ func (t *Transport) Request(ctx context.Context, method, path string, body inter
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	token, _, err := t.authProvider.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("resolve auth token: %w", err)
+	}
+
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", t.config.UserAgent)
-	req.Header.Set("X-API-Key", t.config.APIKey)
+	req.Header.Set("X-API-Key", token)
 	req.Header.Set("X-Request-ID", requestID)
-	
+
 	if idempotencyKey != "" {
 		req.Header.Set("Idempotency-Key", idempotencyKey)
 	}
@@ -98,152 +163,41 @@ func (t *Transport) Request(ctx context.Context, method, path string, body inter
 		Timeout: timeout,
 	}
 
-	isRetryable := t.createRetryPredicate(method)
-
-	var result *http.Response
-	var lastErr error
-
-	_, err = t.circuitBreaker.Execute(func() (interface{}, error) {
-		return nil, t.executeWithRetry(client, req, isRetryable, func(resp *http.Response, err error) error {
-			if err != nil {
-				lastErr = err
-				return err
-			}
-			
-			if resp.StatusCode >= 400 {
-				lastErr = t.handleHTTPError(resp, requestID, path, idempotencyKey)
-				return lastErr
-			}
-			
-			result = resp
-			return nil
-		})
-	})
-
-	if err != nil {
-		return nil, lastErr
+	retryConfig := options.Retries
+	if retryConfig == nil {
+		retryConfig = t.config.Retries
 	}
 
-	return result, nil
-}
-
-// RequestStream makes a streaming HTTP request
-func (t *Transport) RequestStream(ctx context.Context, method, path string, body interface{}, options *RequestOptions) (<-chan map[string]interface{}, error) {
-	resp, err := t.Request(ctx, method, path, body, options)
-	if err != nil {
-		return nil, err
-	}
-
-	ch := make(chan map[string]interface{}, 10) // Buffered channel
-
-	go func() {
-		defer resp.Body.Close()
-		defer close(ch)
-
-		decoder := json.NewDecoder(resp.Body)
-		
-		for {
-			var item map[string]interface{}
-			if err := decoder.Decode(&item); err != nil {
-				if err == io.EOF {
-					break
-				}
-				// Skip malformed JSON and continue
-				continue
+	cb := t.circuitBreakers.Get(method, path)
+	resp, err := cb.Execute(ctx, func(callCtx context.Context) (interface{}, error) {
+		return DoWithRetry(callCtx, retryConfig, func(attemptCtx context.Context, attempt int) (interface{}, error) {
+			if attempt > 0 {
+				span.AddEvent("retry", map[string]interface{}{"attempt": attempt})
 			}
-			ch <- item
-		}
-	}()
-
-	return ch, nil
-}
 
-// executeWithRetry executes request with retry logic
-func (t *Transport) executeWithRetry(client *http.Client, req *http.Request, isRetryable func(error) bool, callback func(*http.Response, error) error) error {
-	maxAttempts := t.config.Retries.MaxAttempts
-	var lastErr error
-
-	for attempt := 0; attempt <= maxAttempts; attempt++ {
-		if attempt > 0 {
-			delay := t.calculateDelay(attempt)
-			select {
-			case <-req.Context().Done():
-				return req.Context().Err()
-			case <-time.After(delay):
+			httpResp, doErr := client.Do(req.WithContext(attemptCtx))
+			if doErr != nil {
+				netErr := NewNetworkError(doErr.Error())
+				netErr.requestID = requestID
+				netErr.endpoint = path
+				return nil, netErr
 			}
-		}
 
-		resp, err := client.Do(req)
-		if callbackErr := callback(resp, err); callbackErr != nil {
-			lastErr = callbackErr
-			
-			if attempt == maxAttempts || !isRetryable(lastErr) {
-				return lastErr
-			}
-			
-			// Add attempt count to error if it's a RateLimitError
-			if rateLimitErr, ok := lastErr.(*RateLimitError); ok {
-				attemptCount := attempt + 1
-				rateLimitErr.AttemptCount = &attemptCount
+			span.SetAttribute("http.status_code", httpResp.StatusCode)
+			if httpResp.StatusCode >= 400 {
+				return nil, t.handleHTTPError(httpResp, requestID, path, idempotencyKey)
 			}
-			
-			continue
-		}
-		
-		return nil
-	}
 
-	return lastErr
-}
+			return httpResp, nil
+		})
+	})
 
-// calculateDelay calculates delay for retry attempt with jitter
-func (t *Transport) calculateDelay(attempt int) time.Duration {
-	baseMs := t.config.Retries.BaseMs
-	maxMs := t.config.Retries.MaxMs
-	jitter := t.config.Retries.Jitter
-
-	// Exponential backoff: base * 2^(attempt-1)
-	exponentialDelay := time.Duration(float64(baseMs) * math.Pow(2, float64(attempt-1)))
-	
-	if jitter {
-		// Full jitter: random between 0 and exponentialDelay
-		delay := time.Duration(rand.Float64() * float64(exponentialDelay))
-		if delay > maxMs {
-			delay = maxMs
-		}
-		return delay
-	}
-	
-	if exponentialDelay > maxMs {
-		exponentialDelay = maxMs
+	if err != nil {
+		span.SetError()
+		return nil, err
 	}
-	
-	return exponentialDelay
-}
 
-// createRetryPredicate creates a function to determine if error is retryable
-func (t *Transport) createRetryPredicate(method string) func(error) bool {
-	return func(err error) bool {
-		// Don't retry validation errors, auth errors, or conflicts
-		switch err.(type) {
-		case *ValidationError, *AuthError, *ConflictError:
-			return false
-		}
-
-		// Retry rate limit errors, server errors, and network errors
-		switch err.(type) {
-		case *RateLimitError, *ServerError, *NetworkError:
-			return true
-		}
-
-		// Check for specific HTTP status codes
-		if httpErr, ok := err.(interface{ StatusCode() int }); ok {
-			status := httpErr.StatusCode()
-			return status == 408 || status == 429 || status == 500 || status == 502 || status == 503 || status == 504
-		}
-
-		return false
-	}
+	return resp.(*http.Response), nil
 }
 
 // handleHTTPError handles HTTP error responses
@@ -265,31 +219,25 @@ func (t *Transport) handleHTTPError(resp *http.Response, requestID, path, idempo
 	}
 
 	hint, _ := errorData["hint"].(string)
-	retryAfterHeader := resp.Header.Get("Retry-After")
-	var retryAfter *int
-	if retryAfterHeader != "" {
-		if seconds, err := strconv.Atoi(retryAfterHeader); err == nil {
-			retryAfter = &seconds
-		}
-	}
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
 
 	switch resp.StatusCode {
 	case 401:
-		err := NewAuthError(message)
+		err := NewAuthErrorForProvider(message, t.authProvider)
 		err.requestID = requestID
 		err.endpoint = path
-		err.hint = hint
-		if err.hint == "" {
-			err.hint = "Check your API key in the X-API-Key header"
+		if hint != "" {
+			err.hint = hint
 		}
 		return err
 
 	case 403:
-		err := NewAuthError(message)
+		err := NewAuthErrorForProvider(message, t.authProvider)
 		err.requestID = requestID
 		err.endpoint = path
-		err.hint = hint
-		if err.hint == "" {
+		if hint != "" {
+			err.hint = hint
+		} else {
 			err.hint = "Insufficient permissions for this operation"
 		}
 		return err
@@ -317,7 +265,7 @@ func (t *Transport) handleHTTPError(resp *http.Response, requestID, path, idempo
 		return err
 
 	case 500, 502, 503, 504:
-		err := NewServerError(message)
+		err := NewServerErrorWithRetryAfter(message, retryAfter)
 		err.requestID = requestID
 		err.endpoint = path
 		err.hint = hint
@@ -332,19 +280,58 @@ func (t *Transport) handleHTTPError(resp *http.Response, requestID, path, idempo
 	}
 }
 
-// generateRequestID generates a unique request ID
+// parseRetryAfter parses a Retry-After header per RFC 7231: either a number
+// of seconds, or an HTTP-date to convert into a relative delay.
+func parseRetryAfter(header string) *int {
+	if header == "" {
+		return nil
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return &seconds
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		seconds := int(time.Until(t).Seconds())
+		if seconds < 0 {
+			seconds = 0
+		}
+		return &seconds
+	}
+	return nil
+}
+
+// generateRequestID generates a unique request ID via config.IDGenerator.
 func (t *Transport) generateRequestID() string {
-	return fmt.Sprintf("req_%d_%d", time.Now().Unix(), rand.Intn(10000))
+	return t.config.IDGenerator.RequestID()
 }
 
-// generateIdempotencyKey generates a unique idempotency key
+// generateIdempotencyKey generates a unique idempotency key via
+// config.IDGenerator.
 func (t *Transport) generateIdempotencyKey() string {
-	return uuid.New().String()
+	return t.config.IDGenerator.IdempotencyKey()
 }
 
-// GetCircuitBreakerState returns the current circuit breaker state
+// GetCircuitBreakerState returns the worst-case state across every endpoint
+// breaker currently registered (open, if any is open; else half-open, if
+// any is half-open; else closed), since callers asking "is the transport
+// healthy" care about the worst endpoint, not any one in particular.
 func (t *Transport) GetCircuitBreakerState() string {
-	return t.circuitBreaker.GetState()
+	state := "closed"
+	for _, snap := range t.circuitBreakers.Snapshot() {
+		switch snap.State {
+		case "open":
+			return "open"
+		case "half-open":
+			state = "half-open"
+		}
+	}
+	return state
+}
+
+// GetCircuitBreakerSnapshots returns a point-in-time snapshot of every
+// endpoint breaker's state and rolling-window totals, suitable for
+// exporting through Config.Telemetry to a Prometheus scrape handler.
+func (t *Transport) GetCircuitBreakerSnapshots() []CircuitBreakerSnapshot {
+	return t.circuitBreakers.Snapshot()
 }
 
 // Close closes the HTTP client and cleans up resources
@@ -354,44 +341,72 @@ func (t *Transport) Close() {
 }
 
 // ============================================================================
-// Telemetry Manager (simplified for Go)
+// Telemetry Manager
 // ============================================================================
 
-// TelemetryManager manages telemetry (placeholder implementation)
+// TelemetryManager creates spans and records metrics, exporting both to an
+// OTLP/HTTP collector via exporter when enabled.
 type TelemetryManager struct {
-	enabled bool
-	config  *TelemetryConfig
+	enabled  bool
+	config   *TelemetryConfig
+	exporter *otlpExporter
 }
 
-// NewTelemetryManager creates a new telemetry manager
-func NewTelemetryManager(config *TelemetryConfig) *TelemetryManager {
+// NewTelemetryManager creates a new telemetry manager. httpClient is cloned
+// (its underlying http.Transport, specifically) for the OTLP exporter so TLS
+// and proxy overrides configured on the SDK's own client apply to exports.
+func NewTelemetryManager(config *TelemetryConfig, httpClient *http.Client) *TelemetryManager {
 	enabled := config != nil && config.Enabled
-	return &TelemetryManager{
+	tm := &TelemetryManager{
 		enabled: enabled,
 		config:  config,
 	}
+	if enabled {
+		tm.exporter = newOTLPExporter(config, httpClient)
+	}
+	return tm
 }
 
-// CreateSpan creates a span for tracing (placeholder)
+// CreateSpan starts a span. Spans are flushed to the OTLP collector in
+// batches by the exporter once End() is called; the caller must always call
+// End(), typically via defer.
 func (tm *TelemetryManager) CreateSpan(name string, attributes map[string]interface{}) *Span {
 	if !tm.enabled {
 		return &Span{enabled: false}
 	}
 	return &Span{
-		enabled:   true,
-		name:      name,
+		enabled:    true,
+		name:       name,
 		attributes: attributes,
-		startTime: time.Now(),
+		startTime:  time.Now(),
+		traceID:    newTraceID(),
+		spanID:     newSpanID(),
+		exporter:   tm.exporter,
 	}
 }
 
-// RecordMetric records a metric (placeholder)
+// RecordMetric records a gauge metric, exported to the OTLP collector's
+// /v1/metrics endpoint in the same batches as spans.
 func (tm *TelemetryManager) RecordMetric(name string, value float64, attributes map[string]interface{}) {
 	if !tm.enabled {
 		return
 	}
-	// In a full implementation, this would use OpenTelemetry metrics
-	fmt.Printf("Metric: %s = %f %v\n", name, value, attributes)
+	now := time.Now()
+	metric := &metricspb.Metric{
+		Name: name,
+		Data: &metricspb.Metric_Gauge{
+			Gauge: &metricspb.Gauge{
+				DataPoints: []*metricspb.NumberDataPoint{
+					{
+						Attributes:   toAttributes(attributes),
+						TimeUnixNano: uint64(now.UnixNano()),
+						Value:        &metricspb.NumberDataPoint_AsDouble{AsDouble: value},
+					},
+				},
+			},
+		},
+	}
+	tm.exporter.enqueueMetric(metric)
 }
 
 // IsEnabled returns whether telemetry is enabled
@@ -399,12 +414,27 @@ func (tm *TelemetryManager) IsEnabled() bool {
 	return tm.enabled
 }
 
-// Span represents a tracing span (placeholder implementation)
+// Close signals the export loop to flush any buffered spans/metrics and
+// stop; it returns without waiting for that final export to finish.
+func (tm *TelemetryManager) Close() {
+	if tm.enabled && tm.exporter != nil {
+		tm.exporter.close()
+	}
+}
+
+// Span represents an in-flight OTLP trace span. SetAttribute/AddEvent are
+// safe to call on a disabled span (e.g. when telemetry is off) - they're
+// simply no-ops.
 type Span struct {
 	enabled    bool
 	name       string
 	attributes map[string]interface{}
+	events     []*tracepb.Span_Event
 	startTime  time.Time
+	traceID    []byte
+	spanID     []byte
+	statusCode tracepb.Status_StatusCode
+	exporter   *otlpExporter
 }
 
 // SetAttribute sets an attribute on the span
@@ -418,13 +448,46 @@ func (s *Span) SetAttribute(key string, value interface{}) {
 	s.attributes[key] = value
 }
 
-// End ends the span
+// AddEvent records a timestamped event on the span, e.g. a retry attempt or
+// a circuit-breaker state transition, so operators can see them inline in
+// the trace instead of as separate printf output.
+func (s *Span) AddEvent(name string, attributes map[string]interface{}) {
+	if !s.enabled {
+		return
+	}
+	s.events = append(s.events, &tracepb.Span_Event{
+		TimeUnixNano: uint64(time.Now().UnixNano()),
+		Name:         name,
+		Attributes:   toAttributes(attributes),
+	})
+}
+
+// SetError marks the span as failed, per OTLP's status code convention.
+func (s *Span) SetError() {
+	if !s.enabled {
+		return
+	}
+	s.statusCode = tracepb.Status_STATUS_CODE_ERROR
+}
+
+// End finalizes the span and enqueues it on the exporter's batch queue; it
+// does not make an HTTP call itself.
 func (s *Span) End() {
 	if !s.enabled {
 		return
 	}
-	duration := time.Since(s.startTime)
-	fmt.Printf("Span: %s completed in %v\n", s.name, duration)
+	end := time.Now()
+	s.exporter.enqueueSpan(&tracepb.Span{
+		TraceId:           s.traceID,
+		SpanId:            s.spanID,
+		Name:              s.name,
+		Kind:              tracepb.Span_SPAN_KIND_CLIENT,
+		StartTimeUnixNano: uint64(s.startTime.UnixNano()),
+		EndTimeUnixNano:   uint64(end.UnixNano()),
+		Attributes:        toAttributes(s.attributes),
+		Events:            s.events,
+		Status:            &tracepb.Status{Code: s.statusCode},
+	})
 }
 
 // ============================================================================
@@ -433,10 +496,16 @@ func (s *Span) End() {
 
 // RequestOptions defines options for individual requests
 type RequestOptions struct {
-	Timeout          time.Duration
-	IdempotencyKey   string
-	Headers          map[string]string
-	Retries          *RetryConfig
+	Timeout        time.Duration
+	IdempotencyKey string
+	Headers        map[string]string
+	Retries        *RetryConfig
+	// MaxFrameBytes bounds a single RequestStream frame. Zero uses
+	// defaultMaxFrameBytes (8 MiB).
+	MaxFrameBytes int64
+	// LastEventID seeds the Last-Event-ID header for SSE resumption, e.g.
+	// when a caller is resuming a stream that previously disconnected.
+	LastEventID string
 }
 
 // NewRequestOptions creates new request options with defaults
@@ -472,3 +541,16 @@ func (ro *RequestOptions) WithRetries(retries *RetryConfig) *RequestOptions {
 	ro.Retries = retries
 	return ro
 }
+
+// WithMaxFrameBytes bounds a single RequestStream frame, overriding
+// defaultMaxFrameBytes.
+func (ro *RequestOptions) WithMaxFrameBytes(maxFrameBytes int64) *RequestOptions {
+	ro.MaxFrameBytes = maxFrameBytes
+	return ro
+}
+
+// WithLastEventID seeds the Last-Event-ID header for SSE resumption.
+func (ro *RequestOptions) WithLastEventID(id string) *RequestOptions {
+	ro.LastEventID = id
+	return ro
+}
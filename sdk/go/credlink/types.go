@@ -1,6 +1,7 @@
 package credlink
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -23,6 +24,10 @@ type RetryConfig struct {
 	BaseMs      time.Duration `json:"base_ms"`
 	MaxMs       time.Duration `json:"max_ms"`
 	Jitter      bool          `json:"jitter"`
+	// Budget, if set, caps the rate of retry attempts across every request
+	// sharing this RetryConfig, to prevent a broad outage from turning into
+	// a retry storm. Unset by default - no client-wide cap.
+	Budget *RetryBudget `json:"-"`
 }
 
 // DefaultRetryConfig returns default retry configuration
@@ -35,36 +40,81 @@ func DefaultRetryConfig() *RetryConfig {
 	}
 }
 
-// TelemetryConfig defines telemetry settings
+// TelemetryConfig defines telemetry settings. OTel holds OTLP/HTTP exporter
+// overrides: "endpoint" (defaults to OTEL_EXPORTER_OTLP_ENDPOINT, then
+// http://localhost:4318), "compression" ("gzip" to enable it), "batch_size",
+// and "batch_interval_ms".
 type TelemetryConfig struct {
-	Enabled bool            `json:"enabled"`
+	Enabled bool              `json:"enabled"`
 	OTel    map[string]string `json:"otel,omitempty"`
 }
 
 // Config defines client configuration
 type Config struct {
-	APIKey     string            `json:"api_key"`
-	BaseURL    string            `json:"base_url"`
-	TimeoutMs  time.Duration     `json:"timeout_ms"`
-	Telemetry  *TelemetryConfig  `json:"telemetry,omitempty"`
-	Retries    *RetryConfig      `json:"retries,omitempty"`
-	UserAgent  string            `json:"user_agent,omitempty"`
+	APIKey    string           `json:"api_key"`
+	BaseURL   string           `json:"base_url"`
+	TimeoutMs time.Duration    `json:"timeout_ms"`
+	Telemetry *TelemetryConfig `json:"telemetry,omitempty"`
+	Retries   *RetryConfig     `json:"retries,omitempty"`
+	UserAgent string           `json:"user_agent,omitempty"`
+	// IDGenerator produces request IDs and idempotency keys. Defaults to
+	// NewDefaultIDGenerator(); inject a deterministic implementation in
+	// tests that assert on specific ID values.
+	IDGenerator IDGenerator `json:"-"`
+	// ManifestCache, when set, lets GetManifest/PutManifest consult and
+	// populate a local (or remote) store instead of always round-tripping
+	// to the API. Unset by default - no caching unless a caller opts in
+	// with FSManifestCache or their own ManifestCache implementation.
+	ManifestCache ManifestCache `json:"-"`
+	// Notifications configures webhook endpoints that receive a
+	// NotificationEvent for every verify/sign/inject/manifest.put decision,
+	// so a SIEM/audit pipeline can consume them instead of polling
+	// GetJobStatus. Empty by default - no notifier is created.
+	Notifications []EndpointConfig `json:"notifications,omitempty"`
+	// AuthProvider, when set, supplies the request credential in place of
+	// a fixed APIKey - e.g. a RefreshableAuthProvider kept fresh by a
+	// background LifetimeWatcher. Nil by default, in which case APIKey is
+	// wrapped in a StaticAPIKeyProvider.
+	AuthProvider AuthProvider `json:"-"`
+	// AuthRenewGrace is how far ahead of a RefreshableAuthProvider token's
+	// expiry the LifetimeWatcher renews it. Defaults to 1 minute.
+	AuthRenewGrace time.Duration `json:"auth_renew_grace_ms,omitempty"`
+	// OIDC, when set, configures OIDC or workload-identity authentication
+	// as an alternative to APIKey - e.g. for CI jobs and cluster workloads
+	// that shouldn't hold a long-lived key. Validate builds the
+	// corresponding AuthProvider the first time it runs. Mutually
+	// exclusive with APIKey and with an explicitly set AuthProvider.
+	OIDC *OIDCConfig `json:"oidc,omitempty"`
 }
 
 // DefaultConfig returns default configuration
 func DefaultConfig() *Config {
 	return &Config{
-		BaseURL:   DefaultURL,
-		TimeoutMs: 30 * time.Second,
-		Retries:   DefaultRetryConfig(),
-		UserAgent: UserAgent,
+		BaseURL:     DefaultURL,
+		TimeoutMs:   30 * time.Second,
+		Retries:     DefaultRetryConfig(),
+		UserAgent:   UserAgent,
+		IDGenerator: NewDefaultIDGenerator(),
 	}
 }
 
 // Validate validates the configuration
 func (c *Config) Validate() error {
-	if strings.TrimSpace(c.APIKey) == "" {
-		return fmt.Errorf("API key is required")
+	if c.OIDC != nil && strings.TrimSpace(c.APIKey) != "" {
+		return fmt.Errorf("ambiguous auth configuration: set either APIKey or OIDC, not both")
+	}
+	if c.OIDC != nil && c.AuthProvider != nil {
+		return fmt.Errorf("ambiguous auth configuration: set either AuthProvider or OIDC, not both")
+	}
+	if strings.TrimSpace(c.APIKey) == "" && c.AuthProvider == nil && c.OIDC == nil {
+		return fmt.Errorf("API key, OIDC configuration, or AuthProvider is required")
+	}
+	if c.OIDC != nil {
+		provider, err := newOIDCAuthProvider(c.OIDC)
+		if err != nil {
+			return fmt.Errorf("configure OIDC auth: %w", err)
+		}
+		c.AuthProvider = provider
 	}
 	if c.BaseURL == "" {
 		c.BaseURL = DefaultURL
@@ -78,6 +128,12 @@ func (c *Config) Validate() error {
 	if c.UserAgent == "" {
 		c.UserAgent = UserAgent
 	}
+	if c.IDGenerator == nil {
+		c.IDGenerator = NewDefaultIDGenerator()
+	}
+	if c.AuthRenewGrace == 0 {
+		c.AuthRenewGrace = time.Minute
+	}
 	return nil
 }
 
@@ -98,19 +154,24 @@ type Error interface {
 	Timestamp() time.Time
 	Summary() string
 	NextSteps() []string
+	// Attempts returns how many attempts DoWithRetry made before returning
+	// this error, including the first. Zero if the error never passed
+	// through the retry engine.
+	Attempts() int
 }
 
 // BaseError implements the Error interface
 type BaseError struct {
-	message         string
-	code            string
-	statusCode      int
-	requestID       string
-	idempotencyKey  string
-	endpoint        string
-	hint            string
-	docsURL         string
-	timestamp       time.Time
+	message        string
+	code           string
+	statusCode     int
+	requestID      string
+	idempotencyKey string
+	endpoint       string
+	hint           string
+	docsURL        string
+	timestamp      time.Time
+	attempts       int
 }
 
 // NewBaseError creates a new base error
@@ -169,6 +230,19 @@ func (e *BaseError) Timestamp() time.Time {
 	return e.timestamp
 }
 
+// Attempts returns how many attempts DoWithRetry made before returning
+// this error, including the first.
+func (e *BaseError) Attempts() int {
+	return e.attempts
+}
+
+// setAttempts records the final attempt count. Unexported: only
+// DoWithRetry (same package) needs to set it; callers read it back via
+// Attempts.
+func (e *BaseError) setAttempts(n int) {
+	e.attempts = n
+}
+
 // Summary returns a search-engine friendly summary
 func (e *BaseError) Summary() string {
 	return fmt.Sprintf("C2C %s: %d - %s", e.code, e.statusCode, e.message)
@@ -185,22 +259,75 @@ func (e *BaseError) NextSteps() []string {
 // AuthError represents authentication errors (401/403)
 type AuthError struct {
 	*BaseError
+	// mode is which AuthProvider produced the credential that was
+	// rejected - "api_key", "oidc", or "workload_identity" - so NextSteps
+	// can point at the right thing to check. Defaults to "api_key".
+	mode string
 }
 
-// NewAuthError creates a new authentication error
+// NewAuthError creates a new authentication error for the default API-key
+// auth mode. Transport uses NewAuthErrorForProvider instead, so its
+// NextSteps can branch on whichever AuthProvider is actually configured.
 func NewAuthError(message string) *AuthError {
 	err := NewBaseError(message, "AUTH_ERROR", 401)
 	err.hint = "Check your API key in the X-API-Key header"
-	return &AuthError{BaseError: err}
+	return &AuthError{BaseError: err, mode: "api_key"}
 }
 
-// NextSteps returns authentication-specific next steps
+// NewAuthErrorForProvider creates an authentication error whose hint and
+// NextSteps are tailored to provider, so a 401 from an OIDC or
+// workload-identity setup doesn't tell the caller to check an X-API-Key
+// header it never sent.
+func NewAuthErrorForProvider(message string, provider AuthProvider) *AuthError {
+	mode := authModeName(provider)
+	err := NewBaseError(message, "AUTH_ERROR", 401)
+	switch mode {
+	case "oidc":
+		err.hint = "Check OIDC_CLIENT_ID and OIDC_ISSUER, and that the token hasn't been revoked"
+	case "workload_identity":
+		err.hint = "Check that the workload identity token file is present and not expired"
+	default:
+		err.hint = "Check your API key in the X-API-Key header"
+	}
+	return &AuthError{BaseError: err, mode: mode}
+}
+
+// authModeName reports which auth mode produced provider's credential, for
+// AuthError's mode-specific guidance. RefreshableAuthProvider.Mode is set
+// by NewOIDCAuthCodeProvider and NewWorkloadIdentityAuthProvider; anything
+// else - including a StaticAPIKeyProvider - is treated as "api_key".
+func authModeName(provider AuthProvider) string {
+	if rap, ok := provider.(*RefreshableAuthProvider); ok && rap.Mode != "" {
+		return rap.Mode
+	}
+	return "api_key"
+}
+
+// NextSteps returns authentication-specific next steps, tailored to which
+// auth mode produced the rejected credential.
 func (e *AuthError) NextSteps() []string {
-	return []string{
-		"Verify your API key is correct",
-		"Check the X-API-Key header format",
-		"Ensure your API key is active and not expired",
-		"Contact support if the issue persists",
+	switch e.mode {
+	case "oidc":
+		return []string{
+			"Verify OIDC_CLIENT_ID and OIDC_ISSUER are correct",
+			"Check that the refresh token hasn't been revoked",
+			"Re-run the interactive login if the refresh token has expired",
+			"Contact support if the issue persists",
+		}
+	case "workload_identity":
+		return []string{
+			"Verify the workload identity token file path is correct and readable",
+			"Check that the projected token hasn't expired",
+			"Confirm the workload identity is trusted by the OIDC issuer",
+			"Contact support if the issue persists",
+		}
+	default:
+		return []string{
+			"Verify your API key is correct",
+			"Check the X-API-Key header format",
+			"Ensure your API key is active and not expired",
+			"Contact support if the issue persists",
+		}
 	}
 }
 
@@ -237,11 +364,11 @@ func (e *RateLimitError) NextSteps() []string {
 		"Honor the Retry-After header if provided",
 		"Consider reducing request frequency",
 	}
-	
+
 	if e.RetryAfter != nil {
 		steps = append(steps, fmt.Sprintf("Wait %d seconds before retrying", *e.RetryAfter))
 	}
-	
+
 	steps = append(steps, "Contact support for rate limit increases")
 	return steps
 }
@@ -302,6 +429,10 @@ func (e *ValidationError) NextSteps() []string {
 // ServerError represents server errors (5xx)
 type ServerError struct {
 	*BaseError
+	// RetryAfter is how long the caller should wait before retrying, in
+	// seconds, set from the response's Retry-After header. A circuit-breaker
+	// trip is reported as a CircuitOpenError, not a ServerError.
+	RetryAfter *int
 }
 
 // NewServerError creates a new server error
@@ -311,6 +442,15 @@ func NewServerError(message string) *ServerError {
 	return &ServerError{BaseError: err}
 }
 
+// NewServerErrorWithRetryAfter creates a server error that carries an
+// explicit retry delay, e.g. a 5xx response's Retry-After header, so callers
+// above the SDK can throttle correctly.
+func NewServerErrorWithRetryAfter(message string, retryAfter *int) *ServerError {
+	err := NewServerError(message)
+	err.RetryAfter = retryAfter
+	return err
+}
+
 // NextSteps returns server error-specific next steps
 func (e *ServerError) NextSteps() []string {
 	return []string{
@@ -321,6 +461,36 @@ func (e *ServerError) NextSteps() []string {
 	}
 }
 
+// CircuitOpenError is returned by CircuitBreaker.Execute when the breaker
+// is open (or its half-open probe slots are full) and fn was never called.
+type CircuitOpenError struct {
+	*BaseError
+	// NextAttemptTime is when the breaker will next allow a half-open
+	// probe.
+	NextAttemptTime time.Time
+}
+
+// NewCircuitOpenError creates a circuit-open error carrying the breaker's
+// name and its remaining cool-down, encoded as a Retry-After-style hint.
+func NewCircuitOpenError(name string, nextAttemptTime time.Time) *CircuitOpenError {
+	retryAfter := int(time.Until(nextAttemptTime).Seconds())
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+	err := NewBaseError(fmt.Sprintf("circuit breaker '%s' is open", name), "CIRCUIT_OPEN", 503)
+	err.hint = fmt.Sprintf("retry after %ds, once the circuit breaker's cool-down elapses", retryAfter)
+	return &CircuitOpenError{BaseError: err, NextAttemptTime: nextAttemptTime}
+}
+
+// NextSteps returns circuit-open-specific next steps
+func (e *CircuitOpenError) NextSteps() []string {
+	return []string{
+		"Wait until NextAttemptTime before retrying",
+		"Check the downstream dependency's health",
+		"Reduce request volume until the circuit closes",
+	}
+}
+
 // NetworkError represents network errors
 type NetworkError struct {
 	*BaseError
@@ -349,12 +519,25 @@ func (e *NetworkError) NextSteps() []string {
 
 // VerificationResult represents the result of asset verification
 type VerificationResult struct {
-	Verified        bool      `json:"verified"`
-	ManifestURL     *string   `json:"manifest_url,omitempty"`
-	TrustRoots      []string  `json:"trust_roots,omitempty"`
-	PolicyVersion   *string   `json:"policy_version,omitempty"`
+	Verified         bool     `json:"verified"`
+	ManifestURL      *string  `json:"manifest_url,omitempty"`
+	TrustRoots       []string `json:"trust_roots,omitempty"`
+	PolicyVersion    *string  `json:"policy_version,omitempty"`
 	VerificationTime *string  `json:"verification_time,omitempty"`
-	Cached          bool      `json:"cached"`
+	Cached           bool     `json:"cached"`
+	// Keyless is set when the verified manifest was signed with a
+	// Fulcio-like short-lived certificate instead of a long-lived key.
+	Keyless *KeylessVerification `json:"keyless,omitempty"`
+}
+
+// KeylessVerification carries the workload-identity binding the server
+// checked for a keyless-signed manifest: the cert subject/issuer, and
+// whether its Rekor-style transparency log inclusion proof verified.
+type KeylessVerification struct {
+	Identity     string `json:"identity"`
+	Issuer       string `json:"issuer"`
+	TLogIndex    *int64 `json:"tlog_index,omitempty"`
+	TLogVerified bool   `json:"tlog_verified"`
 }
 
 // VerifyAssetRequest represents a request to verify an asset
@@ -367,14 +550,48 @@ type VerifyAssetRequest struct {
 	CachedETag            *string  `json:"cached_etag,omitempty"`
 	CachedCertThumbprints []string `json:"cached_cert_thumbprints,omitempty"`
 	EnableDelta           bool     `json:"enable_delta"`
+	// ExpectedIdentity/ExpectedIssuer/RequireTLog request keyless-signature
+	// verification: the server binds the manifest's certificate subject to
+	// ExpectedIdentity, checks it was issued by ExpectedIssuer, and, if
+	// RequireTLog is set, verifies the Rekor-style transparency log
+	// inclusion proof before reporting Verified.
+	ExpectedIdentity *string `json:"expected_identity,omitempty"`
+	ExpectedIssuer   *string `json:"expected_issuer,omitempty"`
+	RequireTLog      bool    `json:"require_tlog,omitempty"`
+}
+
+// KeylessOptions configures keyless (Sigstore/Fulcio-style) signing for
+// SignFolder/SignAsset: an ephemeral key is generated in-process, an OIDC ID
+// token authenticates its owner, and a short-lived certificate binds the two
+// instead of requiring a pre-provisioned ProfileID tied to a long-lived key.
+type KeylessOptions struct {
+	OIDCIssuer       string
+	OIDCClientID     string
+	OIDCClientSecret string
+	// OIDCRedirectURL, when set, selects the browser-based OIDC flow over
+	// the device-code flow. See oidcflow.Config.
+	OIDCRedirectURL string
+	// Identity overrides the certificate's subject; defaults to the ID
+	// token's "sub" claim.
+	Identity string
+}
+
+// KeylessVerifyOptions requests keyless-signature verification from
+// VerifyAsset: the server binds the signing certificate's subject to
+// ExpectedIdentity and issuer to ExpectedIssuer, and, if RequireTLog is set,
+// verifies transparency log inclusion before reporting the asset verified.
+type KeylessVerifyOptions struct {
+	ExpectedIdentity string
+	ExpectedIssuer   string
+	RequireTLog      bool
 }
 
 // VerifyAssetResponse represents the response from asset verification
 type VerifyAssetResponse struct {
-	Success   bool              `json:"success"`
+	Success   bool               `json:"success"`
 	Data      VerificationResult `json:"data"`
-	RequestID string            `json:"request_id"`
-	Timestamp time.Time         `json:"timestamp"`
+	RequestID string             `json:"request_id"`
+	Timestamp time.Time          `json:"timestamp"`
 }
 
 // AssetVerificationResult represents a single asset verification result
@@ -387,19 +604,20 @@ type AssetVerificationResult struct {
 
 // VerifyPageRequest represents a request to verify a page
 type VerifyPageRequest struct {
-	PageURL     string `json:"page_url"`
-	FollowLinks bool   `json:"follow_links"`
-	MaxDepth    int    `json:"max_depth"`
-	PolicyID    string `json:"policy_id"`
-	Timeout     *int   `json:"timeout,omitempty"`
+	PageURL     string  `json:"page_url"`
+	FollowLinks bool    `json:"follow_links"`
+	MaxDepth    int     `json:"max_depth"`
+	PolicyID    string  `json:"policy_id"`
+	Timeout     *int    `json:"timeout,omitempty"`
+	CallbackURL *string `json:"callback_url,omitempty"`
 }
 
 // VerifyPageResponse represents the response from page verification
 type VerifyPageResponse struct {
-	Success   bool                `json:"success"`
+	Success   bool                   `json:"success"`
 	Data      map[string]interface{} `json:"data"`
-	RequestID string              `json:"request_id"`
-	Timestamp time.Time           `json:"timestamp"`
+	RequestID string                 `json:"request_id"`
+	Timestamp time.Time              `json:"timestamp"`
 }
 
 // AssetReference represents a reference to an asset
@@ -410,34 +628,34 @@ type AssetReference struct {
 
 // BatchVerifyRequest represents a request to verify multiple assets
 type BatchVerifyRequest struct {
-	Assets         []AssetReference `json:"assets"`
-	PolicyID       string           `json:"policy_id"`
-	Parallel       bool             `json:"parallel"`
+	Assets          []AssetReference `json:"assets"`
+	PolicyID        string           `json:"policy_id"`
+	Parallel        bool             `json:"parallel"`
 	TimeoutPerAsset *int             `json:"timeout_per_asset,omitempty"`
 }
 
 // BatchVerifyResponse represents the response from batch verification
 type BatchVerifyResponse struct {
-	Success   bool                `json:"success"`
+	Success   bool                   `json:"success"`
 	Data      map[string]interface{} `json:"data"`
-	RequestID string              `json:"request_id"`
-	Timestamp time.Time           `json:"timestamp"`
+	RequestID string                 `json:"request_id"`
+	Timestamp time.Time              `json:"timestamp"`
 }
 
 // InjectLinkRequest represents a request to inject links
 type InjectLinkRequest struct {
-	HTML        string `json:"html"`
-	ManifestURL string `json:"manifest_url"`
-	Strategy    string `json:"strategy"`
+	HTML        string  `json:"html"`
+	ManifestURL string  `json:"manifest_url"`
+	Strategy    string  `json:"strategy"`
 	Selector    *string `json:"selector,omitempty"`
 }
 
 // InjectLinkResponse represents the response from link injection
 type InjectLinkResponse struct {
-	Success   bool                `json:"success"`
+	Success   bool                   `json:"success"`
 	Data      map[string]interface{} `json:"data"`
-	RequestID string              `json:"request_id"`
-	Timestamp time.Time           `json:"timestamp"`
+	RequestID string                 `json:"request_id"`
+	Timestamp time.Time              `json:"timestamp"`
 }
 
 // SignFolderRequest represents a request to sign a folder
@@ -448,131 +666,289 @@ type SignFolderRequest struct {
 	Recursive      bool     `json:"recursive"`
 	FilePatterns   []string `json:"file_patterns,omitempty"`
 	IdempotencyKey string   `json:"idempotency_key"`
+	CallbackURL    *string  `json:"callback_url,omitempty"`
+	Files          []string `json:"files,omitempty"`
+	// KeylessIDToken/KeylessCertificateChain/KeylessPublicKey carry a
+	// keyless signing request's OIDC proof and Fulcio-issued short-lived
+	// certificate, set when SignFolderOptions.Keyless is non-nil.
+	KeylessIDToken          string   `json:"keyless_id_token,omitempty"`
+	KeylessCertificateChain []string `json:"keyless_certificate_chain,omitempty"`
+	KeylessPublicKey        string   `json:"keyless_public_key,omitempty"`
+	// Attestations carries SignFolderOptions.Attestations with each spec's
+	// PredicatePath already resolved to PredicateInline - the server walks
+	// the folder and has no access to the caller's local filesystem, so it
+	// needs the predicate content up front rather than a path.
+	Attestations []attestationSpecWire `json:"attestations,omitempty"`
 }
 
 // SignFolderResponse represents the response from folder signing
 type SignFolderResponse struct {
-	Success   bool                `json:"success"`
+	Success   bool                   `json:"success"`
 	Data      map[string]interface{} `json:"data"`
-	RequestID string              `json:"request_id"`
-	Timestamp time.Time           `json:"timestamp"`
+	RequestID string                 `json:"request_id"`
+	Timestamp time.Time              `json:"timestamp"`
 }
 
-// ManifestRequest represents a request to store a manifest
-type ManifestRequest struct {
-	Content     *string               `json:"content,omitempty"`
-	ContentType string                `json:"content_type"`
-	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+// SignAssetRequest represents a request to sign a single asset, the
+// single-file counterpart to SignFolderRequest.
+type SignAssetRequest struct {
+	AssetPath               string   `json:"asset_path"`
+	ProfileID               string   `json:"profile_id"`
+	TSA                     bool     `json:"tsa"`
+	IdempotencyKey          string   `json:"idempotency_key"`
+	CallbackURL             *string  `json:"callback_url,omitempty"`
+	KeylessIDToken          string   `json:"keyless_id_token,omitempty"`
+	KeylessCertificateChain []string `json:"keyless_certificate_chain,omitempty"`
+	KeylessPublicKey        string   `json:"keyless_public_key,omitempty"`
 }
 
-// ManifestResponse represents the response from manifest operations
-type ManifestResponse struct {
-	Success   bool                `json:"success"`
+// ============================================================================
+// In-toto / SLSA Attestations
+// ============================================================================
+
+// AttestationSpec describes one in-toto Statement to attach to a signed
+// asset: PredicateType identifies the kind of claim (e.g. SLSA provenance,
+// an SPDX or CycloneDX SBOM, or a custom predicate), and exactly one of
+// PredicatePath or PredicateInline supplies its content.
+type AttestationSpec struct {
+	PredicateType string
+	// PredicatePath reads the predicate JSON from disk at request time.
+	PredicatePath string
+	// PredicateInline supplies the predicate JSON directly, taking
+	// precedence over PredicatePath if both are set.
+	PredicateInline json.RawMessage
+}
+
+// attestationSpecWire is an AttestationSpec with its predicate already
+// resolved to inline content, the form sent over the wire (PredicatePath is
+// meaningless once it leaves the caller's filesystem).
+type attestationSpecWire struct {
+	PredicateType string          `json:"predicate_type"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// AttestationSignature is one DSSE envelope signature, following the DSSE
+// spec's {keyid, sig} shape. KeyID is omitted for keyless signatures, whose
+// identity is instead carried by the accompanying certificate chain.
+type AttestationSignature struct {
+	Sig   string `json:"sig"`
+	KeyID string `json:"keyid,omitempty"`
+}
+
+// AttestationEnvelope is a DSSE envelope wrapping a base64-encoded in-toto
+// Statement, per https://github.com/secure-systems-lab/dsse.
+type AttestationEnvelope struct {
+	PayloadType string                 `json:"payloadType"`
+	Payload     string                 `json:"payload"`
+	Signatures  []AttestationSignature `json:"signatures"`
+}
+
+// PutAttestationRequest uploads an attestation envelope alongside a
+// manifest, the attestation counterpart to ManifestRequest.
+type PutAttestationRequest struct {
+	Envelope       AttestationEnvelope `json:"envelope"`
+	IdempotencyKey string              `json:"idempotency_key"`
+}
+
+// PutAttestationResponse represents the response from uploading an
+// attestation envelope.
+type PutAttestationResponse struct {
+	Success   bool                   `json:"success"`
 	Data      map[string]interface{} `json:"data"`
-	RequestID string              `json:"request_id"`
-	Timestamp time.Time           `json:"timestamp"`
+	RequestID string                 `json:"request_id"`
+	Timestamp time.Time              `json:"timestamp"`
 }
 
-// JobStatus represents the status of a job
-type JobStatus struct {
-	JobID              string                 `json:"job_id"`
-	Status             string                 `json:"status"`
-	Progress           *float64               `json:"progress,omitempty"`
-	Result             map[string]interface{} `json:"result,omitempty"`
-	Error              map[string]interface{} `json:"error,omitempty"`
-	CreatedAt          time.Time              `json:"created_at"`
-	UpdatedAt          time.Time              `json:"updated_at"`
-	EstimatedCompletion *time.Time             `json:"estimated_completion,omitempty"`
+// VerifyAttestationOptions filters which attestations Client.VerifyAttestation
+// checks: PredicateType narrows to one predicate kind (all types if empty),
+// and Policy names the server-side policy to evaluate the predicate against
+// (mirrors VerifyAssetOptions.PolicyID).
+type VerifyAttestationOptions struct {
+	PredicateType string
+	Policy        string
+}
+
+// AttestationSubject is the in-toto Statement subject an attestation covers.
+type AttestationSubject struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+}
+
+// AttestationPredicate is one verified attestation returned by
+// Client.VerifyAttestation: the subject it covers, its predicate type, and
+// the parsed predicate body.
+type AttestationPredicate struct {
+	Subject       AttestationSubject `json:"subject"`
+	PredicateType string             `json:"predicate_type"`
+	Predicate     json.RawMessage    `json:"predicate"`
+	Verified      bool               `json:"verified"`
+}
+
+// VerifyAttestationRequest represents a request to verify attestations
+// attached to a manifest.
+type VerifyAttestationRequest struct {
+	PredicateType string `json:"predicate_type,omitempty"`
+	Policy        string `json:"policy,omitempty"`
+}
+
+// VerifyAttestationResult is the decoded body of a VerifyAttestationResponse.
+type VerifyAttestationResult struct {
+	Verified   bool                   `json:"verified"`
+	Predicates []AttestationPredicate `json:"predicates,omitempty"`
+}
+
+// VerifyAttestationResponse represents the response from attestation
+// verification.
+type VerifyAttestationResponse struct {
+	Success   bool                    `json:"success"`
+	Data      VerifyAttestationResult `json:"data"`
+	RequestID string                  `json:"request_id"`
+	Timestamp time.Time               `json:"timestamp"`
+}
+
+// keylessCertificateRequest is the CSR + OIDC proof POSTed to the
+// Fulcio-like /keyless/certificate endpoint.
+type keylessCertificateRequest struct {
+	CSR     string `json:"csr"`
+	IDToken string `json:"id_token"`
+}
+
+// keylessCertificateResponse is the short-lived certificate chain Fulcio-
+// like endpoints issue in exchange for a verified CSR + ID token.
+type keylessCertificateResponse struct {
+	CertificateChain []string `json:"certificate_chain"`
+}
+
+// TLogEntryRequest submits a signed bundle's certificate chain to a
+// Rekor-style transparency log.
+type TLogEntryRequest struct {
+	CertificateChain []string `json:"certificate_chain"`
+	ManifestDigest   string   `json:"manifest_digest"`
+	Signature        string   `json:"signature,omitempty"`
+}
+
+// TLogEntryResponse is a transparency log's inclusion receipt.
+type TLogEntryResponse struct {
+	Success   bool                   `json:"success"`
+	Data      map[string]interface{} `json:"data"`
+	RequestID string                 `json:"request_id"`
+	Timestamp time.Time              `json:"timestamp"`
 }
 
 // ============================================================================
-// Circuit Breaker Implementation
+// ACME Profile Enrollment
 // ============================================================================
 
-// CircuitBreakerState represents the state of a circuit breaker
-type CircuitBreakerState struct {
-	State             string
-	FailureCount      int
-	LastFailureTime   time.Time
-	NextAttemptTime   time.Time
-}
-
-// CircuitBreaker prevents cascading failures
-type CircuitBreaker struct {
-	name              string
-	state             CircuitBreakerState
-	failureThreshold  int
-	recoveryTimeout   time.Duration
-	halfOpenMaxCalls  int
-	halfOpenCalls     int
-}
-
-// NewCircuitBreaker creates a new circuit breaker
-func NewCircuitBreaker(name string) *CircuitBreaker {
-	return &CircuitBreaker{
-		name:             name,
-		failureThreshold: 5,
-		recoveryTimeout:  60 * time.Second,
-		halfOpenMaxCalls: 3,
-		state: CircuitBreakerState{
-			State: "closed",
-		},
-	}
+// ACMEChallengeType names an ACME challenge type (RFC 8555 section 8), the
+// proof of control EnrollProfileOptions asks the CA to validate before
+// issuing a certificate.
+type ACMEChallengeType string
+
+const (
+	ChallengeHTTP01         ACMEChallengeType = "http-01"
+	ChallengeDNS01          ACMEChallengeType = "dns-01"
+	ChallengeDeviceAttest01 ACMEChallengeType = "device-attest-01"
+)
+
+// EnrollProfileOptions configures Client.EnrollProfile: provisioning a
+// signing profile's X.509 certificate from an ACME-compatible CA (e.g. a
+// step-ca deployment) instead of uploading a pre-issued one.
+type EnrollProfileOptions struct {
+	ACMEDirectoryURL string
+	Contact          []string
+	// KeyType selects the generated key's algorithm. Only "ecdsa-p256" (the
+	// default) is supported; any other value, "rsa-2048" included, makes
+	// EnrollProfile fail.
+	KeyType   string
+	Subject   string
+	Challenge ACMEChallengeType
+	// RenewBefore is how long before the certificate's NotAfter
+	// Client.StartProfileRenewal reruns the ACME order. Defaults to 30 days.
+	RenewBefore time.Duration
 }
 
-// Execute executes an operation with circuit breaker protection
-func (cb *CircuitBreaker) Execute(fn func() (interface{}, error)) (interface{}, error) {
-	if cb.state.State == "open" {
-		if time.Now().Before(cb.state.NextAttemptTime) {
-			return nil, NewNetworkError(fmt.Sprintf("Circuit breaker '%s' is open", cb.name))
-		}
-		cb.setState("half-open")
-		cb.halfOpenCalls = 0
-	}
+// ProfileStatus reports a ProfileManager-issued certificate's current state,
+// returned by Client.ProfileStatus.
+type ProfileStatus struct {
+	NotAfter    time.Time
+	Issuer      string
+	LastRenewal time.Time
+	// NextAttempt is when the certificate enters its RenewBefore window
+	// (NotAfter minus RenewBefore), or, after a failed ACME renewal, one hour
+	// past that failure. StartProfileRenewal's background goroutine checks
+	// every profile once an hour regardless of this value.
+	NextAttempt time.Time
+}
 
-	result, err := fn()
-	if err != nil {
-		cb.onFailure()
-		return nil, err
-	}
+// acmeOrderRequest starts an ACME order for Subject, the first step of
+// EnrollProfile and of each renewal.
+type acmeOrderRequest struct {
+	Contact []string `json:"contact,omitempty"`
+	Subject string   `json:"subject"`
+}
 
-	cb.onSuccess()
-	return result, nil
+// acmeChallenge is one challenge offered for an authorization; Challenge
+// picks which Type EnrollProfile responds to.
+type acmeChallenge struct {
+	Type  ACMEChallengeType `json:"type"`
+	Token string            `json:"token"`
+	URL   string            `json:"url"`
 }
 
-func (cb *CircuitBreaker) onSuccess() {
-	if cb.state.State == "half-open" {
-		cb.halfOpenCalls++
-		if cb.halfOpenCalls >= cb.halfOpenMaxCalls {
-			cb.setState("closed")
-		}
-	} else {
-		cb.setState("closed")
-	}
+// acmeOrderResponse is the directory's response to acmeOrderRequest: an
+// order to finalize once one of Challenges has been satisfied.
+type acmeOrderResponse struct {
+	OrderID    string          `json:"order_id"`
+	Challenges []acmeChallenge `json:"challenges"`
 }
 
-func (cb *CircuitBreaker) onFailure() {
-	cb.state.FailureCount++
-	cb.state.LastFailureTime = time.Now()
+// acmeChallengeResponseRequest tells the CA a challenge's proof of control
+// is ready to check, e.g. that the http-01 token is now being served.
+type acmeChallengeResponseRequest struct {
+	KeyAuthorization string `json:"key_authorization"`
+}
 
-	if cb.state.State == "half-open" {
-		cb.setState("open")
-	} else if cb.state.FailureCount >= cb.failureThreshold {
-		cb.setState("open")
-	}
+// acmeChallengeResponse reports whether the CA accepted a challenge
+// response.
+type acmeChallengeResponse struct {
+	Status string `json:"status"`
 }
 
-func (cb *CircuitBreaker) setState(newState string) {
-	cb.state.State = newState
-	if newState == "open" {
-		cb.state.NextAttemptTime = time.Now().Add(cb.recoveryTimeout)
-	} else if newState == "closed" {
-		cb.state.FailureCount = 0
-		cb.halfOpenCalls = 0
-	}
+// acmeFinalizeRequest submits the CSR for an authorized order.
+type acmeFinalizeRequest struct {
+	CSR string `json:"csr"`
+}
+
+// acmeFinalizeResponse is the issued certificate for a finalized order.
+type acmeFinalizeResponse struct {
+	CertificateChain []string  `json:"certificate_chain"`
+	NotAfter         time.Time `json:"not_after"`
+	Issuer           string    `json:"issuer"`
+}
+
+// ManifestRequest represents a request to store a manifest
+type ManifestRequest struct {
+	Content     *string                `json:"content,omitempty"`
+	ContentType string                 `json:"content_type"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// ManifestResponse represents the response from manifest operations
+type ManifestResponse struct {
+	Success   bool                   `json:"success"`
+	Data      map[string]interface{} `json:"data"`
+	RequestID string                 `json:"request_id"`
+	Timestamp time.Time              `json:"timestamp"`
 }
 
-// GetState returns the current circuit breaker state
-func (cb *CircuitBreaker) GetState() string {
-	return cb.state.State
+// JobStatus represents the status of a job
+type JobStatus struct {
+	JobID               string                 `json:"job_id"`
+	Status              string                 `json:"status"`
+	Progress            *float64               `json:"progress,omitempty"`
+	Result              map[string]interface{} `json:"result,omitempty"`
+	Error               map[string]interface{} `json:"error,omitempty"`
+	CreatedAt           time.Time              `json:"created_at"`
+	UpdatedAt           time.Time              `json:"updated_at"`
+	EstimatedCompletion *time.Time             `json:"estimated_completion,omitempty"`
 }